@@ -4,18 +4,66 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
 
 	"github.com/guevarez30/dockit/pretty"
 )
 
 func main() {
-	if len(os.Args) < 2 {
+	// Help never needs the config or a Docker connection
+	if len(os.Args) == 2 && isHelpFlag(os.Args[1]) {
 		printUsage()
 		os.Exit(0)
 	}
 
+	if err := pretty.LoadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	// Global flags come before the command, like docker --context
+	args := os.Args[1:]
+	rest := parseGlobalFlags(args)
+	globals := slices.Clone(args[:len(args)-len(rest)])
+
+	if len(rest) == 0 {
+		// Run the configured default command, or reopen the last view
+		if defaultCommand := pretty.DefaultCommand(); defaultCommand != "" {
+			rest = []string{defaultCommand}
+		} else if view := pretty.LastView(); len(view) > 0 {
+			// The view reopens on the daemon it was left on, unless a
+			// --context or --host given now says otherwise
+			rest = parseGlobalFlags(view)
+			if slices.ContainsFunc(globals, func(arg string) bool { return arg != "--trace" }) {
+				pretty.SetContext("")
+				pretty.SetHost("")
+				parseGlobalFlags(globals)
+			} else {
+				globals = append(slices.Clone(view[:len(view)-len(rest)]), globals...)
+			}
+		} else {
+			printUsage()
+			os.Exit(0)
+		}
+	}
+	os.Args = append(os.Args[:1], rest...)
+
+	// The view is saved with the flags that pick its daemon, but not --trace
+	globals = slices.DeleteFunc(globals, func(arg string) bool { return arg == "--trace" })
+	pretty.SetView(append(globals, rest...))
+
 	command := os.Args[1]
 
+	// dockit help [COMMAND] is dockit's usage, or COMMAND --help
+	if isHelpFlag(command) {
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(0)
+		}
+		command = os.Args[2]
+		os.Args = []string{os.Args[0], command, "--help"}
+	}
+
 	// Check if we have a pretty printer for this command
 	switch command {
 	case "ps":
@@ -24,24 +72,254 @@ func main() {
 	case "images":
 		// Pretty print docker images
 		pretty.PrintImages(os.Args[2:])
+	case "volumes":
+		// Pretty print docker volume ls, or volume usage by class
+		if len(os.Args) > 2 && os.Args[2] == "df" {
+			pretty.PrintVolumeUsage(os.Args[3:])
+		} else {
+			pretty.PrintVolumes(os.Args[2:])
+		}
+	case "networks":
+		// Pretty print docker network ls
+		pretty.PrintNetworks(os.Args[2:])
 	case "logs":
 		// Pretty print docker logs with search
 		pretty.PrintLogs(os.Args[2:])
+	case "pull":
+		// Pull with per-layer progress
+		pretty.PullImage(os.Args[2:])
+	case "login":
+		// Log in to a registry, saving credentials like docker login
+		pretty.RunLogin(os.Args[2:])
+	case "logout":
+		// Remove a registry's saved credentials
+		pretty.RunLogout(os.Args[2:])
+	case "save":
+		// Save images to a tarball with a progress bar
+		pretty.SaveImages(os.Args[2:])
+	case "load":
+		// Load images from a tarball with a progress bar
+		pretty.LoadImages(os.Args[2:])
+	case "verify":
+		// Check saved tarballs against their checksum manifest
+		pretty.VerifyManifest(os.Args[2:])
+	case "query":
+		// Query resources with jq-like filters for scripting
+		pretty.RunQuery(os.Args[2:])
+	case "config":
+		// Manage the dockit config file, pass through swarm config subcommands
+		if len(os.Args) > 2 && (os.Args[2] == "init" || os.Args[2] == "path") {
+			pretty.RunConfig(os.Args[2:])
+		} else {
+			runDockerCommand(os.Args[1:])
+		}
+	case "prune":
+		// Interactive cleanup with reclaimable space per category
+		pretty.PrintPrune(os.Args[2:])
+	case "doctor":
+		// Check the Docker environment for common problems
+		pretty.PrintDoctor(os.Args[2:])
+	case "health":
+		// Show healthcheck status and recent probe output
+		pretty.PrintHealth(os.Args[2:])
+	case "healthgate":
+		// Wait for a project's containers to be running and healthy
+		pretty.HealthGate(os.Args[2:])
+	case "inspect":
+		// Sectioned summary of any resource; --format goes to docker inspect
+		if hasFormatFlag(os.Args[2:]) {
+			runDockerCommand(os.Args[1:])
+		} else {
+			pretty.Inspect(os.Args[2:])
+		}
+	case "lint":
+		// Check a Dockerfile for common issues
+		pretty.LintDockerfile(os.Args[2:])
+	case "compare":
+		// Show two containers' configuration side by side
+		pretty.CompareContainers(os.Args[2:])
+	case "label":
+		// Add or remove a container's labels by recreating it
+		pretty.LabelContainer(os.Args[2:])
+	case "recreate":
+		// Recreate containers on the image their tag currently points to
+		pretty.RecreateContainer(os.Args[2:])
+	case "events":
+		// Live events feed, pass through when a --format is given for scripting
+		if hasFormatFlag(os.Args[2:]) {
+			runDockerCommand(os.Args[1:])
+		} else {
+			pretty.PrintEvents(os.Args[2:])
+		}
+	case "cp":
+		// Copy files between a container and the host with a progress bar
+		pretty.CopyFiles(os.Args[2:])
+	case "files":
+		// Browse a container's filesystem
+		pretty.PrintFiles(os.Args[2:])
+	case "dashboard":
+		// Resource overview: daemon, CPU and memory, disk, and warnings
+		pretty.PrintDashboard(os.Args[2:])
+	case "quick":
+		// One screen of stats, logs, and actions for a single container
+		pretty.PrintQuick(os.Args[2:])
+	case "watch":
+		// Read-only live view of one container for deploys: health, restarts, graphs, and logs
+		pretty.PrintWatch(os.Args[2:])
+	case "ports":
+		// Map of published host ports across containers, with the free gaps
+		pretty.PrintPorts(os.Args[2:])
+	case "df":
+		// Disk usage by type with what could be reclaimed
+		pretty.PrintSystemDiskUsage(os.Args[2:])
+	case "info":
+		// Daemon info in sections, pass through when a --format is given for scripting
+		if hasFormatFlag(os.Args[2:]) {
+			runDockerCommand(os.Args[1:])
+		} else {
+			pretty.PrintSystemInfo(os.Args[2:])
+		}
+	case "du":
+		// Rank a container's mounts and writable layer by disk usage
+		pretty.PrintDiskUsage(os.Args[2:])
+	case "pins":
+		// Resolve the digests behind mutable image tags
+		pretty.PrintPins(os.Args[2:])
+	case "profile":
+		// Start or stop configured groups of containers in order
+		pretty.RunProfile(os.Args[2:])
+	case "meta":
+		// Export, import, or sync shared protect patterns, profiles, and registries
+		pretty.RunMetadata(os.Args[2:])
+	case "sh":
+		// Open a shell in the running container matching a partial name
+		pretty.OpenShell(os.Args[2:])
+	case "exec":
+		// Run docker exec, capturing output when --output is given
+		pretty.RunExec(os.Args[2:])
+	case "run":
+		// Guided docker run form with --wizard, pass through otherwise
+		if len(os.Args) > 2 && os.Args[2] == "--wizard" {
+			pretty.RunWizard(os.Args[3:])
+		} else {
+			runDockerCommand(os.Args[1:])
+		}
+	case "start":
+		// Measure time to running and healthy with --time, start a project or
+		// label's containers with --project or --label, pass through otherwise
+		switch {
+		case slices.Contains(os.Args[2:], "--time"):
+			pretty.TimeStart(os.Args[2:])
+		case hasGroupFlag(os.Args[2:]):
+			pretty.StartGroup(os.Args[2:])
+		default:
+			runDockerCommand(os.Args[1:])
+		}
+	case "stop":
+		// Stop every running container, or a project or label's, with a live
+		// list with --all, --project, or --label, pass through otherwise
+		switch {
+		case slices.Contains(os.Args[2:], "--all"):
+			pretty.StopAll(os.Args[2:])
+		case hasGroupFlag(os.Args[2:]):
+			pretty.StopGroup(os.Args[2:])
+		default:
+			runDockerCommand(os.Args[1:])
+		}
+	case "sessions":
+		// Detachable exec sessions kept alive in tmux
+		pretty.RunSessions(os.Args[2:])
+	case "volume":
+		// Pretty print volume details, guided creation, pass through other volume subcommands
+		if len(os.Args) > 2 && os.Args[2] == "inspect" {
+			pretty.PrintVolumeDetails(os.Args[3:])
+		} else if len(os.Args) > 2 && os.Args[2] == "create" {
+			pretty.CreateVolume(os.Args[3:])
+		} else {
+			runDockerCommand(os.Args[1:])
+		}
+	case "image":
+		// Copy files out of an image or diff two images' layers, pass through
+		// other image subcommands
+		if len(os.Args) > 2 && os.Args[2] == "unpack" {
+			pretty.UnpackImage(os.Args[3:])
+		} else if len(os.Args) > 2 && os.Args[2] == "diff" {
+			pretty.DiffImages(os.Args[3:])
+		} else {
+			runDockerCommand(os.Args[1:])
+		}
+	case "network":
+		// Guided network creation, pass through other network subcommands
+		if len(os.Args) > 2 && os.Args[2] == "create" {
+			pretty.CreateNetwork(os.Args[3:])
+		} else {
+			runDockerCommand(os.Args[1:])
+		}
 	default:
 		// Pass through to docker command for everything else
 		runDockerCommand(os.Args[1:])
 	}
+
+	pretty.PrintTrace()
 }
 
 func printUsage() {
 	fmt.Println("Dockit - A prettier wrapper for Docker CLI")
 	fmt.Println()
-	fmt.Println("Usage: dockit [command] [options]")
+	fmt.Println("Usage: dockit [--context NAME | --host HOST] [--trace] [command] [options]")
+	fmt.Println()
+	fmt.Println("With no command, dockit runs defaults.default_command from the config, or")
+	fmt.Println("reopens the last interactive view quit, on the same context or host; it only")
+	fmt.Println("prints this usage before any view has been quit. Run dockit help to see it.")
 	fmt.Println()
 	fmt.Println("Pretty Commands (enhanced output):")
 	fmt.Println("  ps              List containers with pretty formatting")
 	fmt.Println("  images          List images with pretty formatting")
+	fmt.Println("  volumes         List volumes with driver, mountpoint, and in-use status")
+	fmt.Println("  networks        List networks with driver, subnets, and in-use status")
 	fmt.Println("  logs            View container logs with search and highlighting")
+	fmt.Println("  events          Live feed of container/image/volume/network events")
+	fmt.Println("  volume inspect  Show volume details, size, and attached containers")
+	fmt.Println("  volumes df      Sizes of in-use, stopped-only, and orphaned volumes, with cleanup by class")
+	fmt.Println("  pull            Pull an image with per-layer progress")
+	fmt.Println("  login/logout    Save or remove registry credentials (config.json or credential helper)")
+	fmt.Println("  save/load       Export images to a tarball or import them, with progress")
+	fmt.Println("  verify          Check saved and exported tarballs against their checksum manifest")
+	fmt.Println("  prune           Pick what to clean up and see the space reclaimed")
+	fmt.Println("  doctor          Check the Docker environment for common problems")
+	fmt.Println("  query           Query containers/images/volumes/networks with jq-like filters")
+	fmt.Println("  config init     Write a commented config file template")
+	fmt.Println("  run --wizard    Fill in a form for docker run, then create and start the container")
+	fmt.Println("  start --time    Start containers and time how long they take to be running and healthy")
+	fmt.Println("  stop --all      Stop every running container, showing who ignores the stop signal")
+	fmt.Println("  stop --project  Stop a compose project's containers (or --label KEY=VALUE), a few at a time")
+	fmt.Println("  start --project Start a compose project's containers (or --label KEY=VALUE), a few at a time")
+	fmt.Println("  sessions        Start, resume, and kill detachable exec sessions (needs tmux)")
+	fmt.Println("  sh              Open bash, ash, or sh in the running container matching part of a name")
+	fmt.Println("  exec --output   Run docker exec and save its output to a file")
+	fmt.Println("  image unpack    Copy files or globs out of an image without running it")
+	fmt.Println("  image diff      Show the layers two images share and the ones each adds")
+	fmt.Println("  volume create   Create a volume, prompting for name, driver, options, and labels")
+	fmt.Println("  network create  Create a network with driver-specific checks, or a wizard with no arguments")
+	fmt.Println("  health          Show a container's healthcheck status and recent probes")
+	fmt.Println("  healthgate      Wait until a project's containers are healthy, failing with the ones that aren't")
+	fmt.Println("  recreate        Recreate containers on the current image for their tag")
+	fmt.Println("  inspect         Summarize any container, image, volume, or network in sections; --raw for JSON")
+	fmt.Println("  lint            Check a Dockerfile for a missing USER, package caches, latest tags, and too many layers")
+	fmt.Println("  compare         Compare two containers' image, env, mounts, ports, and limits side by side")
+	fmt.Println("  label           Add or remove a container's labels (recreates it after confirming)")
+	fmt.Println("  cp              Copy files between a container and the host with progress")
+	fmt.Println("  files           Browse a container's filesystem, view and download files")
+	fmt.Println("  dashboard       Overview of the daemon, CPU and memory, disk usage, and unhealthy containers")
+	fmt.Println("  quick           Live stats, log tail, processes, and restart/stop/exec keys for one container")
+	fmt.Println("  watch           Follow one container's health, restarts, CPU and memory graphs, and logs")
+	fmt.Println("  ports           Map every published host port to its container, with free ranges between")
+	fmt.Println("  df              Show the space images, containers, volumes, and build cache use, and what is reclaimable")
+	fmt.Println("  info            Show the daemon's version, storage and cgroup drivers, runtimes, registries, and warnings")
+	fmt.Println("  du              Rank a container's mounts and writable layer by size")
+	fmt.Println("  pins            Show the digest behind each container's image tag, write a pin file")
+	fmt.Println("  profile         Start/stop configured container groups in order, waiting for health")
+	fmt.Println("  meta            Export, import, or git-sync protect patterns, profiles, and trusted registries")
 	fmt.Println()
 	fmt.Println("All other commands are passed directly to Docker:")
 	fmt.Println("  dockit run [...]         -> docker run [...]")
@@ -54,11 +332,71 @@ func printUsage() {
 	fmt.Println("  dockit ps -a                 # All containers (pretty)")
 	fmt.Println("  dockit images                # Pretty image list")
 	fmt.Println("  dockit logs --search error myapp  # View logs with search")
+	fmt.Println("  dockit exec --output out.txt web ls /app  # Save exec output")
+	fmt.Println("  dockit --context prod ps     # Containers on another daemon")
+	fmt.Println("  dockit --trace ps            # Show the Docker API calls and their timings")
 	fmt.Println("  dockit run -d nginx          # Standard docker run")
 }
 
+// parseGlobalFlags applies --context, --host, and --trace and returns the remaining arguments
+func parseGlobalFlags(args []string) []string {
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case (arg == "--context" || arg == "-c") && len(args) > 1:
+			pretty.SetContext(args[1])
+			args = args[2:]
+		case strings.HasPrefix(arg, "--context="):
+			pretty.SetContext(strings.TrimPrefix(arg, "--context="))
+			args = args[1:]
+		case (arg == "--host" || arg == "-H") && len(args) > 1:
+			pretty.SetHost(args[1])
+			args = args[2:]
+		case strings.HasPrefix(arg, "--host="):
+			pretty.SetHost(strings.TrimPrefix(arg, "--host="))
+			args = args[1:]
+		case arg == "--trace":
+			pretty.EnableTrace()
+			args = args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// isHelpFlag reports whether arg asks for dockit's usage
+func isHelpFlag(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "--help"
+}
+
+// hasFormatFlag reports whether args ask docker for formatted output
+func hasFormatFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--format" || strings.HasPrefix(arg, "--format=") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGroupFlag reports whether args pick containers by compose project or
+// label, which docker start and stop don't do
+func hasGroupFlag(args []string) bool {
+	for _, arg := range args {
+		name, _, _ := strings.Cut(arg, "=")
+		if name == "--project" || name == "--label" {
+			return true
+		}
+	}
+	return false
+}
+
 func runDockerCommand(args []string) {
-	cmd := exec.Command("docker", args...)
+	pretty.WarnUnattachedStdin(args)
+
+	// stdin is inherited as-is so piped input and its EOF reach docker untouched
+	cmd := pretty.DockerCommand(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -0,0 +1,106 @@
+package pretty
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/events"
+)
+
+var paletteOnce sync.Once
+
+// preparePalette applies the background palette the first time something is
+// drawn with the TUI styles; explicit theme colors still win over it
+func preparePalette() {
+	paletteOnce.Do(func() {
+		applyBackground()
+		applyTheme()
+	})
+}
+
+// newProgram starts a TUI with the palette for the terminal's background
+func newProgram(model tea.Model, opts ...tea.ProgramOption) *tea.Program {
+	preparePalette()
+	return tea.NewProgram(model, opts...)
+}
+
+// applyBackground picks the dark or light TUI palette from DOCKIT_THEME or
+// the config ("auto", "dark", or "light"), detecting the terminal for "auto"
+func applyBackground() {
+	mode := config.Theme.Background
+	if value := os.Getenv("DOCKIT_THEME"); value != "" {
+		mode = value
+	}
+
+	var dark bool
+	switch strings.ToLower(mode) {
+	case "dark":
+		dark = true
+	case "light":
+		dark = false
+	default:
+		dark = darkBackground()
+	}
+
+	lipgloss.SetHasDarkBackground(dark)
+	if !dark {
+		applyLightPalette()
+	}
+}
+
+// darkBackground guesses the terminal background from COLORFGBG, then by
+// asking the terminal itself (OSC 11)
+func darkBackground() bool {
+	// COLORFGBG is "fg;bg" (rxvt adds a middle field); bg is an ANSI color
+	// index, where white (7) and the bright colors except gray (8) are light
+	if value := os.Getenv("COLORFGBG"); value != "" {
+		fields := strings.Split(value, ";")
+		if bg, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			return bg != 7 && (bg < 9 || bg > 15)
+		}
+	}
+
+	// Only query a real terminal, so piped output never waits on a reply
+	if !isTerminal(os.Stdout) {
+		return true
+	}
+	return lipgloss.HasDarkBackground()
+}
+
+// applyLightPalette swaps the bright colors tuned for dark terminals for
+// darker shades that stay readable on a light background
+func applyLightPalette() {
+	accent := lipgloss.Color("#005f87")
+	success := lipgloss.Color("#008700")
+	muted := lipgloss.Color("#585858")
+
+	titleStyle = titleStyle.Foreground(accent)
+	cursorStyle = cursorStyle.Foreground(accent)
+	activeTabStyle = activeTabStyle.Background(accent).Foreground(lipgloss.Color("#ffffff"))
+	tabStyle = tabStyle.Foreground(muted)
+	helpStyle = helpStyle.Foreground(muted)
+	selectedStyle = selectedStyle.Foreground(success)
+	progressFillStyle = progressFillStyle.Foreground(accent)
+	progressDoneStyle = progressDoneStyle.Foreground(success)
+	errorStyle = errorStyle.Foreground(lipgloss.Color("#d70000"))
+	detachedStyle = detachedStyle.Foreground(lipgloss.Color("#af8700"))
+
+	dirStyle = dirStyle.Foreground(lipgloss.Color("#0000d7"))
+	linkStyle = linkStyle.Foreground(lipgloss.Color("#008787"))
+	commentStyle = commentStyle.Foreground(muted)
+	stringStyle = stringStyle.Foreground(lipgloss.Color("#5f8700"))
+	keyStyle = keyStyle.Foreground(accent)
+
+	eventTypeStyles[events.ContainerEventType] = eventTypeStyles[events.ContainerEventType].Foreground(accent)
+	eventTypeStyles[events.VolumeEventType] = eventTypeStyles[events.VolumeEventType].Foreground(lipgloss.Color("#af5f00"))
+	for _, action := range []events.Action{events.ActionStart, events.ActionCreate, events.ActionPull} {
+		eventActionStyles[action] = eventActionStyles[action].Foreground(success)
+	}
+	levelStyles[levelWarn] = levelStyles[levelWarn].Foreground(lipgloss.Color("#af5f00"))
+	levelStyles[levelInfo] = levelStyles[levelInfo].Foreground(lipgloss.Color("#005faf"))
+	levelStyles[levelDebug] = levelStyles[levelDebug].Foreground(muted)
+}
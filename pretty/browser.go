@@ -0,0 +1,66 @@
+package pretty
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"sort"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// browserHost is the host name published ports are reached on: the daemon's
+// address for a remote tcp:// daemon, localhost otherwise
+func browserHost(daemonHost string) string {
+	u, err := url.Parse(daemonHost)
+	if err != nil || u.Scheme != "tcp" || u.Hostname() == "" {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// portURLs returns an http:// URL for each published TCP port, once per host
+// port even when Docker binds it on both IPv4 and IPv6
+func portURLs(ports []container.Port, host string) []string {
+	seen := map[uint16]bool{}
+	var published []container.Port
+	for _, port := range ports {
+		if port.Type != "tcp" || port.PublicPort == 0 || seen[port.PublicPort] {
+			continue
+		}
+		seen[port.PublicPort] = true
+		published = append(published, port)
+	}
+	sort.Slice(published, func(i, j int) bool { return published[i].PublicPort < published[j].PublicPort })
+
+	var urls []string
+	for _, port := range published {
+		// Ports bound to one specific address are only reachable there
+		target := host
+		if ip := net.ParseIP(port.IP); ip != nil && !ip.IsUnspecified() && !ip.IsLoopback() {
+			target = port.IP
+		}
+		urls = append(urls, fmt.Sprintf("http://%s/", net.JoinHostPort(target, fmt.Sprint(port.PublicPort))))
+	}
+	return urls
+}
+
+// openBrowser opens target in the default browser without waiting for it
+func openBrowser(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
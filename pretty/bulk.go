@@ -0,0 +1,380 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// bulkItem is a row that can be marked in a bulk selection list
+type bulkItem struct {
+	id        string
+	name      string
+	detail    string
+	search    string // text the / filter matches: name, image, labels
+	copyText  string // what y copies: the ID, or the name for images and volumes
+	listed    int    // position in the daemon's listing, the order with no sort
+	image     string
+	state     string
+	driver    string
+	created   time.Time
+	size      int64
+	ports     string
+	urls      []string // published ports o can open in a browser
+	project   string   // compose project, for containers
+	protected bool
+	selected  bool
+}
+
+// bulkAction is an operation applied to every marked row
+type bulkAction struct {
+	key         string
+	verb        string
+	done        string
+	destructive bool // skipped for protected rows
+	run         func(ctx context.Context, cli *client.Client, id string) error
+
+	// runPair, when set, takes exactly two marked rows together instead
+	runPair func(ctx context.Context, cli *client.Client, a, b string) error
+
+	// runAll, when set, takes every marked row in one call instead
+	runAll func(ctx context.Context, cli *client.Client, items []bulkItem) error
+
+	// runOrdered is runAll for actions whose order can matter, like stops;
+	// inOrder is set when the rows were reordered in the review
+	runOrdered func(ctx context.Context, cli *client.Client, items []bulkItem, inOrder bool) error
+
+	// noRows actions, like creating a new resource, need no marked rows
+	noRows bool
+}
+
+var containerActions = []bulkAction{
+	{key: "s", verb: "start", done: "started", run: func(ctx context.Context, cli *client.Client, id string) error {
+		return cli.ContainerStart(ctx, id, container.StartOptions{})
+	}},
+	{key: "t", verb: "stop", done: "stopped", runOrdered: stopSelectedContainers},
+	{key: "T", verb: "stop with timeout", done: "stopped", runOrdered: stopSelectedWithTimeout},
+	{key: "r", verb: "restart", done: "restarted", run: func(ctx context.Context, cli *client.Client, id string) error {
+		info, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return err
+		}
+		return cli.ContainerRestart(ctx, id, container.StopOptions{Timeout: stopTimeoutFor(info.Config)})
+	}},
+	{key: "P", verb: "pause/unpause", done: "pause toggled", run: togglePause},
+	{key: "d", verb: "remove", done: "removed", destructive: true, run: func(ctx context.Context, cli *client.Client, id string) error {
+		return cli.ContainerRemove(ctx, id, container.RemoveOptions{})
+	}},
+	{key: "C", verb: "commit to image", runAll: commitSelectedContainers},
+	{key: "x", verb: "export to tar", runAll: exportSelectedContainers},
+	{key: "c", verb: "compare", runPair: func(ctx context.Context, cli *client.Client, a, b string) error {
+		return printComparison(ctx, cli, a, b, false)
+	}},
+}
+
+var imageActions = []bulkAction{
+	{key: "p", verb: "pull", done: "pulled", run: pullImageTag},
+	{key: "S", verb: "save to tar", runAll: saveSelectedImages},
+	{key: "D", verb: "remove unused", noRows: true, runAll: removeUnusedImages},
+	{key: "c", verb: "compare layers", runPair: printLayerDiff},
+	{key: "d", verb: "remove", done: "removed", destructive: true, run: func(ctx context.Context, cli *client.Client, id string) error {
+		_, err := cli.ImageRemove(ctx, id, image.RemoveOptions{PruneChildren: true})
+		return err
+	}},
+}
+
+var volumeActions = []bulkAction{
+	{key: "c", verb: "create", noRows: true, runAll: func(ctx context.Context, cli *client.Client, _ []bulkItem) error {
+		return createVolumeInteractive(ctx, cli, volumeCreateOptions{driver: "local"})
+	}},
+	{key: "d", verb: "remove", done: "removed", destructive: true, run: func(ctx context.Context, cli *client.Client, id string) error {
+		return cli.VolumeRemove(ctx, id, false)
+	}},
+}
+
+var networkActions = []bulkAction{
+	{key: "c", verb: "create", noRows: true, runAll: func(ctx context.Context, cli *client.Client, _ []bulkItem) error {
+		return createNetworkInteractive(ctx, cli, newNetworkCreateOptions())
+	}},
+	{key: "d", verb: "remove", done: "removed", destructive: true, run: func(ctx context.Context, cli *client.Client, id string) error {
+		return cli.NetworkRemove(ctx, id)
+	}},
+}
+
+// togglePause unpauses a paused container and pauses any other
+func togglePause(ctx context.Context, cli *client.Client, id string) error {
+	info, err := cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+	if info.State != nil && info.State.Paused {
+		return cli.ContainerUnpause(ctx, id)
+	}
+	return cli.ContainerPause(ctx, id)
+}
+
+func containerBulkItems(containers []container.Summary, host string) []bulkItem {
+	var items []bulkItem
+	for _, c := range containers {
+		name := formatID(c.ID, false)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		items = append(items, bulkItem{
+			id:        c.ID,
+			copyText:  c.ID,
+			name:      name,
+			detail:    c.Status,
+			image:     c.Image,
+			state:     string(c.State),
+			created:   time.Unix(c.Created, 0),
+			search:    strings.Join([]string{name, c.Image, formatLabels(c.Labels)}, " "),
+			ports:     formatPorts(c.Ports),
+			urls:      portURLs(c.Ports, host),
+			project:   c.Labels[composeProjectLabel],
+			protected: isProtected(name, c.Labels),
+		})
+	}
+	return items
+}
+
+func imageBulkItems(images []image.Summary, used map[string]bool) []bulkItem {
+	var items []bulkItem
+	for _, img := range images {
+		name, ref := formatID(img.ID, false), img.ID
+		if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+			name, ref = img.RepoTags[0], img.RepoTags[0]
+		}
+		detail := formatSize(img.Size)
+		if !used[img.ID] {
+			detail += ", unused"
+		}
+		items = append(items, bulkItem{
+			id:        img.ID,
+			copyText:  ref,
+			name:      name,
+			detail:    detail,
+			created:   time.Unix(img.Created, 0),
+			size:      img.Size,
+			search:    strings.Join(img.RepoTags, " ") + " " + formatLabels(img.Labels),
+			protected: isProtected(img.ID, img.Labels),
+		})
+	}
+	return items
+}
+
+func volumeBulkItems(volumes []*volume.Volume, used map[string]bool) []bulkItem {
+	var items []bulkItem
+	for _, v := range volumes {
+		detail := v.Driver
+		if used[v.Name] {
+			detail += ", in use"
+		}
+		created, _ := time.Parse(time.RFC3339, v.CreatedAt)
+		items = append(items, bulkItem{
+			id:        v.Name,
+			driver:    v.Driver,
+			created:   created,
+			copyText:  v.Name,
+			name:      v.Name,
+			detail:    detail,
+			search:    strings.Join([]string{v.Name, v.Driver, formatLabels(v.Labels)}, " "),
+			protected: isProtected(v.Name, v.Labels),
+		})
+	}
+	return items
+}
+
+func networkBulkItems(networks []network.Summary, used map[string]bool) []bulkItem {
+	var items []bulkItem
+	for _, n := range networks {
+		detail := n.Driver
+		if used[n.Name] {
+			detail += ", in use"
+		}
+		items = append(items, bulkItem{
+			id:        n.ID,
+			driver:    n.Driver,
+			created:   n.Created,
+			copyText:  n.ID,
+			name:      n.Name,
+			detail:    detail,
+			search:    strings.Join([]string{n.Name, n.Driver, formatLabels(n.Labels)}, " "),
+			protected: isProtected(n.Name, n.Labels),
+		})
+	}
+	return items
+}
+
+// runBulk lets the user mark items, then applies the chosen action to each,
+// reporting success or failure per item. reload lists the items again when
+// the daemon reports a change to them.
+func runBulk(ctx context.Context, cli *client.Client, kind string, items []bulkItem, actions []bulkAction, reload func() ([]bulkItem, error)) {
+	if len(items) == 0 {
+		gray.Printf("No %s found\n", kind)
+		return
+	}
+
+	// Containers can be renamed and signalled in place from the list, their
+	// usage is sampled for alerts, and their exits are diagnosed
+	var rename func(id, name string) error
+	var kill func(id, signal string) error
+	refresher := bulkRefresher{reload: reload, watch: watchBulkEvents(cli, kind)}
+	if kind == "containers" {
+		refresher.sample = sampleUsage(ctx, cli)
+		refresher.diagnose = func(id string, withLogs bool) (crashReport, error) {
+			return diagnoseCrash(ctx, cli, id, withLogs)
+		}
+		rename = func(id, name string) error {
+			return cli.ContainerRename(ctx, id, name)
+		}
+		kill = func(id, signal string) error {
+			return cli.ContainerKill(ctx, id, signal)
+		}
+	}
+
+	selected, action, err := LaunchBulkTUI(kind, items, actions, rename, kill, refresher)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if action == nil {
+		gray.Println("Cancelled")
+		return
+	}
+
+	rows := make([]reviewRow, 0, len(selected))
+	for _, item := range selected {
+		rows = append(rows, reviewRow{item: item})
+	}
+
+	// Removes and stops of several rows get a last look first, where rows
+	// can be left out, annotated, and for stops reordered
+	reviewed, inOrder := false, false
+	if (action.destructive || action.runOrdered != nil) && len(selected) > 1 {
+		var ok bool
+		rows, inOrder, ok, err = LaunchReviewTUI(action, kind, selected)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			gray.Println("Cancelled")
+			return
+		}
+		reviewed = true
+	}
+
+	results, failed := runBulkAction(ctx, cli, kind, action, rows, inOrder)
+	if reviewed {
+		if err := appendAudit(auditEntry{At: time.Now(), Action: action.verb, Kind: kind, Rows: results}); err != nil {
+			yellow.Printf("%s Could not write the audit log: %v\n", glyphs.warn, err)
+		} else {
+			gray.Printf("%s Logged to %s\n", glyphs.detail, auditLogPath())
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// runBulkAction applies an action to the rows not left out, in order, and
+// returns each row's outcome and whether any failed
+func runBulkAction(ctx context.Context, cli *client.Client, kind string, action *bulkAction, rows []reviewRow, inOrder bool) ([]auditResult, bool) {
+	var selected []bulkItem
+	results := make([]auditResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, auditResult{Name: row.item.name, ID: row.item.id, Result: "left out", Note: row.note})
+		if !row.left {
+			selected = append(selected, row.item)
+		}
+	}
+
+	if action.runPair != nil {
+		if err := action.runPair(ctx, cli, selected[0].id, selected[1].id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return nil, true
+		}
+		return nil, false
+	}
+	if action.runAll != nil || action.runOrdered != nil {
+		var err error
+		if action.runAll != nil {
+			err = action.runAll(ctx, cli, selected)
+		} else {
+			err = action.runOrdered(ctx, cli, selected, inOrder)
+		}
+		outcome := action.done
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			outcome = err.Error()
+		}
+		for i, row := range rows {
+			if !row.left {
+				results[i].Result = outcome
+			}
+		}
+		return results, err != nil
+	}
+
+	fmt.Println()
+	cyan.Printf("%s %d %s\n", strings.ToUpper(action.verb), len(selected), kind)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	succeeded, failed, skipped, left := 0, 0, 0, 0
+	step := 0
+	for i, row := range rows {
+		if row.left {
+			left++
+			continue
+		}
+		item := row.item
+		step++
+		gray.Printf("[%d/%d] ", step, len(selected))
+		fmt.Printf("%s ", item.name)
+
+		if action.destructive && item.protected {
+			yellow.Print(glyphs.warn + " ")
+			gray.Println("skipped (protected)")
+			results[i].Result = "protected"
+			skipped++
+		} else if err := action.run(ctx, cli, item.id); err != nil {
+			red.Print(glyphs.failed + " ")
+			red.Println(err)
+			results[i].Result = err.Error()
+			failed++
+		} else {
+			green.Print(glyphs.ok + " ")
+			gray.Println(action.done)
+			results[i].Result = action.done
+			succeeded++
+		}
+		if row.note != "" {
+			gray.Printf("      %s %s\n", glyphs.detail, row.note)
+		}
+	}
+
+	// Summary
+	fmt.Println()
+	green.Printf("%d succeeded", succeeded)
+	if failed > 0 {
+		red.Printf(", %d failed", failed)
+	}
+	if skipped > 0 {
+		yellow.Printf(", %d protected", skipped)
+	}
+	if left > 0 {
+		gray.Printf(", %d left out", left)
+	}
+	fmt.Println()
+
+	return results, failed > 0
+}
@@ -0,0 +1,270 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+const (
+	defaultAlertCPU     = 90.0
+	defaultAlertMemory  = 90.0
+	defaultAlertSamples = 3
+
+	// alertHistoryLimit caps how many ended alerts the panel remembers
+	alertHistoryLimit = 50
+
+	// alertPanelRows is how many alerts the panel shows at once
+	alertPanelRows = 8
+)
+
+// usageSample is a running container's CPU, in percent of one core, and
+// memory, in percent of its limit
+type usageSample struct {
+	cpu    float64
+	memory float64
+}
+
+// containerAlert is a container whose CPU or memory stayed over its
+// threshold; ended is zero while it still is
+type containerAlert struct {
+	id        string
+	name      string
+	metric    string // "CPU" or "MEM"
+	threshold float64
+	value     float64 // the latest sample
+	peak      float64
+	started   time.Time
+	ended     time.Time
+}
+
+// alertTracker turns samples into alerts once a metric is over its
+// threshold for enough samples in a row
+type alertTracker struct {
+	cpu, memory float64
+	samples     int
+	streaks     map[string]int             // by container ID and metric
+	active      map[string]*containerAlert // by container ID and metric
+	history     []containerAlert           // ended alerts, newest first
+}
+
+func newAlertTracker() *alertTracker {
+	t := &alertTracker{
+		cpu:     config.Alerts.CPU,
+		memory:  config.Alerts.Memory,
+		samples: config.Alerts.Samples,
+		streaks: map[string]int{},
+		active:  map[string]*containerAlert{},
+	}
+	if t.cpu <= 0 {
+		t.cpu = defaultAlertCPU
+	}
+	if t.memory <= 0 {
+		t.memory = defaultAlertMemory
+	}
+	if t.samples <= 0 {
+		t.samples = defaultAlertSamples
+	}
+	return t
+}
+
+// alertInterval is the time between samples, or 0 when alerts are off
+func alertInterval() time.Duration {
+	interval, err := parseRefreshInterval(config.Alerts.Interval)
+	if err != nil {
+		return defaultRefreshInterval
+	}
+	return interval
+}
+
+// record takes a round of samples and returns the alerts it started.
+// Containers missing from the samples have stopped, ending their alerts.
+func (t *alertTracker) record(samples map[string]usageSample, names map[string]string, now time.Time) []containerAlert {
+	var started []containerAlert
+	check := func(id, metric string, value, threshold float64) {
+		key := id + "/" + metric
+		alert := t.active[key]
+		if value <= threshold {
+			t.streaks[key] = 0
+			if alert != nil {
+				t.end(key, now)
+			}
+			return
+		}
+		t.streaks[key]++
+		switch {
+		case alert != nil:
+			alert.value = value
+			alert.peak = math.Max(alert.peak, value)
+		case t.streaks[key] >= t.samples:
+			alert = &containerAlert{id: id, name: names[id], metric: metric, threshold: threshold, value: value, peak: value, started: now}
+			t.active[key] = alert
+			started = append(started, *alert)
+		}
+	}
+	for id, sample := range samples {
+		check(id, "CPU", sample.cpu, t.cpu)
+		check(id, "MEM", sample.memory, t.memory)
+	}
+
+	for key, alert := range t.active {
+		if _, running := samples[alert.id]; !running {
+			t.end(key, now)
+		}
+	}
+	for key := range t.streaks {
+		id, _, _ := strings.Cut(key, "/")
+		if _, running := samples[id]; !running {
+			delete(t.streaks, key)
+		}
+	}
+	return started
+}
+
+// end moves an active alert to the history
+func (t *alertTracker) end(key string, now time.Time) {
+	alert := *t.active[key]
+	delete(t.active, key)
+	alert.ended = now
+	t.history = append([]containerAlert{alert}, t.history...)
+	if len(t.history) > alertHistoryLimit {
+		t.history = t.history[:alertHistoryLimit]
+	}
+}
+
+// rowAlert describes a container's active alerts for its row, e.g.
+// "CPU 143%", or "" when it has none
+func (t *alertTracker) rowAlert(id string) string {
+	var parts []string
+	for _, metric := range []string{"CPU", "MEM"} {
+		if alert := t.active[id+"/"+metric]; alert != nil {
+			parts = append(parts, fmt.Sprintf("%s %.0f%%", metric, alert.value))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sorted returns the active alerts, longest running first, then the history
+func (t *alertTracker) sorted() (active, ended []containerAlert) {
+	for _, alert := range t.active {
+		active = append(active, *alert)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		a, b := active[i], active[j]
+		if !a.started.Equal(b.started) {
+			return a.started.Before(b.started)
+		}
+		return a.name+a.metric < b.name+b.metric
+	})
+	return active, t.history
+}
+
+// sampleUsage takes one stats sample from every running container at once
+func sampleUsage(ctx context.Context, cli *client.Client) func() (map[string]usageSample, error) {
+	return func() (map[string]usageSample, error) {
+		containers, err := cli.ContainerList(ctx, container.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		samples := map[string]usageSample{}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, c := range containers {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				stats, err := sampleStats(ctx, cli, id)
+				if err != nil {
+					// Stopped between the listing and the sample
+					return
+				}
+				sample := usageSample{cpu: cpuPercent(stats)}
+				if used, limit := memoryUsage(stats); limit > 0 {
+					sample.memory = float64(used) / float64(limit) * 100
+				}
+				mu.Lock()
+				samples[id] = sample
+				mu.Unlock()
+			}(c.ID)
+		}
+		wg.Wait()
+		return samples, nil
+	}
+}
+
+// bulkAlertTickMsg asks for the next round of samples
+type bulkAlertTickMsg struct{}
+
+type bulkSampledMsg struct {
+	samples map[string]usageSample
+	err     error
+}
+
+// scheduleSample ticks once the alert interval has passed
+func (m *bulkModel) scheduleSample() tea.Cmd {
+	return tea.Tick(m.alertEvery, func(time.Time) tea.Msg { return bulkAlertTickMsg{} })
+}
+
+// updateAlerts samples on each tick and records what came back, keeping
+// the loop going whether or not a sample worked
+func (m *bulkModel) updateAlerts(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case bulkAlertTickMsg:
+		sample := m.sample
+		return func() tea.Msg {
+			samples, err := sample()
+			return bulkSampledMsg{samples: samples, err: err}
+		}
+
+	case bulkSampledMsg:
+		if msg.err != nil {
+			m.hint = fmt.Sprintf("Could not sample usage: %v", msg.err)
+			return m.scheduleSample()
+		}
+		names := map[string]string{}
+		for _, item := range m.items {
+			names[item.id] = item.name
+		}
+		started := m.alerts.record(msg.samples, names, time.Now())
+		if len(started) > 0 {
+			alert := started[0]
+			m.hint = fmt.Sprintf("%s %s at %.0f%% (over %.0f%% for %d samples)", alert.name, alert.metric, alert.value, alert.threshold, m.alerts.samples)
+			if len(started) > 1 {
+				m.hint += fmt.Sprintf(" and %d more; ! for alerts", len(started)-1)
+			}
+		}
+		return m.scheduleSample()
+	}
+	return nil
+}
+
+// alertPanelLines renders the alerts panel: the active alerts, then the
+// ended ones, up to alertPanelRows
+func (m bulkModel) alertPanelLines() []string {
+	active, ended := m.alerts.sorted()
+	if len(active) == 0 && len(ended) == 0 {
+		return []string{helpStyle.Render(fmt.Sprintf("  No alerts yet (CPU over %.0f%% or memory over %.0f%% for %d samples)", m.alerts.cpu, m.alerts.memory, m.alerts.samples))}
+	}
+
+	var lines []string
+	for _, alert := range active {
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  %s %-24s %s %4.0f%%  peak %.0f%%  since %s", glyphs.warn, ellipsize(alert.name, 24), alert.metric, alert.value, alert.peak, formatClock(alert.started))))
+	}
+	for _, alert := range ended {
+		lines = append(lines, helpStyle.Render(fmt.Sprintf("    %-24s %s peak %.0f%%  %s to %s", ellipsize(alert.name, 24), alert.metric, alert.peak, formatClock(alert.started), formatClock(alert.ended))))
+	}
+	if len(lines) > alertPanelRows {
+		more := len(lines) - alertPanelRows + 1
+		lines = append(lines[:alertPanelRows-1], helpStyle.Render(fmt.Sprintf("  ... %d more", more)))
+	}
+	return lines
+}
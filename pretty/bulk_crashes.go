@@ -0,0 +1,326 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+)
+
+const (
+	// A container that exits crashLoopExits times within crashLoopWindow, or
+	// whose restart count climbs that much while the list is open, is
+	// badged as crash looping
+	crashLoopWindow = 10 * time.Minute
+	crashLoopExits  = 3
+)
+
+// crashReport is what an inspect says about a container's last exit, with
+// the log lines leading up to it when asked for
+type crashReport struct {
+	state  string
+	times  containerTimes
+	reason string // the daemon's error for the exit, if any
+	policy string // restart policy
+	logs   []string
+	cause  string // the probable cause, from analyzeCrash
+}
+
+// crashTracker counts the exits reported by die events and the restart
+// counts seen after them
+type crashTracker struct {
+	exits    map[string][]time.Time // by container ID, within the window
+	restarts map[string]int         // latest restart count by container ID
+	baseline map[string]int         // the first restart count seen
+}
+
+func newCrashTracker() *crashTracker {
+	return &crashTracker{exits: map[string][]time.Time{}, restarts: map[string]int{}, baseline: map[string]int{}}
+}
+
+// exited records an exit, forgetting the ones older than the window
+func (t *crashTracker) exited(id string, at time.Time) {
+	t.exits[id] = append(t.recentExits(id, at), at)
+}
+
+// restarted records a container's restart count from an inspect
+func (t *crashTracker) restarted(id string, count int) {
+	if _, seen := t.baseline[id]; !seen {
+		t.baseline[id] = count
+	}
+	t.restarts[id] = count
+}
+
+func (t *crashTracker) recentExits(id string, now time.Time) []time.Time {
+	var recent []time.Time
+	for _, at := range t.exits[id] {
+		if now.Sub(at) < crashLoopWindow {
+			recent = append(recent, at)
+		}
+	}
+	return recent
+}
+
+// badge describes a container stuck restarting for its row, e.g.
+// "crash loop: 4 exits in 10m, 37 restarts", or "" when it isn't
+func (t *crashTracker) badge(id, state string, now time.Time) string {
+	exits := len(t.recentExits(id, now))
+	count, known := t.restarts[id]
+	climbed := count - t.baseline[id]
+
+	var text string
+	switch {
+	case exits >= crashLoopExits:
+		text = fmt.Sprintf("crash loop: %d exits in %.0fm", exits, crashLoopWindow.Minutes())
+	case known && climbed >= crashLoopExits:
+		text = fmt.Sprintf("crash loop: %d restarts since opened", climbed)
+	case state == "restarting":
+		text = "restarting"
+	default:
+		return ""
+	}
+	if known {
+		text += fmt.Sprintf(", %d restarts", count)
+	}
+	return text
+}
+
+// looping counts the rows with a badge, for the title
+func (t *crashTracker) looping(items []bulkItem, now time.Time) int {
+	n := 0
+	for _, item := range items {
+		if t.badge(item.id, item.state, now) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// diagnoseCrash inspects a container's last exit and, with withLogs, reads
+// the log lines it wrote before it
+func diagnoseCrash(ctx context.Context, cli *client.Client, id string, withLogs bool) (crashReport, error) {
+	info, err := cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return crashReport{}, err
+	}
+	if info.State == nil {
+		return crashReport{}, fmt.Errorf("the daemon reported no state")
+	}
+	report := crashReport{
+		state: info.State.Status,
+		times: containerTimes{
+			started:   parseDaemonTime(info.State.StartedAt),
+			finished:  parseDaemonTime(info.State.FinishedAt),
+			exitCode:  info.State.ExitCode,
+			restarts:  info.RestartCount,
+			oomKilled: info.State.OOMKilled,
+		},
+		reason: info.State.Error,
+	}
+	if info.HostConfig != nil {
+		report.policy = string(info.HostConfig.RestartPolicy.Name)
+	}
+	if !withLogs || report.times.finished.IsZero() {
+		return report, nil
+	}
+
+	// Log drivers apply a tail before until, so a tail would come back
+	// short once the next run has logged; read up to the exit instead and
+	// keep the end. When the container is still down, its last run started
+	// at started, which bounds the read.
+	options := container.LogsOptions{ShowStdout: true, ShowStderr: true, Until: report.times.finished.Format(time.RFC3339Nano)}
+	if report.times.started.Before(report.times.finished) {
+		options.Since = report.times.started.Format(time.RFC3339Nano)
+	}
+	reader, err := cli.ContainerLogs(ctx, id, options)
+	if err != nil {
+		return report, err
+	}
+	defer reader.Close()
+	tty := info.Config != nil && info.Config.Tty
+	err = demuxLogs(reader, tty, func(text string, _ bool) bool {
+		report.logs = append(report.logs, text)
+		if len(report.logs) > crashLogLines {
+			report.logs = report.logs[len(report.logs)-crashLogLines:]
+		}
+		return true
+	})
+	// analyzeCrash only looks at stopped containers; for one that has
+	// restarted since, the log lines still explain the exit
+	state := *info.State
+	state.Status = "exited"
+	if cause, _, ok := analyzeCrash(&state, report.logs); ok {
+		report.cause = cause
+	}
+	return report, err
+}
+
+// crashPanel is the diagnostics for one container, shown under the rows
+type crashPanel struct {
+	id      string
+	name    string
+	loading bool
+	report  crashReport
+	err     error
+	scroll  int // log lines scrolled back from the newest
+}
+
+// bulkCrashInspectedMsg carries a restart count looked up after a die event
+type bulkCrashInspectedMsg struct {
+	id     string
+	report crashReport
+	err    error
+}
+
+// bulkCrashDiagnosedMsg carries the diagnostics for the panel
+type bulkCrashDiagnosedMsg struct {
+	id     string
+	report crashReport
+	err    error
+}
+
+// recordCrash counts a die event and looks up the restart count after it
+func (m *bulkModel) recordCrash(event events.Message) tea.Cmd {
+	if m.crashes == nil || event.Type != events.ContainerEventType || event.Action != events.ActionDie {
+		return nil
+	}
+	id := event.Actor.ID
+	m.crashes.exited(id, time.Unix(0, event.TimeNano))
+	diagnose := m.diagnose
+	return func() tea.Msg {
+		report, err := diagnose(id, false)
+		return bulkCrashInspectedMsg{id: id, report: report, err: err}
+	}
+}
+
+// openCrashPanel shows the diagnostics for the cursor row
+func (m *bulkModel) openCrashPanel() tea.Cmd {
+	item := m.items[m.visible[m.cursor]]
+	m.crashPanel = &crashPanel{id: item.id, name: item.name, loading: true}
+	diagnose := m.diagnose
+	return func() tea.Msg {
+		report, err := diagnose(item.id, true)
+		return bulkCrashDiagnosedMsg{id: item.id, report: report, err: err}
+	}
+}
+
+// updateCrashes takes the inspects behind the badges and the panel
+func (m *bulkModel) updateCrashes(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case bulkCrashInspectedMsg:
+		// A container removed since it died has nothing left to badge
+		if msg.err == nil {
+			m.crashes.restarted(msg.id, msg.report.times.restarts)
+		}
+
+	case bulkCrashDiagnosedMsg:
+		if m.crashPanel == nil || m.crashPanel.id != msg.id {
+			return
+		}
+		m.crashPanel.loading = false
+		m.crashPanel.report, m.crashPanel.err = msg.report, msg.err
+		if msg.err == nil {
+			m.crashes.restarted(msg.id, msg.report.times.restarts)
+		}
+	}
+	m.clampOffset()
+}
+
+// updateCrashPanel scrolls the panel's log lines or closes it
+func (m *bulkModel) updateCrashPanel(msg tea.KeyMsg) {
+	most := max(len(m.crashPanel.report.logs)-m.crashLogRoom(), 0)
+	switch msg.String() {
+	case "up", "k":
+		m.crashPanel.scroll = min(m.crashPanel.scroll+1, most)
+	case "down", "j":
+		m.crashPanel.scroll = max(m.crashPanel.scroll-1, 0)
+	case "i", "esc", "q":
+		m.crashPanel = nil
+	}
+	m.clampOffset()
+}
+
+// crashLogRoom is how many log lines the panel shows at once, leaving the
+// rows at least half the screen
+func (m bulkModel) crashLogRoom() int {
+	if m.height == 0 {
+		return crashLogLines
+	}
+	return max(m.height/2-3, 3)
+}
+
+// crashPanelLines renders the panel: how the container last exited, its
+// restarts and policy, and the log lines before the exit
+func (m bulkModel) crashPanelLines() []string {
+	p := m.crashPanel
+	switch {
+	case p.loading:
+		return []string{helpStyle.Render("  Inspecting...")}
+	case p.err != nil:
+		return []string{errorStyle.Render(fmt.Sprintf("  Could not diagnose: %v", p.err))}
+	}
+	r := p.report
+	if r.times.finished.IsZero() {
+		return []string{helpStyle.Render("  Has not exited since it was created")}
+	}
+
+	width := m.width
+	if width == 0 {
+		width = 100
+	}
+	exit := fmt.Sprintf("  Last exited with code %d %s (at %s)", r.times.exitCode, formatAgo(time.Since(r.times.finished)), formatDateTime(r.times.finished))
+	if r.times.oomKilled {
+		exit += ", OOM killed"
+	}
+	exitStyle := helpStyle
+	if r.times.exitCode != 0 || r.times.oomKilled {
+		exitStyle = errorStyle
+	}
+	summary := fmt.Sprintf("  %d restarts, restart policy %s, now %s", r.times.restarts, orDash(r.policy), r.state)
+	if r.reason != "" {
+		summary += ", error: " + r.reason
+	}
+	lines := []string{exitStyle.Render(ellipsize(exit, width)), helpStyle.Render(ellipsize(summary, width))}
+	if r.cause != "" {
+		lines = append(lines, errorStyle.Render(ellipsize("  Probable cause: "+r.cause, width)))
+	}
+	if len(r.logs) == 0 {
+		return append(lines, helpStyle.Render("  No log lines before the exit"))
+	}
+
+	end := len(r.logs) - p.scroll
+	start := max(end-m.crashLogRoom(), 0)
+	lines = append(lines, helpStyle.Render(fmt.Sprintf("  Log lines %d-%d of the last %d before the exit:", start+1, end, len(r.logs))))
+	for _, line := range r.logs[start:end] {
+		text := "  " + ellipsize(line, width-2)
+		if style, ok := lineLevelStyles[detectLevel(line)]; ok {
+			text = style.Render(text)
+		}
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+// crashBadge is a row's crash badge, or "" without crash tracking
+func (m bulkModel) crashBadge(item bulkItem, now time.Time) string {
+	if m.crashes == nil {
+		return ""
+	}
+	return m.crashes.badge(item.id, item.state, now)
+}
+
+// joinFlags joins a row's badges, skipping empty ones
+func joinFlags(flags ...string) string {
+	var kept []string
+	for _, flag := range flags {
+		if flag != "" {
+			kept = append(kept, flag)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
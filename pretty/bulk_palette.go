@@ -0,0 +1,285 @@
+package pretty
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteRows is how many matches the command palette shows at once
+const paletteRows = 10
+
+// paletteEntry is a command the ctrl+p palette offers
+type paletteEntry struct {
+	title string // what the search matches, e.g. "logs for web"
+	key   string // the list key that does the same, if any
+	run   func(m *bulkModel) tea.Cmd
+}
+
+// bulkPalette searches the list's commands: its keys, its actions on the
+// marked rows or the cursor row, each container's views, and the other
+// lists and views
+type bulkPalette struct {
+	input   textinput.Model
+	entries []paletteEntry
+	matches []int // indexes into entries, best first
+	cursor  int
+}
+
+// bulkListViews are the lists the palette switches between, with the
+// arguments that open each
+var bulkListViews = []struct {
+	kind string
+	args []string
+}{
+	{"containers", []string{"ps", "-i"}},
+	{"images", []string{"images", "-i"}},
+	{"volumes", []string{"volumes", "-i"}},
+	{"networks", []string{"networks", "-i"}},
+}
+
+// openPalette lists what can be run from here and focuses the search
+func (m *bulkModel) openPalette() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "stop, logs for web, switch to images..."
+	input.CharLimit = 100
+	input.Width = 40
+	input.Focus()
+	m.palette = &bulkPalette{input: input, entries: m.paletteEntries()}
+	m.palette.match()
+	m.clampOffset()
+	return textinput.Blink
+}
+
+// paletteEntries builds the palette's commands for the list as it is now
+func (m *bulkModel) paletteEntries() []paletteEntry {
+	var entries []paletteEntry
+	add := func(title, key string, run func(m *bulkModel) tea.Cmd) {
+		entries = append(entries, paletteEntry{title: title, key: key, run: run})
+	}
+	// Most commands do what their key does
+	press := func(key string) func(m *bulkModel) tea.Cmd {
+		return func(m *bulkModel) tea.Cmd {
+			updated, cmd := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			*m = updated
+			return cmd
+		}
+	}
+
+	var cursor *bulkItem
+	if m.cursor < len(m.visible) {
+		item := m.items[m.visible[m.cursor]]
+		cursor = &item
+	}
+	marked := m.selectedCount()
+
+	// The actions take the marked rows, or the cursor row when none are
+	for _, action := range m.actions {
+		switch {
+		case action.noRows:
+			add(fmt.Sprintf("%s (%s)", action.verb, m.kind), action.key, press(action.key))
+		case action.runPair != nil:
+			if marked == 2 {
+				add(fmt.Sprintf("%s the 2 marked %s", action.verb, m.kind), action.key, press(action.key))
+			}
+		case marked > 0:
+			add(fmt.Sprintf("%s %d marked %s", action.verb, marked, m.kind), action.key, press(action.key))
+		case cursor != nil:
+			key := action.key
+			add(action.verb+" "+cursor.name, key, func(m *bulkModel) tea.Cmd {
+				if index := m.itemIndex(cursor.id); index >= 0 {
+					m.items[index].selected = true
+				}
+				return press(key)(m)
+			})
+		}
+	}
+
+	add("filter rows", "/", press("/"))
+	add("mark all / none", "a", press("a"))
+	add("sort by the next order", ">", press(">"))
+	if m.reload != nil {
+		add("toggle auto-refresh", "A", press("A"))
+	}
+	if m.alerts != nil {
+		add("show alerts", "!", press("!"))
+	}
+	if cursor != nil {
+		add(fmt.Sprintf("copy %s of %s", copyLabel(m.kind), cursor.name), "y", press("y"))
+		if m.rename != nil {
+			add("rename "+cursor.name, "n", press("n"))
+		}
+		if m.kill != nil {
+			add("send a signal to "+cursor.name, "K", press("K"))
+		}
+		if m.diagnose != nil {
+			add("crash info for "+cursor.name, "i", press("i"))
+		}
+		if len(cursor.urls) > 0 {
+			add("open "+cursor.name+" in the browser", "o", press("o"))
+		}
+	}
+
+	// Views of any container, not just the cursor row's, come back here
+	// when they are quit
+	if m.kind == "containers" {
+		var projects []string
+		for _, item := range m.items {
+			add("logs for "+item.name, "", openView("logs", item.id))
+			add("quick view of "+item.name, "", openView("quick", item.id))
+			add("watch "+item.name, "", openView("watch", item.id))
+			if item.state == "running" {
+				add("shell in "+item.name, "", openView("sh", item.name))
+			}
+			if item.project != "" && !slices.Contains(projects, item.project) {
+				projects = append(projects, item.project)
+			}
+		}
+		sort.Strings(projects)
+		for _, project := range projects {
+			add("stop project "+project, "", switchTo("stop", "--project", project))
+			add("start project "+project, "", switchTo("start", "--project", project))
+		}
+	}
+
+	// Other lists and views take over from this one
+	for _, view := range bulkListViews {
+		if view.kind != m.kind {
+			add("switch to "+view.kind, "", switchTo(view.args...))
+		}
+	}
+	add("dashboard", "", switchTo("dashboard"))
+	add("events", "", switchTo("events"))
+	add("prune", "", switchTo("prune"))
+	return entries
+}
+
+// openView runs another dockit view over the list, which picks up again
+// when it is quit
+func openView(args ...string) func(m *bulkModel) tea.Cmd {
+	return func(m *bulkModel) tea.Cmd {
+		return tea.ExecProcess(dockitCommand(args...), func(err error) tea.Msg {
+			return externalDoneMsg{err: err}
+		})
+	}
+}
+
+// switchTo quits the list for another dockit command, which LaunchBulkTUI
+// runs once the list is gone so its output stays on the screen
+func switchTo(args ...string) func(m *bulkModel) tea.Cmd {
+	return func(m *bulkModel) tea.Cmd {
+		m.switchTo = args
+		return tea.Quit
+	}
+}
+
+// runSwitch runs the command the palette switched to in place of this dockit
+func runSwitch(args []string) {
+	cmd := dockitCommand(args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	os.Exit(runCommand(cmd))
+}
+
+// match ranks the entries against the search: titles containing it first,
+// earliest match first, then titles holding its letters in order, tightest
+// first
+func (p *bulkPalette) match() {
+	query := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	type ranked struct {
+		index, class, score int
+	}
+	var found []ranked
+	for i, entry := range p.entries {
+		title := strings.ToLower(entry.title)
+		if index := strings.Index(title, query); index >= 0 {
+			found = append(found, ranked{i, 0, index})
+		} else if span, ok := subsequenceSpan(query, title); ok {
+			found = append(found, ranked{i, 1, span})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].class != found[j].class {
+			return found[i].class < found[j].class
+		}
+		return found[i].score < found[j].score
+	})
+	p.matches = p.matches[:0]
+	for _, r := range found {
+		p.matches = append(p.matches, r.index)
+	}
+	p.cursor = 0
+}
+
+// updatePalette moves through the matches, runs one with enter, or narrows
+// them as the user types
+func (m *bulkModel) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	p := m.palette
+	switch msg.String() {
+	case "up", "ctrl+k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return nil
+	case "down", "ctrl+j":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return nil
+	case "esc", "ctrl+p":
+		m.palette = nil
+		m.clampOffset()
+		return nil
+	case "enter":
+		if len(p.matches) == 0 {
+			return nil
+		}
+		entry := p.entries[p.matches[p.cursor]]
+		m.palette = nil
+		m.clampOffset()
+		return entry.run(m)
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.match()
+	m.clampOffset()
+	return cmd
+}
+
+// paletteLines renders the search and the matches around the cursor
+func (m bulkModel) paletteLines() []string {
+	p := m.palette
+	lines := []string{p.input.View()}
+	if len(p.matches) == 0 {
+		return append(lines, helpStyle.Render("  No matching commands"))
+	}
+
+	start := max(min(p.cursor-paletteRows/2, len(p.matches)-paletteRows), 0)
+	end := min(start+paletteRows, len(p.matches))
+	width := m.width
+	if width == 0 {
+		width = 80
+	}
+	for i := start; i < end; i++ {
+		entry := p.entries[p.matches[i]]
+		cursor := "  "
+		if i == p.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+		line := cursor + ellipsize(entry.title, max(width-12, 10))
+		if entry.key != "" {
+			line += helpStyle.Render("  " + entry.key)
+		}
+		lines = append(lines, line)
+	}
+	if len(p.matches) > paletteRows {
+		lines = append(lines, helpStyle.Render(fmt.Sprintf("  %d of %d", p.cursor+1, len(p.matches))))
+	}
+	return lines
+}
@@ -0,0 +1,225 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// bulkRefresher keeps a list's rows current: reload lists them again,
+// watch, when set, streams the daemon events that can change them, sample,
+// when set, measures the running containers' usage for alerts, and
+// diagnose, when set, inspects a container's last exit
+type bulkRefresher struct {
+	reload   func() ([]bulkItem, error)
+	watch    func(ctx context.Context) (<-chan events.Message, <-chan error)
+	sample   func() (map[string]usageSample, error)
+	diagnose func(id string, withLogs bool) (crashReport, error)
+}
+
+// bulkEventTypes are the events that can change each kind's rows; image,
+// volume, and network rows also say whether a container uses them
+var bulkEventTypes = map[string][]events.Type{
+	"containers": {events.ContainerEventType},
+	"images":     {events.ImageEventType, events.ContainerEventType},
+	"volumes":    {events.VolumeEventType, events.ContainerEventType},
+	"networks":   {events.NetworkEventType, events.ContainerEventType},
+}
+
+// watchBulkEvents subscribes to the events that can change kind's rows
+func watchBulkEvents(cli *client.Client, kind string) func(ctx context.Context) (<-chan events.Message, <-chan error) {
+	types, ok := bulkEventTypes[kind]
+	if !ok {
+		return nil
+	}
+	return func(ctx context.Context) (<-chan events.Message, <-chan error) {
+		filterArgs := filters.NewArgs()
+		for _, t := range types {
+			filterArgs.Add("type", string(t))
+		}
+		return cli.Events(ctx, events.ListOptions{Filters: filterArgs})
+	}
+}
+
+// changesRows reports whether an event can change what a list shows; execs
+// and file copies leave containers as they were
+func changesRows(event events.Message) bool {
+	if strings.HasPrefix(string(event.Action), "exec_") {
+		return false
+	}
+	switch event.Action {
+	case events.ActionAttach, events.ActionDetach, events.ActionResize, events.ActionTop,
+		events.ActionCopy, events.ActionArchivePath, events.ActionExtractToDir, events.ActionExport:
+		return false
+	}
+	return true
+}
+
+// bulkWatchMsg starts auto-refresh from Update, so the model keeps its
+// events stream
+type bulkWatchMsg struct {
+	gen int
+}
+
+// bulkEventMsg reports a change to the listed resources
+type bulkEventMsg struct {
+	gen   int
+	event events.Message
+}
+
+type bulkEventsErrMsg struct {
+	gen int
+	err error
+}
+
+// bulkRefreshTickMsg asks for a fresh listing when polling
+type bulkRefreshTickMsg struct {
+	gen int
+}
+
+type bulkReloadedMsg struct {
+	gen   int
+	items []bulkItem
+	err   error
+}
+
+// updateRefresh handles the auto-refresh messages. Messages from before
+// auto-refresh was last toggled are ignored.
+func (m *bulkModel) updateRefresh(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case bulkWatchMsg:
+		if msg.gen != m.refreshGen {
+			return nil
+		}
+		if m.watch == nil {
+			return m.scheduleRefresh()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.stopEvents = cancel
+		m.eventStream, m.eventErrs = m.watch(ctx)
+		return m.waitForEvent()
+
+	case bulkEventMsg:
+		if msg.gen != m.refreshGen {
+			return nil
+		}
+		crash := m.recordCrash(msg.event)
+		if m.reloading || m.rowsPinned() {
+			// Bursts of events, like compose starting a project, come down
+			// to one more reload
+			m.reloadPending = true
+			return tea.Batch(m.waitForEvent(), crash)
+		}
+		return tea.Batch(m.waitForEvent(), m.reloadNow(), crash)
+
+	case bulkEventsErrMsg:
+		if msg.gen != m.refreshGen {
+			return nil
+		}
+		// Without events, fall back to listing the rows every interval
+		m.stopEvents()
+		m.eventStream, m.eventErrs = nil, nil
+		m.hint = fmt.Sprintf("Lost the events stream (%v); refreshing every %s instead", msg.err, m.refresh)
+		return m.scheduleRefresh()
+
+	case bulkRefreshTickMsg:
+		if msg.gen != m.refreshGen {
+			return nil
+		}
+		if m.reloading || m.rowsPinned() {
+			m.reloadPending = true
+			return m.scheduleRefresh()
+		}
+		return m.reloadNow()
+
+	case bulkReloadedMsg:
+		if msg.gen != m.refreshGen {
+			return nil
+		}
+		m.reloading = false
+		switch {
+		case msg.err != nil:
+			m.hint = fmt.Sprintf("Could not refresh: %v", msg.err)
+		case m.rowsPinned():
+			m.reloadPending = true
+		default:
+			m.replaceItems(msg.items)
+		}
+		if m.eventStream == nil {
+			return m.scheduleRefresh()
+		}
+	}
+	return nil
+}
+
+// rowsPinned reports whether rows are addressed by position, while a picker
+// or the rename is open, so a new listing has to wait
+func (m *bulkModel) rowsPinned() bool {
+	return m.renaming || m.killing || m.portChoices != nil
+}
+
+// reloadNow lists the rows again in the background
+func (m *bulkModel) reloadNow() tea.Cmd {
+	m.reloading = true
+	m.reloadPending = false
+	reload, gen := m.reload, m.refreshGen
+	return func() tea.Msg {
+		items, err := reload()
+		return bulkReloadedMsg{gen: gen, items: items, err: err}
+	}
+}
+
+// scheduleRefresh ticks once the interval has passed
+func (m *bulkModel) scheduleRefresh() tea.Cmd {
+	gen := m.refreshGen
+	return tea.Tick(m.refresh, func(time.Time) tea.Msg {
+		return bulkRefreshTickMsg{gen: gen}
+	})
+}
+
+// waitForEvent waits for the next event that changes the rows
+func (m *bulkModel) waitForEvent() tea.Cmd {
+	stream, errs, gen := m.eventStream, m.eventErrs, m.refreshGen
+	return func() tea.Msg {
+		for {
+			select {
+			case event := <-stream:
+				if changesRows(event) {
+					return bulkEventMsg{gen: gen, event: event}
+				}
+			case err := <-errs:
+				return bulkEventsErrMsg{gen: gen, err: err}
+			}
+		}
+	}
+}
+
+// toggleRefresh turns auto-refresh on or off; turning it on lists the rows
+// again straight away
+func (m *bulkModel) toggleRefresh() tea.Cmd {
+	m.refreshGen++
+	m.refreshOn = !m.refreshOn
+	m.reloading, m.reloadPending = false, false
+	if m.stopEvents != nil {
+		m.stopEvents()
+		m.stopEvents = nil
+	}
+	m.eventStream, m.eventErrs = nil, nil
+	if !m.refreshOn {
+		m.notice = "Auto-refresh off"
+		return nil
+	}
+
+	if m.refresh <= 0 {
+		m.refresh = defaultRefreshInterval
+	}
+	m.notice = "Auto-refresh on"
+	gen := m.refreshGen
+	return tea.Batch(m.reloadNow(), func() tea.Msg { return bulkWatchMsg{gen: gen} })
+}
@@ -0,0 +1,250 @@
+package pretty
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reviewRow is one marked row in the review before a bulk action runs
+type reviewRow struct {
+	item bulkItem
+	left bool   // left out of the run
+	note string // why, or anything worth keeping in the audit log
+}
+
+// reviewModel is a last look at the rows a remove or stop is about to touch:
+// rows can be left out and annotated, and for stops reordered
+type reviewModel struct {
+	verb      string
+	done      string
+	kind      string
+	rows      []reviewRow
+	cursor    int
+	skipsProt bool // protected rows are skipped by this action
+	canOrder  bool
+	reordered bool
+	noteInput textinput.Model
+	noting    bool
+	confirmed bool
+}
+
+func newReviewModel(action *bulkAction, kind string, items []bulkItem) reviewModel {
+	ni := textinput.New()
+	ni.Placeholder = "note for the audit log"
+	ni.CharLimit = 200
+	ni.Width = 50
+
+	rows := make([]reviewRow, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, reviewRow{item: item})
+	}
+	return reviewModel{
+		verb:      action.verb,
+		done:      action.done,
+		kind:      kind,
+		rows:      rows,
+		skipsProt: action.destructive,
+		canOrder:  action.runOrdered != nil,
+		noteInput: ni,
+	}
+}
+
+func (m reviewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.noting {
+		switch key.String() {
+		case "enter":
+			m.rows[m.cursor].note = strings.TrimSpace(m.noteInput.Value())
+			fallthrough
+		case "esc":
+			m.noting = false
+			m.noteInput.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.noteInput, cmd = m.noteInput.Update(msg)
+		return m, cmd
+	}
+
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "K", "shift+up":
+		if m.canOrder && m.cursor > 0 {
+			m.rows[m.cursor], m.rows[m.cursor-1] = m.rows[m.cursor-1], m.rows[m.cursor]
+			m.cursor--
+			m.reordered = true
+		}
+	case "J", "shift+down":
+		if m.canOrder && m.cursor < len(m.rows)-1 {
+			m.rows[m.cursor], m.rows[m.cursor+1] = m.rows[m.cursor+1], m.rows[m.cursor]
+			m.cursor++
+			m.reordered = true
+		}
+	case " ", "x":
+		m.rows[m.cursor].left = !m.rows[m.cursor].left
+	case "n":
+		m.noting = true
+		m.noteInput.SetValue(m.rows[m.cursor].note)
+		m.noteInput.CursorEnd()
+		return m, m.noteInput.Focus()
+	case "enter":
+		if m.included() > 0 {
+			m.confirmed = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// included counts the rows the action will run on
+func (m reviewModel) included() int {
+	n := 0
+	for _, row := range m.rows {
+		if !row.left && !(m.skipsProt && row.item.protected) {
+			n++
+		}
+	}
+	return n
+}
+
+func (m reviewModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("REVIEW: %s %d %s", strings.ToUpper(m.verb), len(m.rows), m.kind)))
+	sb.WriteString("\n")
+
+	for i, row := range m.rows {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+
+		checkbox := selectedStyle.Render("[" + glyphs.ok + "]")
+		status := ""
+		switch {
+		case m.skipsProt && row.item.protected:
+			checkbox = "[ ]"
+			status = "protected, will be skipped"
+		case row.left:
+			checkbox = "[ ]"
+			status = "left out"
+		}
+
+		line := fmt.Sprintf("%s %2d. %-40s %s", checkbox, i+1, ellipsize(row.item.name, 40), row.item.detail)
+		if status != "" {
+			line += helpStyle.Render("  " + status)
+		}
+		sb.WriteString(cursor + line + "\n")
+
+		switch {
+		case m.noting && i == m.cursor:
+			sb.WriteString("       " + m.noteInput.View() + "\n")
+		case row.note != "":
+			sb.WriteString(helpStyle.Render("       "+glyphs.detail+" "+row.note) + "\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	summary := fmt.Sprintf("%d of %d %s will be %s", m.included(), len(m.rows), m.kind, m.done)
+	if m.reordered {
+		summary += ", one at a time in this order"
+	}
+	sb.WriteString(summary + "\n")
+
+	help := []string{"space: leave out/include", "n: note"}
+	if m.canOrder {
+		help = append(help, "K/J: move up/down")
+	}
+	help = append(help, "enter: run", "q: cancel")
+	if m.noting {
+		help = []string{"enter: keep note", "esc: cancel"}
+	}
+	sb.WriteString(helpStyle.Render(strings.Join(help, " | ")))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// LaunchReviewTUI shows the rows an action is about to run on for a last
+// look. It returns the rows in their final order, whether the user changed
+// that order, and false if the user cancelled.
+func LaunchReviewTUI(action *bulkAction, kind string, items []bulkItem) ([]reviewRow, bool, bool, error) {
+	p := newProgram(newReviewModel(action, kind, items), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return nil, false, false, fmt.Errorf("error running TUI: %v", err)
+	}
+
+	result := final.(reviewModel)
+	return result.rows, result.reordered, result.confirmed, nil
+}
+
+// auditEntry is one reviewed bulk run, as appended to the audit log
+type auditEntry struct {
+	At     time.Time     `json:"at"`
+	Action string        `json:"action"`
+	Kind   string        `json:"kind"`
+	Rows   []auditResult `json:"rows"`
+}
+
+type auditResult struct {
+	Name   string `json:"name"`
+	ID     string `json:"id"`
+	Result string `json:"result"` // the action's done word, "left out", "protected", or the error
+	Note   string `json:"note,omitempty"`
+}
+
+// auditLogPath is where reviewed bulk runs are logged, next to the config
+// file
+func auditLogPath() string {
+	path := ConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "audit.log")
+}
+
+// appendAudit adds one JSON line for a reviewed run to the audit log
+func appendAudit(entry auditEntry) error {
+	path := auditLogPath()
+	if path == "" {
+		return fmt.Errorf("could not determine config location")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
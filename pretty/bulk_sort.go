@@ -0,0 +1,125 @@
+package pretty
+
+import (
+	"sort"
+	"strings"
+)
+
+// bulkSort is an order the picker's rows can be put in with < and >
+type bulkSort struct {
+	label string
+	desc  bool // the direction it is first applied in, e.g. newest first
+	less  func(a, b bulkItem) bool
+}
+
+// stateRank orders container states from running to gone
+var stateRank = map[string]int{"running": 0, "restarting": 1, "paused": 2, "created": 3, "exited": 4, "dead": 5}
+
+var (
+	sortByName    = bulkSort{label: "name", less: func(a, b bulkItem) bool { return strings.ToLower(a.name) < strings.ToLower(b.name) }}
+	sortByCreated = bulkSort{label: "created", desc: true, less: func(a, b bulkItem) bool { return a.created.Before(b.created) }}
+	sortByDriver  = bulkSort{label: "driver", less: func(a, b bulkItem) bool { return a.driver < b.driver }}
+)
+
+// bulkSorts are the orders each kind offers, as the header names them
+var bulkSorts = map[string][]bulkSort{
+	"containers": {
+		sortByName,
+		{label: "status", less: func(a, b bulkItem) bool {
+			rankA, okA := stateRank[a.state]
+			rankB, okB := stateRank[b.state]
+			if !okA {
+				rankA = len(stateRank)
+			}
+			if !okB {
+				rankB = len(stateRank)
+			}
+			return rankA < rankB
+		}},
+		{label: "image", less: func(a, b bulkItem) bool { return a.image < b.image }},
+		sortByCreated,
+	},
+	"images": {
+		{label: "size", desc: true, less: func(a, b bulkItem) bool { return a.size < b.size }},
+		sortByCreated,
+		sortByName,
+	},
+	"volumes":  {sortByName, sortByDriver, sortByCreated},
+	"networks": {sortByName, sortByDriver, sortByCreated},
+}
+
+// bulkSortSteps is how many orders < and > step through for kind: each sort
+// both ways, then back to the order the daemon listed the rows in
+func bulkSortSteps(kind string) int {
+	return 2*len(bulkSorts[kind]) + 1
+}
+
+// currentSort returns the active sort and whether it runs descending; ok is
+// false while the rows are in the daemon's order
+func (m bulkModel) currentSort() (sorting bulkSort, desc, ok bool) {
+	if m.sortStep == 0 {
+		return bulkSort{}, false, false
+	}
+	sorting = bulkSorts[m.kind][(m.sortStep-1)/2]
+	// The second step of each sort reverses its usual direction
+	desc = sorting.desc != ((m.sortStep-1)%2 == 1)
+	return sorting, desc, true
+}
+
+// stepSort moves to the next (or previous, for a negative step) order and
+// puts the rows in it, keeping the cursor on the same row
+func (m *bulkModel) stepSort(step int) {
+	steps := bulkSortSteps(m.kind)
+	if steps == 1 {
+		return
+	}
+	m.sortStep = (m.sortStep + step + steps) % steps
+
+	current := ""
+	if m.cursor < len(m.visible) {
+		current = m.items[m.visible[m.cursor]].id
+	}
+	m.sortItems()
+	m.visible = nil
+	m.applyFilter()
+	for pos, i := range m.visible {
+		if m.items[i].id == current {
+			m.cursor = pos
+		}
+	}
+	m.clampOffset()
+
+	if sorting, desc, ok := m.currentSort(); ok {
+		m.notice = "Sorted by " + sorting.label + " " + sortArrow(desc)
+	} else {
+		m.notice = "Sorted as listed"
+	}
+}
+
+// sortItems puts the rows in the active order; ties, and the rows when no
+// sort is active, keep the order the daemon listed them in
+func (m *bulkModel) sortItems() {
+	sorting, desc, ok := m.currentSort()
+	sort.SliceStable(m.items, func(i, j int) bool {
+		a, b := m.items[i], m.items[j]
+		if ok {
+			if desc {
+				a, b = b, a
+			}
+			if sorting.less(a, b) {
+				return true
+			}
+			if sorting.less(b, a) {
+				return false
+			}
+		}
+		return m.items[i].listed < m.items[j].listed
+	})
+}
+
+func sortArrow(desc bool) string {
+	if desc {
+		return glyphs.sortDown
+	}
+	return glyphs.sortUp
+}
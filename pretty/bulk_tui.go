@@ -0,0 +1,1009 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/events"
+)
+
+type bulkModel struct {
+	kind        string
+	items       []bulkItem
+	visible     []int // indexes into items matching the filter
+	actions     []bulkAction
+	cursor      int // position in visible
+	offset      int
+	width       int
+	height      int
+	chosen      *bulkAction
+	hint        string
+	filtering   bool
+	filterInput textinput.Model
+	notice      string
+	sortStep    int // position in the orders < and > step through; 0 is as listed
+
+	// Renaming the cursor row in place, for kinds that support it
+	rename      func(id, name string) error
+	renaming    bool
+	renameInput textinput.Model
+
+	// Port picker for containers publishing more than one port
+	portChoices []string
+	portCursor  int
+
+	// Signal picker for killing the marked rows, or the cursor row
+	kill        func(id, signal string) error
+	killing     bool
+	killCursor  int
+	killTargets []int // indexes into items
+	customInput textinput.Model
+	customOpen  bool // typing a signal for the last entry
+
+	// Auto-refresh re-lists the rows as the daemon reports changes, or every
+	// interval when there is no events stream, while it is on
+	reload        func() ([]bulkItem, error)
+	watch         func(ctx context.Context) (<-chan events.Message, <-chan error)
+	refresh       time.Duration
+	refreshOn     bool
+	refreshGen    int // bumped when auto-refresh is toggled, retiring old messages
+	eventStream   <-chan events.Message
+	eventErrs     <-chan error
+	stopEvents    context.CancelFunc
+	reloading     bool
+	reloadPending bool // something changed while reloading or a picker was open
+
+	// Usage alerts for containers running hot, sampled every alertEvery;
+	// ! shows them in a panel under the rows
+	sample     func() (map[string]usageSample, error)
+	alertEvery time.Duration
+	alerts     *alertTracker
+	alertsOpen bool
+
+	// Crash loops spotted from die events while the list is live; i shows
+	// the cursor row's last exit in a panel under the rows
+	diagnose   func(id string, withLogs bool) (crashReport, error)
+	crashes    *crashTracker
+	crashPanel *crashPanel
+
+	// ctrl+p searches every command in a palette; switchTo is the dockit
+	// command one of them quit the list for
+	palette  *bulkPalette
+	switchTo []string
+}
+
+// killSignals are the signals the K picker offers; the last entry asks for
+// any other by name or number
+var killSignals = []string{"SIGTERM", "SIGKILL", "SIGHUP", "SIGUSR1", "SIGUSR2", "custom..."}
+
+func newBulkModel(kind string, items []bulkItem, actions []bulkAction, rename func(id, name string) error, kill func(id, signal string) error, refresher bulkRefresher) bulkModel {
+	ti := textinput.New()
+	ti.Placeholder = "name, image, or label"
+	ti.CharLimit = 100
+	ti.Width = 40
+
+	// The rename input is drawn in place of the cursor row's name
+	ri := textinput.New()
+	ri.Prompt = ""
+	ri.CharLimit = 128
+	ri.Width = 40
+
+	ci := textinput.New()
+	ci.Placeholder = "SIGQUIT, QUIT, or 3"
+	ci.CharLimit = 16
+	ci.Width = 20
+
+	m := bulkModel{kind: kind, items: items, actions: actions, filterInput: ti, rename: rename, renameInput: ri, kill: kill, customInput: ci, reload: refresher.reload, watch: refresher.watch}
+	m.refresh = refreshInterval()
+	m.refreshOn = m.reload != nil && m.refresh > 0
+	if refresher.sample != nil {
+		m.alertEvery = alertInterval()
+		if m.alertEvery > 0 {
+			m.sample = refresher.sample
+			m.alerts = newAlertTracker()
+		}
+	}
+	if refresher.diagnose != nil {
+		m.diagnose = refresher.diagnose
+		m.crashes = newCrashTracker()
+	}
+	for i := range m.items {
+		m.items[i].listed = i
+	}
+
+	// Reopen with the order and cursor row the list was last left on
+	state := loadState()
+	if step := state.Sort[kind]; step > 0 && step < bulkSortSteps(kind) {
+		m.sortStep = step
+		m.sortItems()
+	}
+	m.applyFilter()
+	for pos, i := range m.visible {
+		if m.items[i].id == state.Selected[kind] {
+			m.cursor = pos
+		}
+	}
+	return m
+}
+
+// bulkRenamedMsg reports a rename of the item with id to name
+type bulkRenamedMsg struct {
+	id   string
+	name string
+	err  error
+}
+
+// bulkKilledMsg reports sending signal to targets, with the error for each
+// that failed by ID
+type bulkKilledMsg struct {
+	signal  string
+	targets []bulkItem
+	errs    map[string]error
+}
+
+func (m bulkModel) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.refreshOn {
+		gen := m.refreshGen
+		cmds = append(cmds, func() tea.Msg { return bulkWatchMsg{gen: gen} })
+	}
+	if m.sample != nil {
+		cmds = append(cmds, func() tea.Msg { return bulkAlertTickMsg{} })
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m bulkModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	// A change that came in while a picker or the rename was open is
+	// picked up once it closes
+	if m.reloadPending && !m.reloading && !m.rowsPinned() {
+		return m, tea.Batch(cmd, m.reloadNow())
+	}
+	return m, cmd
+}
+
+func (m bulkModel) update(msg tea.Msg) (bulkModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampOffset()
+
+	case bulkRenamedMsg:
+		if msg.err != nil {
+			m.hint = fmt.Sprintf("Could not rename: %v", msg.err)
+			break
+		}
+		index := m.itemIndex(msg.id)
+		if index < 0 {
+			break
+		}
+		item := &m.items[index]
+		m.notice = fmt.Sprintf("Renamed %s to %s", item.name, msg.name)
+		item.search = strings.Replace(item.search, item.name, msg.name, 1)
+		item.name = msg.name
+
+	case bulkKilledMsg:
+		m.reportKill(msg)
+
+	case bulkWatchMsg, bulkEventMsg, bulkEventsErrMsg, bulkRefreshTickMsg, bulkReloadedMsg:
+		return m, m.updateRefresh(msg)
+
+	case bulkAlertTickMsg, bulkSampledMsg:
+		return m, m.updateAlerts(msg)
+
+	case bulkCrashInspectedMsg, bulkCrashDiagnosedMsg:
+		m.updateCrashes(msg)
+
+	case externalDoneMsg:
+		// Back from a view the palette opened, which may have changed things
+		if msg.err != nil {
+			m.hint = fmt.Sprintf("Could not open the view: %v", msg.err)
+		}
+		if m.reload != nil && !m.reloading {
+			return m, m.reloadNow()
+		}
+
+	case tea.KeyMsg:
+		m.hint = ""
+		m.notice = ""
+		if m.palette != nil {
+			return m, m.updatePalette(msg)
+		}
+		if m.filtering {
+			return m, m.updateFilter(msg)
+		}
+		if m.renaming {
+			return m, m.updateRename(msg)
+		}
+		if m.portChoices != nil {
+			m.updatePortPicker(msg)
+			return m, nil
+		}
+		if m.killing {
+			return m, m.updateKillPicker(msg)
+		}
+		if m.crashPanel != nil {
+			m.updateCrashPanel(msg)
+			return m, nil
+		}
+
+		if m.alertsOpen && (msg.String() == "esc" || msg.String() == "!") {
+			m.alertsOpen = false
+			m.clampOffset()
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "ctrl+p":
+			return m, m.openPalette()
+		case "!":
+			if m.alerts == nil {
+				break
+			}
+			m.alertsOpen = true
+		case "i":
+			if len(m.visible) == 0 || m.diagnose == nil {
+				break
+			}
+			cmd := m.openCrashPanel()
+			m.clampOffset()
+			return m, cmd
+		case "/":
+			m.filtering = true
+			m.filterInput.Focus()
+			return m, textinput.Blink
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.visible)-1 {
+				m.cursor++
+			}
+		case " ":
+			if len(m.visible) == 0 {
+				break
+			}
+			item := &m.items[m.visible[m.cursor]]
+			item.selected = !item.selected
+			if m.cursor < len(m.visible)-1 {
+				m.cursor++
+			}
+		case "n", "f2":
+			if len(m.visible) == 0 || m.rename == nil {
+				break
+			}
+			m.renaming = true
+			m.renameInput.SetValue(m.items[m.visible[m.cursor]].name)
+			m.renameInput.CursorEnd()
+			m.renameInput.Focus()
+			return m, textinput.Blink
+		case "y":
+			if len(m.visible) == 0 {
+				break
+			}
+			text := m.items[m.visible[m.cursor]].copyText
+			m.notice = copiedNotice(text)
+			return m, copyToClipboard(text)
+		case "o":
+			if len(m.visible) == 0 || !m.hasPorts() {
+				break
+			}
+			urls := m.items[m.visible[m.cursor]].urls
+			switch len(urls) {
+			case 0:
+				m.hint = "No published TCP ports"
+			case 1:
+				m.open(urls[0])
+			default:
+				m.portChoices = urls
+				m.portCursor = 0
+			}
+		case "K":
+			if len(m.visible) == 0 || m.kill == nil {
+				break
+			}
+			m.killTargets = nil
+			for i, item := range m.items {
+				if item.selected {
+					m.killTargets = append(m.killTargets, i)
+				}
+			}
+			if len(m.killTargets) == 0 {
+				m.killTargets = []int{m.visible[m.cursor]}
+			}
+			m.killing = true
+			m.killCursor = 0
+		case ">":
+			m.stepSort(1)
+			return m, nil
+		case "<":
+			m.stepSort(-1)
+			return m, nil
+		case "A":
+			if m.reload == nil {
+				break
+			}
+			return m, m.toggleRefresh()
+		case "a":
+			// Select all matching rows, or clear them if they're all selected
+			all := true
+			for _, i := range m.visible {
+				all = all && m.items[i].selected
+			}
+			for _, i := range m.visible {
+				m.items[i].selected = !all
+			}
+		default:
+			for i, action := range m.actions {
+				if msg.String() != action.key {
+					continue
+				}
+				if !action.noRows && m.selectedCount() == 0 {
+					m.hint = "Select rows with space first"
+					return m, nil
+				}
+				if action.runPair != nil && m.selectedCount() != 2 {
+					m.hint = fmt.Sprintf("Select exactly two rows to %s", action.verb)
+					return m, nil
+				}
+				m.chosen = &m.actions[i]
+				return m, tea.Quit
+			}
+		}
+		m.clampOffset()
+	}
+
+	return m, nil
+}
+
+// updateRename edits the new name for the cursor row; enter applies it
+func (m *bulkModel) updateRename(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		m.renaming = false
+		m.renameInput.Blur()
+		item := m.items[m.visible[m.cursor]]
+		name := strings.TrimSpace(m.renameInput.Value())
+		if name == "" || name == item.name {
+			return nil
+		}
+		rename := m.rename
+		return func() tea.Msg {
+			return bulkRenamedMsg{id: item.id, name: name, err: rename(item.id, name)}
+		}
+	case "esc":
+		m.renaming = false
+		m.renameInput.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.renameInput, cmd = m.renameInput.Update(msg)
+	return cmd
+}
+
+// updatePortPicker moves through and opens one of the cursor row's ports
+func (m *bulkModel) updatePortPicker(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "up", "k":
+		if m.portCursor > 0 {
+			m.portCursor--
+		}
+	case "down", "j":
+		if m.portCursor < len(m.portChoices)-1 {
+			m.portCursor++
+		}
+	case "enter":
+		m.open(m.portChoices[m.portCursor])
+		m.portChoices = nil
+	case "esc", "q":
+		m.portChoices = nil
+	}
+}
+
+// updateKillPicker picks a signal and sends it to the kill targets; the
+// custom entry opens an input for any other signal
+func (m *bulkModel) updateKillPicker(msg tea.KeyMsg) tea.Cmd {
+	if m.customOpen {
+		switch msg.String() {
+		case "enter":
+			signal := strings.TrimSpace(m.customInput.Value())
+			if signal == "" {
+				return nil
+			}
+			m.customOpen = false
+			m.customInput.Blur()
+			return m.sendSignal(strings.ToUpper(signal))
+		case "esc":
+			m.customOpen = false
+			m.customInput.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		m.customInput, cmd = m.customInput.Update(msg)
+		return cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.killCursor > 0 {
+			m.killCursor--
+		}
+	case "down", "j":
+		if m.killCursor < len(killSignals)-1 {
+			m.killCursor++
+		}
+	case "enter":
+		if m.killCursor == len(killSignals)-1 {
+			m.customOpen = true
+			m.customInput.SetValue("")
+			m.customInput.Focus()
+			return textinput.Blink
+		}
+		return m.sendSignal(killSignals[m.killCursor])
+	case "esc", "q":
+		m.killing = false
+	}
+	return nil
+}
+
+// sendSignal closes the picker and kills the targets in the background
+func (m *bulkModel) sendSignal(signal string) tea.Cmd {
+	m.killing = false
+	m.notice = fmt.Sprintf("Sending %s...", signal)
+	kill := m.kill
+	targets := make([]bulkItem, len(m.killTargets))
+	for n, i := range m.killTargets {
+		targets[n] = m.items[i]
+	}
+	return func() tea.Msg {
+		errs := map[string]error{}
+		for _, target := range targets {
+			if err := kill(target.id, signal); err != nil {
+				errs[target.id] = err
+			}
+		}
+		return bulkKilledMsg{signal: signal, targets: targets, errs: errs}
+	}
+}
+
+// reportKill shows which rows took the signal, or the first error
+func (m *bulkModel) reportKill(msg bulkKilledMsg) {
+	m.notice = ""
+	for _, target := range msg.targets {
+		if err, failed := msg.errs[target.id]; failed {
+			m.hint = fmt.Sprintf("Could not send %s to %s: %v", msg.signal, target.name, err)
+			if len(msg.errs) > 1 {
+				m.hint += fmt.Sprintf(" (and %d more)", len(msg.errs)-1)
+			}
+			return
+		}
+	}
+	target := msg.targets[0].name
+	if len(msg.targets) > 1 {
+		target = fmt.Sprintf("%d containers", len(msg.targets))
+	}
+	m.notice = fmt.Sprintf("Sent %s to %s", msg.signal, target)
+}
+
+// replaceItems swaps in a fresh listing, carrying marks over and keeping
+// the cursor on the same item by ID; when that item is gone, the cursor
+// stays at the same position
+func (m *bulkModel) replaceItems(items []bulkItem) {
+	marked := map[string]bool{}
+	for _, item := range m.items {
+		marked[item.id] = item.selected
+	}
+	current, position := "", m.cursor
+	if m.cursor < len(m.visible) {
+		current = m.items[m.visible[m.cursor]].id
+	}
+
+	for i := range items {
+		items[i].selected = marked[items[i].id]
+		items[i].listed = i
+	}
+	m.items = items
+	m.sortItems()
+	m.visible = nil
+	m.applyFilter()
+
+	m.cursor = min(position, max(len(m.visible)-1, 0))
+	for pos, i := range m.visible {
+		if m.items[i].id == current {
+			m.cursor = pos
+		}
+	}
+	m.clampOffset()
+}
+
+// itemIndex finds an item by ID, or returns -1
+func (m *bulkModel) itemIndex(id string) int {
+	for i, item := range m.items {
+		if item.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *bulkModel) open(url string) {
+	if err := openBrowser(url); err != nil {
+		m.hint = fmt.Sprintf("Could not open browser: %v", err)
+		return
+	}
+	m.notice = "Opened " + url
+}
+
+// hasPorts reports whether the rows show a PORTS column
+func (m bulkModel) hasPorts() bool {
+	for _, item := range m.items {
+		if item.ports != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFilter edits the filter, narrowing the rows as the user types
+func (m *bulkModel) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+		return nil
+	case "esc":
+		m.filtering = false
+		m.filterInput.Blur()
+		m.filterInput.SetValue("")
+		m.applyFilter()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.applyFilter()
+	return cmd
+}
+
+// applyFilter recomputes the matching rows, keeping the cursor on the same
+// item, or the next match after it, when possible
+func (m *bulkModel) applyFilter() {
+	current := -1
+	if m.cursor < len(m.visible) {
+		current = m.visible[m.cursor]
+	}
+
+	needle := strings.ToLower(m.filterInput.Value())
+	m.visible = m.visible[:0]
+	m.cursor = -1
+	for i, item := range m.items {
+		if needle != "" && !strings.Contains(strings.ToLower(item.search), needle) {
+			continue
+		}
+		if m.cursor < 0 && i >= current {
+			m.cursor = len(m.visible)
+		}
+		m.visible = append(m.visible, i)
+	}
+	if m.cursor < 0 {
+		m.cursor = max(0, len(m.visible)-1)
+	}
+	m.clampOffset()
+}
+
+func (m bulkModel) selectedCount() int {
+	n := 0
+	for _, item := range m.items {
+		if item.selected {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *bulkModel) listHeight() int {
+	// Title (2 lines with margin), blank line, status, filter, help, and the
+	// column header, plus the port picker when shown
+	if m.height == 0 {
+		return len(m.items)
+	}
+	reserved := 5 + lipgloss.Height(m.helpText())
+	// The column header
+	reserved++
+	if m.portChoices != nil {
+		reserved += len(m.portChoices) + 2
+	}
+	if m.killing {
+		reserved += len(killSignals) + 2
+	}
+	if m.alertsOpen {
+		reserved += len(m.alertPanelLines()) + 2
+	}
+	if m.crashPanel != nil {
+		reserved += len(m.crashPanelLines()) + 2
+	}
+	if m.palette != nil {
+		reserved += len(m.paletteLines()) + 2
+	}
+	return max(1, m.height-reserved)
+}
+
+// clampOffset scrolls just enough to keep the cursor visible
+func (m *bulkModel) clampOffset() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.visible)-height))
+}
+
+// copyLabel names what y copies from a row of kind
+func copyLabel(kind string) string {
+	switch kind {
+	case "images":
+		return "ref"
+	case "volumes":
+		return "name"
+	}
+	return "ID"
+}
+
+// helpText lists the keys, wrapped to the terminal so it never spills over
+// the rows
+func (m bulkModel) helpText() string {
+	help := []string{"space: select", "a: all/none", "/: filter", "y: copy " + copyLabel(m.kind)}
+	for _, action := range m.actions {
+		help = append(help, action.key+": "+action.verb)
+	}
+	if m.rename != nil {
+		help = append(help, "n/F2: rename")
+	}
+	if m.hasPorts() {
+		help = append(help, "o: open in browser")
+	}
+	if m.kill != nil {
+		help = append(help, "K: send signal")
+	}
+	if m.diagnose != nil {
+		help = append(help, "i: crash info")
+	}
+	if m.alerts != nil {
+		if m.alertsOpen {
+			help = append(help, "!/esc: hide alerts")
+		} else {
+			help = append(help, "!: alerts")
+		}
+	}
+	if m.reload != nil {
+		if m.refreshOn {
+			help = append(help, "A: auto-refresh off")
+		} else {
+			help = append(help, "A: auto-refresh on")
+		}
+	}
+	help = append(help, "</>: sort", "ctrl+p: commands", "q: cancel")
+	switch {
+	case m.renaming:
+		help = []string{"enter: rename", "esc: cancel"}
+	case m.portChoices != nil:
+		help = []string{"enter: open", "esc: back"}
+	case m.customOpen:
+		help = []string{"enter: send", "esc: back"}
+	case m.killing:
+		help = []string{"enter: send", "esc: cancel"}
+	case m.crashPanel != nil:
+		help = []string{glyphs.arrows + ": scroll log", "i/esc: close"}
+	case m.palette != nil:
+		help = []string{"type to search", "up/down: select", "enter: run", "esc: close"}
+	}
+
+	text := strings.Join(help, " | ")
+	if m.width > 0 {
+		text = lipgloss.NewStyle().Width(m.width).Render(text)
+	}
+	return text
+}
+
+// bulkDetailTitles head the detail column of each kind
+var bulkDetailTitles = map[string]string{"containers": "STATUS", "images": "SIZE", "volumes": "DRIVER", "networks": "DRIVER"}
+
+// columnTitles heads the name, ports, and detail columns, with an arrow on
+// the one the rows are sorted by
+func (m bulkModel) columnTitles() []string {
+	titles := []string{"NAME", "PORTS", bulkDetailTitles[m.kind]}
+	if sorting, desc, ok := m.currentSort(); ok {
+		for i, title := range titles {
+			if strings.EqualFold(title, sorting.label) {
+				titles[i] += " " + sortArrow(desc)
+			}
+		}
+	}
+	return titles
+}
+
+// sortShown reports whether the rows are sorted by a column on screen,
+// whose title then carries the arrow
+func (m bulkModel) sortShown() bool {
+	sorting, _, ok := m.currentSort()
+	return ok && (sorting.label == "name" || strings.EqualFold(bulkDetailTitles[m.kind], sorting.label))
+}
+
+// columnWidths lays the name, ports, and detail columns out to the
+// terminal's width, each as wide as the longest visible value up to a cap:
+// ports are narrowed and then dropped first, then the detail, and the name
+// is kept longest. A width is 0 for a column that isn't shown; detail is -1
+// when the width is unknown and it isn't cut.
+func (m bulkModel) columnWidths() (name, ports, detail int) {
+	titles := m.columnTitles()
+	nameWant, portsWant, detailWant := lipgloss.Width(titles[0]), lipgloss.Width(titles[1]), lipgloss.Width(titles[2])
+	for _, i := range m.visible {
+		item := m.items[i]
+		nameWant = max(nameWant, lipgloss.Width(item.name))
+		portsWant = max(portsWant, lipgloss.Width(item.ports))
+		detailWant = max(detailWant, lipgloss.Width(item.detail)+len("  (protected)"))
+	}
+	if m.renaming {
+		// Room to type a longer name
+		nameWant = 40
+	}
+	columns := []column{
+		{width: min(nameWant, 40), min: min(nameWant, 12), priority: 3},
+		{width: min(portsWant, 24), min: 10, priority: 1},
+		{width: min(detailWant, 40), min: 10, priority: 2, fill: true},
+	}
+	if !m.hasPorts() {
+		columns[1] = column{}
+	}
+	if m.width == 0 {
+		return columns[0].width, columns[1].width, -1
+	}
+	// Cursor and checkbox take 6 columns
+	widths := layoutColumns(columns, m.width-6, 2)
+	return widths[0], widths[1], widths[2]
+}
+
+func (m bulkModel) View() string {
+	if small := tooSmallView(m.width, m.height, 30, 8); m.width > 0 && small != "" {
+		return small
+	}
+
+	var sb strings.Builder
+
+	title := strings.ToUpper(m.kind)
+	switch {
+	case m.refreshOn && m.eventStream != nil:
+		title += " (live)"
+	case m.refreshOn:
+		title += fmt.Sprintf(" (every %s)", m.refresh)
+	}
+	sb.WriteString(titleStyle.Render(title))
+	if m.alerts != nil && len(m.alerts.active) > 0 {
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("  %s %d alert(s)", glyphs.warn, len(m.alerts.active))))
+	}
+	now := time.Now()
+	if m.crashes != nil {
+		if n := m.crashes.looping(m.items, now); n > 0 {
+			sb.WriteString(errorStyle.Render(fmt.Sprintf("  %s %d crash looping", glyphs.warn, n)))
+		}
+	}
+	sb.WriteString("\n")
+
+	nameWidth, portsWidth, detailWidth := m.columnWidths()
+	titles := m.columnTitles()
+	header := "      " + fmt.Sprintf("%-*s", nameWidth, titles[0])
+	if portsWidth > 0 {
+		header += fmt.Sprintf("  %-*s", portsWidth, titles[1])
+	}
+	if detailWidth != 0 {
+		header += "  " + titles[2]
+	}
+	if sorting, desc, ok := m.currentSort(); ok && !m.sortShown() {
+		header += fmt.Sprintf("  (by %s %s)", sorting.label, sortArrow(desc))
+	}
+	if m.width > 0 {
+		header = ellipsize(header, m.width)
+	}
+	sb.WriteString(helpStyle.Render(header))
+	sb.WriteString("\n")
+
+	end := min(m.offset+m.listHeight(), len(m.visible))
+	for pos := m.offset; pos < end; pos++ {
+		item := m.items[m.visible[pos]]
+		cursor := "  "
+		if pos == m.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+
+		checkbox := "[ ]"
+		if item.selected {
+			checkbox = selectedStyle.Render("[" + glyphs.ok + "]")
+		}
+
+		name := fmt.Sprintf("%-*s", nameWidth, ellipsize(item.name, nameWidth))
+		if m.renaming && pos == m.cursor {
+			input := m.renameInput
+			input.Width = nameWidth - 1
+			name = input.View()
+			name += strings.Repeat(" ", max(nameWidth-lipgloss.Width(name), 0))
+		}
+		alert := ""
+		if m.alerts != nil {
+			alert = m.alerts.rowAlert(item.id)
+		}
+		alert = joinFlags(m.crashBadge(item, now), alert)
+		if alert != "" && !(m.renaming && pos == m.cursor) {
+			name = errorStyle.Render(name)
+		}
+		line := checkbox + " " + name
+		if portsWidth > 0 {
+			line += fmt.Sprintf("  %-*s", portsWidth, ellipsize(item.ports, portsWidth))
+		}
+		detail := item.detail
+		if item.protected {
+			detail += "  (protected)"
+		}
+		if alert != "" {
+			detail = alert + "  " + detail
+		}
+		if detailWidth > 0 {
+			detail = ellipsize(detail, detailWidth)
+		}
+		switch {
+		case detailWidth == 0:
+		case alert != "":
+			line += errorStyle.Render("  " + detail)
+		default:
+			line += helpStyle.Render("  " + detail)
+		}
+		sb.WriteString(cursor + line + "\n")
+	}
+	if len(m.visible) == 0 {
+		sb.WriteString(helpStyle.Render("  No matches") + "\n")
+	}
+
+	// Port picker overlay
+	if m.portChoices != nil {
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Open which port?"))
+		sb.WriteString("\n")
+		for i, url := range m.portChoices {
+			cursor := "  "
+			if i == m.portCursor {
+				cursor = cursorStyle.Render(glyphs.cursor + " ")
+			}
+			sb.WriteString(cursor + url + "\n")
+		}
+	}
+
+	// Signal picker overlay
+	if m.killing {
+		sb.WriteString("\n")
+		target := m.items[m.killTargets[0]].name
+		if len(m.killTargets) > 1 {
+			target = fmt.Sprintf("%d containers", len(m.killTargets))
+		}
+		sb.WriteString(searchBarStyle.Render("Send which signal to " + target + "?"))
+		sb.WriteString("\n")
+		for i, signal := range killSignals {
+			cursor := "  "
+			if i == m.killCursor {
+				cursor = cursorStyle.Render(glyphs.cursor + " ")
+			}
+			if m.customOpen && i == len(killSignals)-1 {
+				signal = m.customInput.View()
+			}
+			sb.WriteString(cursor + signal + "\n")
+		}
+	}
+
+	// Command palette
+	if m.palette != nil {
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Commands"))
+		sb.WriteString("\n")
+		for _, line := range m.paletteLines() {
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	// Crash diagnostics panel
+	if m.crashPanel != nil {
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Last exit of " + m.crashPanel.name))
+		sb.WriteString("\n")
+		for _, line := range m.crashPanelLines() {
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	// Alerts panel
+	if m.alertsOpen {
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Alerts"))
+		sb.WriteString("\n")
+		for _, line := range m.alertPanelLines() {
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	switch {
+	case m.hint != "":
+		sb.WriteString(errorStyle.Render(m.hint))
+	case m.notice != "":
+		sb.WriteString(selectedStyle.Render(m.notice))
+	default:
+		sb.WriteString(fmt.Sprintf("Selected: %s", selectedStyle.Render(fmt.Sprintf("%d of %d", m.selectedCount(), len(m.items)))))
+	}
+	sb.WriteString("\n")
+
+	// Filter bar while typing, or the active filter and its match count
+	switch {
+	case m.filtering:
+		sb.WriteString(searchBarStyle.Render("Filter: ") + m.filterInput.View())
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("  %d matches", len(m.visible))))
+		sb.WriteString("\n")
+	case m.filterInput.Value() != "":
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("Filter: %q (%d of %d match)", m.filterInput.Value(), len(m.visible), len(m.items))))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(helpStyle.Render(m.helpText()))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// LaunchBulkTUI lets the user mark rows and pick an action to apply to them.
+// The returned action is nil if the user cancelled. A non-nil rename lets n
+// rename the cursor row, a non-nil kill lets K signal the marked rows, and
+// refresher keeps the rows up to date while the list is open.
+func LaunchBulkTUI(kind string, items []bulkItem, actions []bulkAction, rename func(id, name string) error, kill func(id, signal string) error, refresher bulkRefresher) ([]bulkItem, *bulkAction, error) {
+	p := newProgram(newBulkModel(kind, items, actions, rename, kill, refresher), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error running TUI: %v", err)
+	}
+
+	result := final.(bulkModel)
+	if result.stopEvents != nil {
+		result.stopEvents()
+	}
+	updateState(func(state *tuiState) {
+		rememberView(state)
+		if state.Sort == nil {
+			state.Sort = map[string]int{}
+		}
+		state.Sort[kind] = result.sortStep
+		if result.cursor < len(result.visible) {
+			if state.Selected == nil {
+				state.Selected = map[string]string{}
+			}
+			state.Selected[kind] = result.items[result.visible[result.cursor]].id
+		}
+	})
+	if result.switchTo != nil {
+		runSwitch(result.switchTo)
+	}
+	var selected []bulkItem
+	for _, item := range result.items {
+		if item.selected {
+			selected = append(selected, item)
+		}
+	}
+	return selected, result.chosen, nil
+}
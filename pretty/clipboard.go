@@ -0,0 +1,29 @@
+package pretty
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyToClipboard sets the system clipboard through the terminal with an
+// OSC 52 sequence, which also reaches the local clipboard over SSH; tmux
+// only passes it on when wrapped
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+		if os.Getenv("TMUX") != "" {
+			sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+		}
+		os.Stdout.WriteString(sequence)
+		return nil
+	}
+}
+
+// copiedNotice is the status shown after a copy, naming what was copied
+// without spilling a long value across the status line
+func copiedNotice(text string) string {
+	return "Copied " + ellipsize(strings.ReplaceAll(text, "\n", " "), 60)
+}
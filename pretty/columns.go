@@ -0,0 +1,69 @@
+package pretty
+
+import "sort"
+
+// column is one column of a table laid out to the terminal's width
+type column struct {
+	width    int  // what the column wants
+	min      int  // narrowest it is truncated to before being dropped
+	priority int  // higher keeps its width longer; the highest is never dropped
+	fill     bool // takes whatever width is left over
+}
+
+// layoutColumns fits columns into width cells with gap cells between them,
+// returning each one's width, 0 for a dropped column. Columns are narrowed
+// to their minimum from the lowest priority up, then dropped the same way;
+// any room left goes back to them from the highest priority down, and the
+// rest to the fill column.
+func layoutColumns(columns []column, width, gap int) []int {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = c.width
+	}
+	used := func() int {
+		total, shown := 0, 0
+		for _, w := range widths {
+			if w > 0 {
+				total += w
+				shown++
+			}
+		}
+		return total + gap*max(shown-1, 0)
+	}
+
+	order := make([]int, len(columns))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return columns[order[a]].priority < columns[order[b]].priority })
+
+	for _, i := range order {
+		if over := used() - width; over > 0 {
+			widths[i] = max(columns[i].min, widths[i]-over)
+		}
+	}
+	for _, i := range order[:max(len(order)-1, 0)] {
+		if used() <= width {
+			break
+		}
+		widths[i] = 0
+	}
+	if len(order) > 0 {
+		// The most important column is cut short rather than dropped
+		last := order[len(order)-1]
+		widths[last] = max(1, widths[last]-max(used()-width, 0))
+	}
+
+	for j := len(order) - 1; j >= 0; j-- {
+		i := order[j]
+		if spare := width - used(); spare > 0 && widths[i] > 0 {
+			widths[i] = min(columns[i].width, widths[i]+spare)
+		}
+	}
+	for i, c := range columns {
+		if spare := width - used(); c.fill && widths[i] > 0 && spare > 0 {
+			widths[i] += spare
+		}
+	}
+	return widths
+}
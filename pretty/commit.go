@@ -0,0 +1,122 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// commitSelectedContainers is the containers picker's commit action: it asks
+// for an image name for each marked container and commits it
+func commitSelectedContainers(ctx context.Context, cli *client.Client, items []bulkItem) error {
+	reader := bufio.NewReader(os.Stdin)
+	failed := false
+	for _, item := range items {
+		suggested := strings.ToLower(item.name) + ":" + time.Now().Format("20060102-150405")
+		name := suggested
+		for {
+			if answer := prompt(reader, fmt.Sprintf("Commit %s as [%s]: ", item.name, suggested)); answer != "" {
+				name = answer
+			}
+			if _, err := reference.ParseNormalizedNamed(name); err != nil {
+				red.Printf("%s %q is not a valid image name: %v\n", glyphs.failed, name, err)
+				name = suggested
+				continue
+			}
+			break
+		}
+
+		gray.Printf("Committing %s...", item.name)
+		created, err := cli.ContainerCommit(ctx, item.id, container.CommitOptions{
+			Reference: name,
+			Comment:   "Committed from " + item.name + " with dockit",
+			Pause:     true,
+		})
+		fmt.Print("\r\033[K")
+		if err != nil {
+			red.Print(glyphs.failed + " ")
+			fmt.Printf("%s: ", item.name)
+			red.Println(err)
+			failed = true
+			continue
+		}
+
+		green.Print(glyphs.ok + " ")
+		fmt.Printf("Committed %s as %s ", item.name, name)
+		if img, err := cli.ImageInspect(ctx, created.ID); err == nil {
+			gray.Printf("(%s, %s)\n", formatID(created.ID, false), formatSize(img.Size))
+		} else {
+			gray.Printf("(%s)\n", formatID(created.ID, false))
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("some containers were not committed")
+	}
+	return nil
+}
+
+// exportSelectedContainers is the containers picker's export action: it asks
+// where to write each marked container's filesystem and exports it
+func exportSelectedContainers(ctx context.Context, cli *client.Client, items []bulkItem) error {
+	reader := bufio.NewReader(os.Stdin)
+	showProgress := isTerminal(os.Stdout)
+	if showProgress {
+		preparePalette()
+	}
+
+	failed := false
+	for _, item := range items {
+		path := item.name + ".tar"
+		if answer := prompt(reader, fmt.Sprintf("Export %s to [%s]: ", item.name, path)); answer != "" {
+			path = answer
+		}
+		if _, err := os.Stat(path); err == nil && !promptYesNo(reader, path+" exists. Overwrite?") {
+			gray.Println("Skipped")
+			continue
+		}
+
+		written, digest, err := exportContainer(ctx, cli, item.id, item.name, path, showProgress)
+		if err != nil {
+			red.Print(glyphs.failed + " ")
+			fmt.Printf("%s: ", item.name)
+			red.Println(err)
+			failed = true
+			continue
+		}
+		green.Print(glyphs.ok + " ")
+		fmt.Printf("Exported %s to %s ", item.name, path)
+		gray.Printf("(%s)\n", formatSize(written))
+		printRecorded(recordArtifact(path, written, digest, "container export", []string{item.name}))
+	}
+
+	if failed {
+		return fmt.Errorf("some containers were not exported")
+	}
+	return nil
+}
+
+// exportContainer writes a container's flattened filesystem to path; its
+// root filesystem size, when the daemon reports it, sizes the progress bar.
+// It returns the bytes written and their hex SHA256.
+func exportContainer(ctx context.Context, cli *client.Client, id, name, path string, showProgress bool) (int64, string, error) {
+	var total int64
+	if info, _, err := cli.ContainerInspectWithRaw(ctx, id, true); err == nil && info.SizeRootFs != nil {
+		total = *info.SizeRootFs
+	}
+
+	stream, err := cli.ContainerExport(ctx, id)
+	if err != nil {
+		return 0, "", err
+	}
+	defer stream.Close()
+
+	return writeArchive(stream, path, name+" "+glyphs.next+" "+path, total, showProgress)
+}
@@ -0,0 +1,277 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// compareRow is one setting of two containers side by side
+type compareRow struct {
+	label string
+	left  string
+	right string
+}
+
+// compareSection groups rows, like every env variable or every mount
+type compareSection struct {
+	title string
+	rows  []compareRow
+}
+
+// CompareContainers shows two containers' configuration side by side,
+// highlighting what differs
+func CompareContainers(args []string) {
+	diffOnly := false
+	var positional []string
+	for _, arg := range args {
+		switch arg {
+		case "-d", "--diff":
+			diffOnly = true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) != 2 {
+		fmt.Fprintf(os.Stderr, "Error: two containers required\n")
+		fmt.Println("Usage: dockit compare [-d] CONTAINER CONTAINER")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	if err := printComparison(context.Background(), cli, positional[0], positional[1], diffOnly); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printComparison(ctx context.Context, cli *client.Client, a, b string, diffOnly bool) error {
+	left, err := cli.ContainerInspect(ctx, a)
+	if err != nil {
+		return fmt.Errorf("inspecting %s: %v", a, err)
+	}
+	right, err := cli.ContainerInspect(ctx, b)
+	if err != nil {
+		return fmt.Errorf("inspecting %s: %v", b, err)
+	}
+
+	const labelWidth, valueWidth = 20, 34
+
+	fmt.Println()
+	cyan.Printf("%-*s %-*s   %s\n", labelWidth, "COMPARE", valueWidth,
+		ellipsize(strings.TrimPrefix(left.Name, "/"), valueWidth),
+		ellipsize(strings.TrimPrefix(right.Name, "/"), valueWidth))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	differences := 0
+	for _, section := range compareSections(left, right) {
+		var rows []compareRow
+		for _, row := range section.rows {
+			if row.left != row.right {
+				differences++
+			} else if diffOnly {
+				continue
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		fmt.Println()
+		blue.Println(section.title)
+		for _, row := range rows {
+			marker, style := "  ", gray
+			if row.left != row.right {
+				marker, style = yellow.Sprint(glyphs.warn+" "), yellow
+			}
+			fmt.Print(marker)
+			gray.Printf("%-*s ", labelWidth-2, ellipsize(row.label, labelWidth-2))
+			style.Printf("%-*s", valueWidth, ellipsize(orDash(row.left), valueWidth))
+			gray.Print(" " + glyphs.divider + " ")
+			style.Println(ellipsize(orDash(row.right), valueWidth))
+		}
+	}
+
+	fmt.Println()
+	if differences == 0 {
+		green.Println("No differences")
+	} else {
+		yellow.Printf("%d difference(s)\n", differences)
+	}
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// compareSections lines up the settings that usually explain why two
+// containers behave differently
+func compareSections(left, right container.InspectResponse) []compareSection {
+	for _, info := range []*container.InspectResponse{&left, &right} {
+		if info.Config == nil {
+			info.Config = &container.Config{}
+		}
+		if info.HostConfig == nil {
+			info.HostConfig = &container.HostConfig{}
+		}
+		if info.State == nil {
+			info.State = &container.State{}
+		}
+	}
+
+	general := compareSection{title: "General", rows: []compareRow{
+		{"Image", left.Config.Image, right.Config.Image},
+		{"Image ID", formatID(left.Image, false), formatID(right.Image, false)},
+		{"Entrypoint", strings.Join(left.Config.Entrypoint, " "), strings.Join(right.Config.Entrypoint, " ")},
+		{"Command", strings.Join(left.Config.Cmd, " "), strings.Join(right.Config.Cmd, " ")},
+		{"User", left.Config.User, right.Config.User},
+		{"Working dir", left.Config.WorkingDir, right.Config.WorkingDir},
+		{"State", left.State.Status, right.State.Status},
+		{"Restart policy", string(left.HostConfig.RestartPolicy.Name), string(right.HostConfig.RestartPolicy.Name)},
+		{"Network mode", string(left.HostConfig.NetworkMode), string(right.HostConfig.NetworkMode)},
+	}}
+
+	limits := compareSection{title: "Limits", rows: []compareRow{
+		{"Memory", formatLimit(left.HostConfig.Memory), formatLimit(right.HostConfig.Memory)},
+		{"CPUs", formatCPUs(left.HostConfig.NanoCPUs), formatCPUs(right.HostConfig.NanoCPUs)},
+		{"CPU shares", formatCount(left.HostConfig.CPUShares), formatCount(right.HostConfig.CPUShares)},
+		{"PIDs", formatPidsLimit(left.HostConfig.PidsLimit), formatPidsLimit(right.HostConfig.PidsLimit)},
+	}}
+
+	return []compareSection{
+		general,
+		{title: "Environment", rows: compareMaps(envMap(left.Config.Env), envMap(right.Config.Env))},
+		{title: "Mounts", rows: compareMaps(mountMap(left.Mounts), mountMap(right.Mounts))},
+		{title: "Ports", rows: compareMaps(portMap(left.HostConfig.PortBindings), portMap(right.HostConfig.PortBindings))},
+		limits,
+		{title: "Networks", rows: compareMaps(networkMap(left), networkMap(right))},
+		{title: "Labels", rows: compareMaps(left.Config.Labels, right.Config.Labels)},
+	}
+}
+
+// compareMaps makes a row for every key in either map, sorted by key
+func compareMaps(left, right map[string]string) []compareRow {
+	keys := map[string]bool{}
+	for key := range left {
+		keys[key] = true
+	}
+	for key := range right {
+		keys[key] = true
+	}
+
+	var rows []compareRow
+	for key := range keys {
+		rows = append(rows, compareRow{label: key, left: left[key], right: right[key]})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].label < rows[j].label })
+	return rows
+}
+
+func envMap(env []string) map[string]string {
+	m := map[string]string{}
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		m[key] = value
+	}
+	return m
+}
+
+// mountMap keys mounts by their path in the container
+func mountMap(mounts []container.MountPoint) map[string]string {
+	m := map[string]string{}
+	for _, mount := range mounts {
+		source := mount.Source
+		if mount.Name != "" {
+			source = mount.Name
+		}
+		if !mount.RW {
+			source += " (ro)"
+		}
+		m[mount.Destination] = source
+	}
+	return m
+}
+
+// portMap keys published ports by container port
+func portMap(bindings map[nat.Port][]nat.PortBinding) map[string]string {
+	m := map[string]string{}
+	for port, hosts := range bindings {
+		var published []string
+		for _, host := range hosts {
+			address := host.HostPort
+			if host.HostIP != "" {
+				address = host.HostIP + ":" + host.HostPort
+			}
+			published = append(published, address)
+		}
+		sort.Strings(published)
+		m[string(port)] = strings.Join(published, ", ")
+	}
+	return m
+}
+
+func networkMap(info container.InspectResponse) map[string]string {
+	m := map[string]string{}
+	if info.NetworkSettings == nil {
+		return m
+	}
+	for name, endpoint := range info.NetworkSettings.Networks {
+		// Every container gets its short ID as an alias, which always differs
+		var aliases []string
+		for _, alias := range endpoint.Aliases {
+			if !strings.HasPrefix(info.ID, alias) {
+				aliases = append(aliases, alias)
+			}
+		}
+		m[name] = strings.Join(aliases, ", ")
+		if m[name] == "" {
+			m[name] = "connected"
+		}
+	}
+	return m
+}
+
+func formatLimit(bytes int64) string {
+	if bytes == 0 {
+		return "unlimited"
+	}
+	return formatSize(bytes)
+}
+
+func formatCPUs(nano int64) string {
+	if nano == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%g", float64(nano)/1e9)
+}
+
+func formatCount(n int64) string {
+	if n == 0 {
+		return "default"
+	}
+	return fmt.Sprint(n)
+}
+
+func formatPidsLimit(limit *int64) string {
+	if limit == nil || *limit <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(*limit)
+}
@@ -0,0 +1,444 @@
+package pretty
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds user settings loaded from ~/.config/dockit/config.yaml
+type Config struct {
+	Theme             ThemeConfig         `yaml:"theme"`
+	Colors            map[string]string   `yaml:"colors"`
+	Keys              map[string][]string `yaml:"keys"`
+	Defaults          DefaultsConfig      `yaml:"defaults"`
+	TrustedRegistries []string            `yaml:"trusted_registries"`
+	Render            string              `yaml:"render"`
+	Protect           []string            `yaml:"protect"`
+	SecretEnv         []string            `yaml:"secret_env"`
+	Viewer            ViewerConfig        `yaml:"viewer"`
+	Profiles          map[string][]string `yaml:"profiles"`
+	Time              TimeConfig          `yaml:"time"`
+	MetadataRepo      string              `yaml:"metadata_repo"`
+	LogDefaults       []LogRule           `yaml:"log_defaults"`
+	LogLevels         map[string]string   `yaml:"log_levels"`
+	Watch             []WatchRule         `yaml:"watch"`
+	Alerts            AlertsConfig        `yaml:"alerts"`
+}
+
+// ThemeConfig overrides the hex colors used by the interactive TUIs
+type ThemeConfig struct {
+	Background  string `yaml:"background"`
+	Accent      string `yaml:"accent"`
+	Highlight   string `yaml:"highlight"`
+	Muted       string `yaml:"muted"`
+	StatusBarBg string `yaml:"status_bar_bg"`
+	StatusBarFg string `yaml:"status_bar_fg"`
+}
+
+// ViewerConfig sets the external programs used to open logs and inspect output
+type ViewerConfig struct {
+	Pager  string `yaml:"pager"`
+	Editor string `yaml:"editor"`
+}
+
+// TimeConfig sets how dates and times are shown
+type TimeConfig struct {
+	Style string `yaml:"style"` // "relative" or "absolute"
+	Clock string `yaml:"clock"` // "auto", "24h", or "12h"
+	Date  string `yaml:"date"`  // "auto", "ymd", "dmy", or "mdy"
+}
+
+// LogRule sets the log viewers' defaults for containers with a label
+type LogRule struct {
+	Label  string `yaml:"label"` // KEY=VALUE, or KEY for any value
+	Tail   string `yaml:"tail"`
+	Follow *bool  `yaml:"follow"`
+}
+
+// WatchRule makes the logs viewer ring the bell when a container logs a
+// matching line while it is out of view
+type WatchRule struct {
+	Container string `yaml:"container"` // name, or a glob like api-*
+	Pattern   string `yaml:"pattern"`   // regular expression
+}
+
+// AlertsConfig sets when the containers picker flags a container's usage
+type AlertsConfig struct {
+	CPU      float64 `yaml:"cpu"`      // percent of one core, like docker stats
+	Memory   float64 `yaml:"memory"`   // percent of the memory limit
+	Samples  int     `yaml:"samples"`  // samples in a row over a threshold before alerting
+	Interval string  `yaml:"interval"` // time between samples, or "off"
+}
+
+// DefaultsConfig sets default flags for commands
+type DefaultsConfig struct {
+	ShowAll         bool   `yaml:"show_all"`
+	FullIDs         bool   `yaml:"full_ids"`
+	LogTail         string `yaml:"log_tail"`
+	LogFollow       bool   `yaml:"log_follow"`
+	LogLineNumbers  bool   `yaml:"log_line_numbers"`
+	DefaultCommand  string `yaml:"default_command"`
+	NameTemplate    string `yaml:"name_template"`
+	StopTimeout     string `yaml:"stop_timeout"`
+	RefreshInterval string `yaml:"refresh_interval"`
+}
+
+// config is the active configuration, populated by LoadConfig
+var config = Config{
+	Defaults: DefaultsConfig{LogTail: "100"},
+}
+
+// colorNames maps config color names to terminal colors
+var colorNames = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+	"gray":    color.FgHiBlack,
+}
+
+const configTemplate = `# Dockit configuration
+# Generated by 'dockit config init'. Uncomment and edit the settings you want to change.
+
+# Colors used by the interactive TUIs (hex values)
+theme:
+  # background: auto          # "dark" or "light" palette; auto asks the terminal
+  # accent: "#00d7ff"         # titles and cursor
+  # highlight: "#ffff00"      # search matches
+  # muted: "#626262"          # help text
+  # status_bar_bg: "#3a3a3a"
+  # status_bar_fg: "#ffffff"
+
+# Colors used by pretty list output
+# (black, red, green, yellow, blue, magenta, cyan, white, gray)
+colors:
+  # header: cyan
+  # name: blue
+  # running: green
+  # muted: gray              # stopped containers, dividers, and secondary text
+  # paused: yellow
+  # error: red
+
+# Key bindings for the logs TUI; each action takes a list of keys
+keys:
+  # quit: ["q", "ctrl+c"]
+  # search: ["/"]
+  # next_match: ["n"]
+  # prev_match: ["N"]
+  # pause: [" "]
+  # follow: ["f"]
+  # json: ["J"]
+  # open_tab: ["o"]
+  # close_tab: ["x"]
+  # next_tab: ["tab"]
+  # prev_tab: ["shift+tab"]
+  # crash_cause: ["!"]
+  # view_pager: ["v"]
+  # view_editor: ["e"]
+  # view_inspect: ["i"]
+  # switch_context: ["c"]
+  # api_trace: ["ctrl+t"]
+  # line_numbers: ["#"]
+  # copy_reference: ["r"]
+  # copy_line: ["y"]
+  # go_to: [":"]
+  # log_events: ["E"]
+  # timestamps: ["t"]
+  # stderr_only: ["s"]
+  # watch_pattern: ["w"]
+  # notifications: ["W"]
+  # up: ["up", "k"]
+  # down: ["down", "j"]
+  # page_up: ["pgup"]
+  # page_down: ["pgdown"]
+  # top: ["home", "g"]
+  # bottom: ["end", "G"]
+
+defaults:
+  # show_all: false           # dockit ps behaves like dockit ps -a
+  # full_ids: false           # show full IDs everywhere, like --no-trunc
+  # log_tail: "100"           # lines of history loaded by dockit logs ("all" for everything)
+  # log_follow: false         # dockit logs behaves like dockit logs -f
+  # log_line_numbers: false   # start dockit logs with line numbers shown
+  # default_command: ""       # command to run when dockit is called with no arguments, e.g. "ps" or "dashboard"
+  # name_template: "{{.Image}}{{if gt .N 1}}-{{.N}}{{end}}"   # names run --wizard suggests; .Image, .Tag, and .N (1, 2, ... until free)
+  # refresh_interval: 5s      # how often the -i pickers list their rows again; "off" starts them with auto-refresh off (A toggles it)
+  # stop_timeout: 10s         # how long stops and restarts wait before killing a container with no --stop-timeout of its own
+
+# Log defaults for containers matching a label (KEY=VALUE, or KEY for any
+# value); the first matching rule wins, and -f or --tail still override it.
+# Used by dockit logs and the quick view.
+log_defaults:
+  # - label: app=gateway
+  #   tail: "1000"
+  #   follow: true
+
+# Regular expressions that give plain log lines their level in the quick
+# view's log tail, which colors errors red, warnings yellow, and debug lines
+# dim (L filters by level); JSON lines use their level field. Tried from
+# error down; setting one keeps the defaults for the rest, "" turns it off.
+log_levels:
+  # error: '(?i)\b(error|err|fatal|panic|critical|crit)\b'
+  # warn: '(?i)\b(warn|warning)\b'
+  # info: '(?i)\b(info|notice)\b'
+  # debug: '(?i)\b(debug|trace)\b'
+
+# Patterns to watch for in dockit logs: a matching line from a container
+# that isn't in view rings the terminal bell and goes in the W list, from
+# which enter jumps to it. Add more for the session with w.
+watch:
+  # - container: api-*
+  #   pattern: "panic|FATAL"
+
+# Usage alerts in the dockit ps -i picker: a running container over a
+# threshold for this many samples in a row turns red, and ! lists the
+# alerts, current and past
+alerts:
+  # cpu: 90                   # percent of one core, like docker stats
+  # memory: 90                # percent of the memory limit
+  # samples: 3
+  # interval: 5s              # "off" stops sampling
+
+# Dates and times: "relative" ("3 hours ago") or "absolute" created times and
+# details; log and event timestamps are always absolute. The clock and date
+# order default to your locale.
+time:
+  # style: relative
+  # clock: auto               # "24h" or "12h"
+  # date: auto                # "ymd" (2024-03-01), "dmy" (01/03/2024), or "mdy" (03/01/2024)
+
+# Icons and borders: "auto" detects the terminal, "ascii" avoids Unicode glyphs
+# (useful on the legacy Windows console), "unicode" always uses them
+# render: auto
+
+# External programs for the logs TUI's v (pager), e (editor), and i (inspect)
+# keys. Defaults to $PAGER and $EDITOR. Data is passed as a temp file path,
+# or on stdin when the command ends with "-".
+viewer:
+  # pager: "less -R"
+  # editor: "code --wait"     # or "code -" to pipe into a new editor tab
+
+# Name patterns (glob) of containers, volumes, and networks that prune never
+# removes; resources labeled dockit.keep=true are always protected
+protect:
+  # - "postgres-*"
+  # - "*-cache"
+
+# Environment variables whose names contain one of these (any case) have their
+# values shown as ******** by 'dockit inspect' and the quick view; setting the
+# list replaces the defaults below
+secret_env:
+  # - PASSWORD
+  # - PASSWD
+  # - SECRET
+  # - TOKEN
+  # - KEY
+  # - CREDENTIAL
+
+# Start profiles: 'dockit profile start NAME' starts the containers in order,
+# waiting for each to be healthy (or running, without a healthcheck) before
+# the next; 'dockit profile stop NAME' stops them in reverse
+profiles:
+  # backend-stack: [db, cache, api, worker]
+
+# Registries/namespaces considered trusted; containers using other images are flagged
+trusted_registries:
+  # - docker.io/library
+  # - ghcr.io/acme
+
+# A git checkout 'dockit meta sync' shares protect patterns, profiles, and
+# trusted registries through, so a team sees the same curation of a host
+# metadata_repo: ~/src/team-dockit
+`
+
+// ConfigPath returns the location of the config file
+func ConfigPath() string {
+	if path := os.Getenv("DOCKIT_CONFIG"); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "dockit", "config.yaml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "dockit", "config.yaml")
+}
+
+// LoadConfig reads the config file, if present, and applies it. A missing
+// file is not an error.
+func LoadConfig() error {
+	// The render profile also depends on the terminal, so apply it even
+	// without a file. The palette waits for a TUI to start, since asking the
+	// terminal for its background costs a round trip.
+	defer func() {
+		applyRenderProfile()
+		applyTheme()
+	}()
+
+	path := ConfigPath()
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return loadMetadataStore()
+	}
+	if err != nil {
+		return fmt.Errorf("error reading config: %v", err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	if config.Defaults.LogTail == "" {
+		config.Defaults.LogTail = "100"
+	}
+	if _, err := parseRefreshInterval(config.Defaults.RefreshInterval); err != nil {
+		return fmt.Errorf("error in %s: refresh_interval: %v", path, err)
+	}
+	if err := compileLevelPatterns(); err != nil {
+		return fmt.Errorf("error in %s: log_levels: %v", path, err)
+	}
+	if _, err := parseRefreshInterval(config.Alerts.Interval); err != nil {
+		return fmt.Errorf("error in %s: alerts.interval: %v", path, err)
+	}
+	if config.Defaults.StopTimeout != "" {
+		if _, err := parseStopTimeout(config.Defaults.StopTimeout); err != nil {
+			return fmt.Errorf("error in %s: stop_timeout: %v", path, err)
+		}
+	}
+
+	return loadMetadataStore()
+}
+
+// defaultRefreshInterval is how often list views refresh when the config
+// doesn't say
+const defaultRefreshInterval = 5 * time.Second
+
+// refreshInterval is how often list views list their rows again, or 0 when
+// refresh_interval turns auto-refresh off
+func refreshInterval() time.Duration {
+	interval, err := parseRefreshInterval(config.Defaults.RefreshInterval)
+	if err != nil {
+		return defaultRefreshInterval
+	}
+	return interval
+}
+
+// parseRefreshInterval reads refresh_interval: a duration of at least a
+// second, or "off" or 0 for no auto-refresh
+func parseRefreshInterval(text string) (time.Duration, error) {
+	switch text {
+	case "":
+		return defaultRefreshInterval, nil
+	case "off", "0":
+		return 0, nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("invalid interval %q (use a duration like 5s, or off)", text)
+	}
+	return d, nil
+}
+
+// DefaultCommand returns the configured command to run when dockit has no arguments
+func DefaultCommand() string {
+	return config.Defaults.DefaultCommand
+}
+
+// RunConfig handles the dockit config subcommands (init, path)
+func RunConfig(args []string) {
+	switch args[0] {
+	case "init":
+		force := len(args) > 1 && (args[1] == "-f" || args[1] == "--force")
+		initConfig(force)
+	case "path":
+		fmt.Println(ConfigPath())
+	}
+}
+
+func initConfig(force bool) {
+	path := ConfigPath()
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Error: could not determine config location\n")
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite)\n", path)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating config directory: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, []byte(configTemplate), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
+		os.Exit(1)
+	}
+
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Wrote %s\n", path)
+}
+
+// applyTheme updates the package styles from the loaded config
+func applyTheme() {
+	theme := config.Theme
+	if theme.Accent != "" {
+		titleStyle = titleStyle.Foreground(lipgloss.Color(theme.Accent))
+		cursorStyle = cursorStyle.Foreground(lipgloss.Color(theme.Accent))
+		activeTabStyle = activeTabStyle.Background(lipgloss.Color(theme.Accent))
+		progressFillStyle = progressFillStyle.Foreground(lipgloss.Color(theme.Accent))
+	}
+	if theme.Highlight != "" {
+		highlightStyle = highlightStyle.Background(lipgloss.Color(theme.Highlight))
+		searchBarStyle = searchBarStyle.Background(lipgloss.Color(theme.Highlight))
+	}
+	if theme.Muted != "" {
+		helpStyle = helpStyle.Foreground(lipgloss.Color(theme.Muted))
+	}
+	if theme.StatusBarBg != "" {
+		statusBarStyle = statusBarStyle.Background(lipgloss.Color(theme.StatusBarBg))
+	}
+	if theme.StatusBarFg != "" {
+		statusBarStyle = statusBarStyle.Foreground(lipgloss.Color(theme.StatusBarFg))
+	}
+
+	for name, target := range map[string]**color.Color{
+		"header":  &cyan,
+		"name":    &blue,
+		"running": &green,
+		"paused":  &yellow,
+		"error":   &red,
+	} {
+		if attr, ok := colorNames[config.Colors[name]]; ok {
+			*target = color.New(attr, color.Bold)
+		}
+	}
+	if attr, ok := colorNames[config.Colors["muted"]]; ok {
+		gray = color.New(attr)
+	}
+}
+
+// keyBinding returns the binding for a logs TUI action, honoring config overrides
+func keyBinding(action string, defaults ...string) key.Binding {
+	if keys, ok := config.Keys[action]; ok && len(keys) > 0 {
+		return key.NewBinding(key.WithKeys(keys...))
+	}
+	return key.NewBinding(key.WithKeys(defaults...))
+}
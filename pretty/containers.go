@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/fatih/color"
 )
@@ -22,7 +24,7 @@ var (
 
 // PrintContainers displays containers in a pretty format
 func PrintContainers(args []string) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := newClient()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
 		os.Exit(1)
@@ -31,21 +33,83 @@ func PrintContainers(args []string) {
 
 	ctx := context.Background()
 
-	// Check if -a flag is present for showing all containers
-	showAll := false
-	for _, arg := range args {
-		if arg == "-a" || arg == "--all" {
+	format, args, err := parseOutputFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Parse flags; filters are handed to the Docker API as-is, like docker ps
+	showAll := config.Defaults.ShowAll
+	fullIDs := config.Defaults.FullIDs
+	filterArgs := filters.NewArgs()
+	sortBy := ""
+	interactive := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-a" || arg == "--all":
 			showAll = true
-			break
+		case arg == "-i" || arg == "--interactive":
+			interactive = true
+		case arg == "--no-trunc":
+			fullIDs = true
+		case (arg == "-f" || arg == "--filter") && i+1 < len(args):
+			i++
+			if err := addFilter(filterArgs, args[i]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--filter="):
+			if err := addFilter(filterArgs, strings.TrimPrefix(arg, "--filter=")); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case arg == "--sort" && i+1 < len(args):
+			i++
+			sortBy = args[i]
+		case strings.HasPrefix(arg, "--sort="):
+			sortBy = strings.TrimPrefix(arg, "--sort=")
 		}
 	}
 
-	containers, err := cli.ContainerList(ctx, container.ListOptions{All: showAll})
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: showAll, Filters: filterArgs})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
 		os.Exit(1)
 	}
 
+	if err := sortContainers(containers, sortBy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if interactive {
+		host := browserHost(cli.DaemonHost())
+		reload := func() ([]bulkItem, error) {
+			containers, err := cli.ContainerList(ctx, container.ListOptions{All: showAll, Filters: filterArgs})
+			if err != nil {
+				return nil, err
+			}
+			// sortBy was checked when the list was first sorted
+			sortContainers(containers, sortBy)
+			return containerBulkItems(containers, host), nil
+		}
+		runBulk(ctx, cli, "containers", containerBulkItems(containers, host), containerActions, reload)
+		return
+	}
+
+	if format.enabled() {
+		allowlist := trustedRegistries()
+		times := newContainerTimesCache(cli)
+		records := []containerRecord{}
+		for _, c := range containers {
+			records = append(records, newContainerRecord(ctx, c, allowlist, times))
+		}
+		printRecords(format, records)
+		return
+	}
+
 	if len(containers) == 0 {
 		gray.Println("No containers found")
 		if !showAll {
@@ -54,37 +118,34 @@ func PrintContainers(args []string) {
 		return
 	}
 
+	renderContainers(ctx, cli, containers, fullIDs)
+}
+
+// renderContainers prints the pretty container list
+func renderContainers(ctx context.Context, cli *client.Client, containers []container.Summary, fullIDs bool) {
 	// Print header
 	fmt.Println()
-	cyan.Println("CONTAINERS")
-	cyan.Println(strings.Repeat("─", 90))
+	if name := currentContextName(); name != "default" {
+		cyan.Printf("CONTAINERS (context: %s)\n", name)
+	} else {
+		cyan.Println("CONTAINERS")
+	}
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	allowlist := trustedRegistries()
+
+	// Stale detection is best effort; skip it if images can't be listed
+	images, imagesErr := loadImageIndex(ctx, cli)
+	times := newContainerTimesCache(cli)
 
 	// Print containers
 	for _, c := range containers {
 		// Status indicator and color
-		var statusColor *color.Color
-		var indicator string
-		if c.State == "running" {
-			statusColor = green
-			indicator = "●"
-		} else if c.State == "exited" {
-			statusColor = gray
-			indicator = "○"
-		} else if c.State == "paused" {
-			statusColor = yellow
-			indicator = "⏸"
-		} else {
-			statusColor = red
-			indicator = "✖"
-		}
+		statusColor, indicator := stateStyle(c.State)
 
-		// Container ID (short)
-		containerID := c.ID
-		if len(containerID) > 12 {
-			containerID = containerID[:12]
-		}
-		idWidth := 12
-		idPadded := containerID + strings.Repeat(" ", idWidth-len(containerID))
+		// Container ID (short unless --no-trunc)
+		containerID := formatID(c.ID, fullIDs)
+		idPadded := containerID + strings.Repeat(" ", max(0, idWidth(fullIDs)-len(containerID)))
 
 		// Container name
 		name := strings.TrimPrefix(c.Names[0], "/")
@@ -110,22 +171,44 @@ func PrintContainers(args []string) {
 		statusColor.Print(indicator)
 		fmt.Print(" ")
 		gray.Print(idPadded)
-		gray.Print(" │ ")
+		gray.Print(" " + glyphs.divider + " ")
 		blue.Print(namePadded)
-		gray.Print(" │ ")
+		gray.Print(" " + glyphs.divider + " ")
 		statusColor.Print(statePadded)
-		gray.Print("│ ")
+		gray.Print(glyphs.divider + " ")
 		fmt.Println(imagePadded)
 
+		// Healthcheck status
+		if health := healthFromStatus(c.Status); health != "" {
+			gray.Printf("  %s Health: ", glyphs.detail)
+			healthColor(health).Println(health)
+		}
+
 		// Ports
 		ports := formatPorts(c.Ports)
 		if ports != "" {
-			gray.Printf("  ↪ Ports: %s\n", ports)
+			gray.Printf("  %s Ports: %s\n", glyphs.detail, ports)
 		}
 
-		// Status/uptime
-		status := c.Status
-		gray.Printf("  ⏱ %s\n", status)
+		// Kept by prune and other cleanup flows
+		if isProtected(c.Names[0], c.Labels) {
+			gray.Printf("  %s Protected\n", glyphs.detail)
+		}
+
+		// Trusted registry allowlist
+		if !isTrustedImage(c.Image, allowlist) {
+			yellow.Printf("  %s Untrusted image source: %s\n", glyphs.warn, c.Image)
+		}
+
+		// Running on an image its tag no longer points to
+		if imagesErr == nil {
+			if reason := images.staleReason(ctx, cli, c); reason != "" {
+				yellow.Printf("  %s Stale: %s ", glyphs.warn, reason)
+				gray.Printf("(dockit recreate %s)\n", strings.TrimPrefix(c.Names[0], "/"))
+			}
+		}
+
+		printContainerTimes(ctx, times, c)
 
 		fmt.Println()
 	}
@@ -144,6 +227,136 @@ func PrintContainers(args []string) {
 	fmt.Println()
 }
 
+// printContainerTimes shows how long a container has been up since it last
+// started, or when it exited and with what code, falling back to the
+// daemon's status sentence when it can't be inspected
+func printContainerTimes(ctx context.Context, cache *containerTimesCache, c container.Summary) {
+	times, ok := cache.get(ctx, c.ID)
+	switch {
+	case ok && (c.State == "running" || c.State == "paused") && !times.started.IsZero():
+		gray.Printf("  %s %s\n", glyphs.clock, describeUptime(c.State, times))
+	case ok && (c.State == "exited" || c.State == "dead") && !times.finished.IsZero():
+		gray.Printf("  %s Exited %s ", glyphs.clock, formatTime(times.finished))
+		codeColor := gray
+		if times.exitCode != 0 {
+			codeColor = red
+		}
+		codeColor.Printf("(code %d)", times.exitCode)
+		if times.oomKilled {
+			red.Print(" out of memory")
+		}
+		if !times.started.IsZero() && times.finished.After(times.started) {
+			gray.Printf(", ran %s", formatUptime(times.finished.Sub(times.started)))
+		}
+		fmt.Println()
+	default:
+		gray.Printf("  %s %s\n", glyphs.clock, c.Status)
+	}
+}
+
+// healthColor returns the color used for a healthcheck status
+func healthColor(health string) *color.Color {
+	switch health {
+	case "healthy":
+		return green
+	case "unhealthy":
+		return red
+	default:
+		return yellow
+	}
+}
+
+// addFilter adds a KEY=VALUE filter, as given to --filter, to args
+func addFilter(args filters.Args, filter string) error {
+	key, value, found := strings.Cut(filter, "=")
+	if !found || key == "" {
+		return fmt.Errorf("bad format of filter (expected name=value): %s", filter)
+	}
+	args.Add(strings.ToLower(strings.TrimSpace(key)), value)
+	return nil
+}
+
+// sortContainers orders containers by name, created (newest first), status, or image
+func sortContainers(containers []container.Summary, by string) error {
+	name := func(c container.Summary) string {
+		if len(c.Names) == 0 {
+			return c.ID
+		}
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	var less func(a, b container.Summary) bool
+	switch by {
+	case "":
+		return nil
+	case "name":
+		less = func(a, b container.Summary) bool { return name(a) < name(b) }
+	case "created":
+		less = func(a, b container.Summary) bool { return a.Created > b.Created }
+	case "status", "state":
+		less = func(a, b container.Summary) bool {
+			if a.State != b.State {
+				return a.State < b.State
+			}
+			return name(a) < name(b)
+		}
+	case "image":
+		less = func(a, b container.Summary) bool {
+			if a.Image != b.Image {
+				return a.Image < b.Image
+			}
+			return name(a) < name(b)
+		}
+	default:
+		return fmt.Errorf("unknown sort key %q (expected name, created, status, or image)", by)
+	}
+
+	sort.SliceStable(containers, func(i, j int) bool { return less(containers[i], containers[j]) })
+	return nil
+}
+
+// stateStyle returns the color and indicator used for a container state
+func stateStyle(state string) (*color.Color, string) {
+	switch state {
+	case "running":
+		return green, glyphs.running
+	case "exited":
+		return gray, glyphs.stopped
+	case "paused":
+		return yellow, glyphs.paused
+	default:
+		return red, glyphs.failed
+	}
+}
+
+// ellipsize shortens text to width, marking the cut with "..."
+func ellipsize(text string, width int) string {
+	if len(text) > width {
+		return text[:width-3] + "..."
+	}
+	return text
+}
+
+// formatID shortens a container or image ID to 12 characters unless full IDs are requested
+func formatID(id string, full bool) string {
+	if full {
+		return id
+	}
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+// idWidth returns the column width needed for IDs
+func idWidth(full bool) int {
+	if full {
+		return 71 // "sha256:" + 64 hex characters
+	}
+	return 12
+}
+
 func formatPorts(ports []container.Port) string {
 	if len(ports) == 0 {
 		return ""
@@ -0,0 +1,295 @@
+package pretty
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// countingReader counts the bytes read through it so progress can be drawn
+// from another goroutine
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// CopyFiles copies between a container and the host like docker cp, drawing
+// a progress bar while the archive streams
+func CopyFiles(args []string) {
+	quiet := false
+	var positional []string
+	for _, arg := range args {
+		switch {
+		case arg == "-q" || arg == "--quiet":
+			quiet = true
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			os.Exit(1)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) != 2 {
+		fmt.Fprintf(os.Stderr, "Error: source and destination required\n")
+		fmt.Println("Usage: dockit cp [-q] CONTAINER:SRC_PATH DEST_PATH")
+		fmt.Println("       dockit cp [-q] SRC_PATH CONTAINER:DEST_PATH")
+		os.Exit(1)
+	}
+
+	srcContainer, srcPath, srcRemote := splitCopyArg(positional[0])
+	dstContainer, dstPath, dstRemote := splitCopyArg(positional[1])
+	if srcRemote == dstRemote {
+		fmt.Fprintf(os.Stderr, "Error: exactly one of source and destination must be CONTAINER:PATH\n")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	showProgress := !quiet && isTerminal(os.Stdout)
+	if showProgress {
+		preparePalette()
+	}
+
+	var label string
+	var copied int64
+	if srcRemote {
+		label = positional[0] + " " + glyphs.next + " " + dstPath
+		copied, err = copyFromContainer(ctx, cli, srcContainer, srcPath, dstPath, progressPrinter(label, showProgress))
+	} else {
+		label = srcPath + " " + glyphs.next + " " + positional[1]
+		copied, err = copyToContainer(ctx, cli, dstContainer, srcPath, dstPath, progressPrinter(label, showProgress))
+	}
+	if showProgress {
+		fmt.Print("\r\033[K")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error copying %s: %v\n", label, err)
+		os.Exit(1)
+	}
+
+	if !quiet {
+		green.Printf("%s ", glyphs.ok)
+		fmt.Printf("Copied %s ", label)
+		gray.Printf("(%s)\n", formatSize(copied))
+	}
+}
+
+// splitCopyArg separates CONTAINER:PATH; paths starting with / or . are
+// always local, so host files with a colon can still be named
+func splitCopyArg(arg string) (containerID, p string, remote bool) {
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") {
+		return "", arg, false
+	}
+	containerID, p, remote = strings.Cut(arg, ":")
+	if !remote {
+		return "", arg, false
+	}
+	return containerID, p, true
+}
+
+// progressPrinter returns a function that redraws a progress line for a
+// transfer until stop is closed. A zero total draws bytes without a bar.
+func progressPrinter(label string, enabled bool) func(counter *countingReader, total int64, stop <-chan struct{}) {
+	return func(counter *countingReader, total int64, stop <-chan struct{}) {
+		if !enabled {
+			<-stop
+			return
+		}
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			done := counter.n.Load()
+			line := fmt.Sprintf("%s  %s", ellipsize(label, 40), formatSize(done))
+			if total > 0 {
+				percent := float64(done) / float64(total)
+				if percent > 1 {
+					percent = 1
+				}
+				line = fmt.Sprintf("%s  %s %3.0f%%  %s / %s", ellipsize(label, 40), renderProgressBar(percent, 30, false), percent*100, formatSize(done), formatSize(total))
+			}
+			fmt.Print("\r\033[K" + line)
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// copyFromContainer streams a container path into dst on the host. If dst
+// is an existing directory the path lands inside it, otherwise it is
+// created with dst's name.
+func copyFromContainer(ctx context.Context, cli *client.Client, containerID, src, dst string, progress func(*countingReader, int64, <-chan struct{})) (int64, error) {
+	reader, stat, err := cli.CopyFromContainer(ctx, containerID, src)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	// Only a file's size is known up front; directories just count bytes
+	var total int64
+	if !stat.Mode.IsDir() {
+		total = stat.Size
+	}
+
+	counter := &countingReader{r: reader}
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		progress(counter, total, stop)
+		close(finished)
+	}()
+	defer func() {
+		close(stop)
+		<-finished
+	}()
+
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		if _, err := os.Lstat(filepath.Join(dst, path.Base(stat.Name))); err == nil {
+			return 0, fmt.Errorf("%s already exists", filepath.Join(dst, path.Base(stat.Name)))
+		}
+		_, err := extractTar(counter, dst)
+		return counter.n.Load(), err
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return 0, fmt.Errorf("%s already exists", dst)
+	}
+
+	// Extract beside the destination, then move it into place under its
+	// new name
+	staging, err := os.MkdirTemp(filepath.Dir(dst), ".dockit-cp-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(staging)
+	if _, err := extractTar(counter, staging); err != nil {
+		return counter.n.Load(), err
+	}
+	return counter.n.Load(), os.Rename(filepath.Join(staging, path.Base(stat.Name)), dst)
+}
+
+// copyToContainer archives a host file or directory and streams it into the
+// container. If dst is an existing directory the path lands inside it,
+// otherwise it is created with dst's name.
+func copyToContainer(ctx context.Context, cli *client.Client, containerID, src, dst string, progress func(*countingReader, int64, <-chan struct{})) (int64, error) {
+	if _, err := os.Lstat(src); err != nil {
+		return 0, err
+	}
+
+	dstDir, name := dst, filepath.Base(src)
+	if stat, err := cli.ContainerStatPath(ctx, containerID, dst); err != nil || !stat.Mode.IsDir() {
+		dstDir, name = path.Dir(dst), path.Base(dst)
+	}
+
+	total, err := treeSize(src)
+	if err != nil {
+		return 0, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, src, name))
+	}()
+
+	counter := &countingReader{r: pr}
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		progress(counter, total, stop)
+		close(finished)
+	}()
+	defer func() {
+		close(stop)
+		<-finished
+	}()
+
+	err = cli.CopyToContainer(ctx, containerID, dstDir, counter, container.CopyToContainerOptions{})
+	pr.Close()
+	return counter.n.Load(), err
+}
+
+// treeSize adds up the regular files under root, roughly the archive size
+func treeSize(root string) (int64, error) {
+	var total int64
+	err := filepath.Walk(root, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// writeTar archives src into w with its top-level entry renamed to name
+func writeTar(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if fi.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
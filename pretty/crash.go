@@ -0,0 +1,79 @@
+package pretty
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// crashLogLines is how many of a container's final log lines are scanned
+const crashLogLines = 50
+
+// crashPatterns are log messages that commonly explain why a container exited,
+// checked in order
+var crashPatterns = []struct {
+	pattern *regexp.Regexp
+	cause   string
+}{
+	{regexp.MustCompile(`(?i)address already in use|EADDRINUSE|port is already allocated`), "Port already in use"},
+	{regexp.MustCompile(`(?i)out of memory|heap out of memory|cannot allocate memory|OutOfMemoryError|ENOMEM`), "Ran out of memory"},
+	{regexp.MustCompile(`(?i)segmentation fault|SIGSEGV|core dumped`), "Segmentation fault"},
+	{regexp.MustCompile(`(?i)exec format error`), "Binary built for a different CPU architecture"},
+	{regexp.MustCompile(`(?i)permission denied|EACCES|operation not permitted|EPERM`), "Permission denied"},
+	{regexp.MustCompile(`(?i)no such file or directory|ENOENT|cannot find module|executable file not found`), "Missing file or command"},
+	{regexp.MustCompile(`(?i)connection refused|ECONNREFUSED|could not connect|name or service not known|no such host`), "Could not reach a dependency"},
+}
+
+// crashCause is the probable reason a container exited
+type crashCause struct {
+	source  int
+	summary string
+	line    int // index into the viewer's lines, or -1 when only inspect data matched
+}
+
+// analyzeCrash checks inspect data and then the final log lines of an exited
+// container for a known failure. lines holds the container's log text, oldest
+// first; the returned line is an index into it, or -1.
+func analyzeCrash(state *container.State, lines []string) (string, int, bool) {
+	if state == nil || (state.Status != "exited" && state.Status != "dead") {
+		return "", -1, false
+	}
+	if state.OOMKilled {
+		return "Killed by the kernel OOM killer (raise the memory limit)", lastMatch(lines, crashPatterns[1].pattern), true
+	}
+
+	for _, known := range crashPatterns {
+		if line := lastMatch(lines, known.pattern); line >= 0 {
+			return fmt.Sprintf("%s (exit code %d)", known.cause, state.ExitCode), line, true
+		}
+	}
+
+	switch state.ExitCode {
+	case 0:
+		return "", -1, false
+	case 137:
+		return "Killed with SIGKILL (exit code 137), possibly by docker stop timing out", -1, true
+	case 139:
+		return "Segmentation fault (exit code 139)", -1, true
+	case 126:
+		return "Command not executable (exit code 126)", -1, true
+	case 127:
+		return "Command not found (exit code 127)", -1, true
+	}
+	if state.Error != "" {
+		return state.Error, -1, true
+	}
+	return "", -1, false
+}
+
+// lastMatch returns the index of the last line in the final crashLogLines
+// that matches pattern, or -1
+func lastMatch(lines []string, pattern *regexp.Regexp) int {
+	for i := len(lines) - 1; i >= 0 && i >= len(lines)-crashLogLines; i-- {
+		if pattern.MatchString(lines[i]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,325 @@
+package pretty
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// dockerHubServer is the key Docker Hub credentials are stored under
+const dockerHubServer = "https://index.docker.io/v1/"
+
+// identityTokenUser is the user name credential helpers store when the
+// secret is an identity token rather than a password
+const identityTokenUser = "<token>"
+
+// credentialsFile is the credentials part of ~/.docker/config.json
+type credentialsFile struct {
+	Auths       map[string]authEntry `json:"auths,omitempty"`
+	CredsStore  string               `json:"credsStore,omitempty"`
+	CredHelpers map[string]string    `json:"credHelpers,omitempty"`
+}
+
+type authEntry struct {
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// helperCredentials is what docker-credential-* helpers read and write
+type helperCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+func dockerConfigFile() string {
+	return filepath.Join(dockerConfigDir(), "config.json")
+}
+
+func readCredentialsFile() (credentialsFile, error) {
+	var creds credentialsFile
+	data, err := os.ReadFile(dockerConfigFile())
+	if os.IsNotExist(err) {
+		return creds, nil
+	}
+	if err != nil {
+		return creds, err
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return creds, fmt.Errorf("error parsing %s: %v", dockerConfigFile(), err)
+	}
+	return creds, nil
+}
+
+// writeAuths replaces the auths section of config.json, keeping every other
+// setting the docker CLI stores there
+func writeAuths(auths map[string]authEntry) error {
+	path := dockerConfigFile()
+	raw := map[string]json.RawMessage{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("error parsing %s: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	encoded, err := json.Marshal(auths)
+	if err != nil {
+		return err
+	}
+	raw["auths"] = encoded
+
+	data, err := json.MarshalIndent(raw, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// helperFor returns the credential helper that stores server's credentials,
+// or "" when they live in config.json itself
+func (c credentialsFile) helperFor(server string) string {
+	if helper, ok := c.CredHelpers[server]; ok {
+		return helper
+	}
+	return c.CredsStore
+}
+
+// registryServer returns the key credentials for ref's registry are stored
+// under: the Docker Hub index URL or the registry's host name
+func registryServer(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	return normalizeServer(reference.Domain(named)), nil
+}
+
+// normalizeServer maps the names people type for a registry to the key
+// docker login uses
+func normalizeServer(server string) string {
+	server = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://"), "/")
+	switch server {
+	case "", "docker.io", "index.docker.io", "registry-1.docker.io", "index.docker.io/v1":
+		return dockerHubServer
+	}
+	return server
+}
+
+// lookupCredentials returns the stored credentials for server, if any
+func lookupCredentials(server string) (registry.AuthConfig, bool, error) {
+	creds, err := readCredentialsFile()
+	if err != nil {
+		return registry.AuthConfig{}, false, err
+	}
+
+	if helper := creds.helperFor(server); helper != "" {
+		var stored helperCredentials
+		out, err := runCredentialHelper(helper, "get", server)
+		if err != nil {
+			// Helpers report missing credentials as an error
+			if strings.Contains(err.Error(), "credentials not found") {
+				return registry.AuthConfig{}, false, nil
+			}
+			return registry.AuthConfig{}, false, err
+		}
+		if err := json.Unmarshal(out, &stored); err != nil {
+			return registry.AuthConfig{}, false, fmt.Errorf("docker-credential-%s: %v", helper, err)
+		}
+		auth := registry.AuthConfig{ServerAddress: server, Username: stored.Username, Password: stored.Secret}
+		if stored.Username == identityTokenUser {
+			auth = registry.AuthConfig{ServerAddress: server, IdentityToken: stored.Secret}
+		}
+		return auth, true, nil
+	}
+
+	entry, ok := creds.Auths[server]
+	if !ok {
+		return registry.AuthConfig{}, false, nil
+	}
+	auth := registry.AuthConfig{ServerAddress: server, IdentityToken: entry.IdentityToken}
+	if entry.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return auth, false, fmt.Errorf("invalid auth for %s in %s", server, dockerConfigFile())
+		}
+		auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+	}
+	return auth, true, nil
+}
+
+// storeCredentials saves auth for server, in its credential helper when one
+// is configured
+func storeCredentials(server string, auth registry.AuthConfig) error {
+	creds, err := readCredentialsFile()
+	if err != nil {
+		return err
+	}
+
+	if helper := creds.helperFor(server); helper != "" {
+		stored := helperCredentials{ServerURL: server, Username: auth.Username, Secret: auth.Password}
+		if auth.IdentityToken != "" {
+			stored.Username, stored.Secret = identityTokenUser, auth.IdentityToken
+		}
+		input, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		if _, err := runCredentialHelper(helper, "store", string(input)); err != nil {
+			return err
+		}
+		// A helper owns the secret, so drop any copy left in the file
+		if _, ok := creds.Auths[server]; ok {
+			delete(creds.Auths, server)
+			return writeAuths(creds.Auths)
+		}
+		return nil
+	}
+
+	if creds.Auths == nil {
+		creds.Auths = map[string]authEntry{}
+	}
+	entry := authEntry{IdentityToken: auth.IdentityToken}
+	if auth.IdentityToken == "" {
+		entry.Auth = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+	}
+	creds.Auths[server] = entry
+	return writeAuths(creds.Auths)
+}
+
+// eraseCredentials removes server's credentials; it reports whether there
+// were any
+func eraseCredentials(server string) (bool, error) {
+	creds, err := readCredentialsFile()
+	if err != nil {
+		return false, err
+	}
+
+	erased := false
+	if helper := creds.helperFor(server); helper != "" {
+		if _, err := runCredentialHelper(helper, "erase", server); err == nil {
+			erased = true
+		} else if !strings.Contains(err.Error(), "credentials not found") {
+			return false, err
+		}
+	}
+	if _, ok := creds.Auths[server]; ok {
+		delete(creds.Auths, server)
+		if err := writeAuths(creds.Auths); err != nil {
+			return erased, err
+		}
+		erased = true
+	}
+	return erased, nil
+}
+
+// savedServers lists the registries with credentials and where each is kept
+func savedServers() (map[string]string, error) {
+	creds, err := readCredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	servers := map[string]string{}
+	for server := range creds.Auths {
+		servers[server] = "config.json"
+		if helper := creds.helperFor(server); helper != "" {
+			servers[server] = "docker-credential-" + helper
+		}
+	}
+	if creds.CredsStore != "" {
+		if out, err := runCredentialHelper(creds.CredsStore, "list", ""); err == nil {
+			var listed map[string]string
+			if json.Unmarshal(out, &listed) == nil {
+				for server := range listed {
+					servers[server] = "docker-credential-" + creds.CredsStore
+				}
+			}
+		}
+	}
+	for server, helper := range creds.CredHelpers {
+		servers[server] = "docker-credential-" + helper
+	}
+	return servers, nil
+}
+
+// runCredentialHelper runs docker-credential-HELPER ACTION with input on stdin
+func runCredentialHelper(helper, action, input string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker-credential-"+helper, action)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stdout.String() + " " + stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("docker-credential-%s %s: %s", helper, action, msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+// registryAuth returns the X-Registry-Auth value for pulling ref, or "" when
+// no credentials are saved for its registry
+func registryAuth(ref string) string {
+	server, err := registryServer(ref)
+	if err != nil {
+		return ""
+	}
+	auth, ok, err := lookupCredentials(server)
+	if err != nil || !ok {
+		return ""
+	}
+	encoded, err := registry.EncodeAuthConfig(auth)
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
+
+// isAuthError reports whether a registry refused a pull for lack of
+// credentials
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, hint := range []string{"unauthorized", "authentication required", "access denied", "denied: requested access", "no basic auth credentials", "pull access denied"} {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// loginHint is the command that fixes an auth failure for ref
+func loginHint(ref string) string {
+	server, err := registryServer(ref)
+	if err != nil || server == dockerHubServer {
+		return "dockit login"
+	}
+	return "dockit login " + server
+}
+
+func sortedServers(servers map[string]string) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
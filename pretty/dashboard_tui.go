@@ -0,0 +1,395 @@
+package pretty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// dashboardInterval is how often the dashboard refreshes; stats for each
+// running container take about a second to sample
+const dashboardInterval = 5 * time.Second
+
+// containerUsage is one running container's share of the host
+type containerUsage struct {
+	name     string
+	cpu      float64
+	memUsed  uint64
+	memLimit uint64
+}
+
+// diskCategory is one row of the system df breakdown
+type diskCategory struct {
+	name        string
+	count       int
+	size        int64
+	reclaimable int64
+}
+
+// dashboardSnapshot is everything one refresh collects
+type dashboardSnapshot struct {
+	version    string
+	apiVersion string
+	os         string
+	cpus       int
+	memTotal   int64
+	running    int
+	paused     int
+	stopped    int
+	images     int
+	usage      []containerUsage // sorted by CPU, busiest first
+	disk       []diskCategory
+	unhealthy  []string
+	warnings   []string // from the daemon's info
+	fetched    time.Time
+}
+
+type dashboardModel struct {
+	cli        *client.Client
+	ctx        context.Context
+	snapshot   *dashboardSnapshot
+	loading    bool
+	refreshErr errorBanner
+	width      int
+	height     int
+}
+
+type dashboardLoadedMsg struct {
+	snapshot *dashboardSnapshot
+	err      error
+}
+
+type dashboardTickMsg struct{}
+
+func dashboardTick() tea.Cmd {
+	return tea.Tick(dashboardInterval, func(time.Time) tea.Msg { return dashboardTickMsg{} })
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), dashboardTick())
+}
+
+func (m dashboardModel) load() tea.Cmd {
+	cli, ctx := m.cli, m.ctx
+	return func() tea.Msg {
+		snapshot, err := loadDashboard(ctx, cli)
+		return dashboardLoadedMsg{snapshot: snapshot, err: err}
+	}
+}
+
+// loadDashboard collects the daemon info, container counts, per-container
+// usage, and disk usage for one refresh
+func loadDashboard(ctx context.Context, cli *client.Client) (*dashboardSnapshot, error) {
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &dashboardSnapshot{
+		version:    version.Version,
+		apiVersion: version.APIVersion,
+		os:         version.Os + "/" + version.Arch,
+		cpus:       info.NCPU,
+		memTotal:   info.MemTotal,
+		images:     info.Images,
+		warnings:   info.Warnings,
+		fetched:    time.Now(),
+	}
+
+	var running []container.Summary
+	for _, c := range containers {
+		switch c.State {
+		case "running":
+			snapshot.running++
+			running = append(running, c)
+		case "paused":
+			snapshot.paused++
+		default:
+			snapshot.stopped++
+		}
+		if healthFromStatus(c.Status) == "unhealthy" {
+			snapshot.unhealthy = append(snapshot.unhealthy, strings.TrimPrefix(c.Names[0], "/"))
+		}
+	}
+	sort.Strings(snapshot.unhealthy)
+
+	// Sample every running container at once; a non-streaming stats call
+	// waits for a second sample so the CPU figure has something to compare
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, c := range running {
+		wg.Add(1)
+		go func(c container.Summary) {
+			defer wg.Done()
+			sample, err := sampleStats(ctx, cli, c.ID)
+			if err != nil {
+				return
+			}
+			used, limit := memoryUsage(sample)
+			mu.Lock()
+			snapshot.usage = append(snapshot.usage, containerUsage{
+				name:     strings.TrimPrefix(c.Names[0], "/"),
+				cpu:      cpuPercent(sample),
+				memUsed:  used,
+				memLimit: limit,
+			})
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+	sort.Slice(snapshot.usage, func(i, j int) bool { return snapshot.usage[i].cpu > snapshot.usage[j].cpu })
+
+	// Disk usage is slow on big hosts; the dashboard still shows without it
+	if du, err := cli.DiskUsage(ctx, types.DiskUsageOptions{}); err == nil {
+		snapshot.disk = diskCategories(du)
+	}
+
+	return snapshot, nil
+}
+
+// sampleStats takes a single stats sample, with the previous one filled in
+func sampleStats(ctx context.Context, cli *client.Client, containerID string) (container.StatsResponse, error) {
+	var sample container.StatsResponse
+	resp, err := cli.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return sample, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&sample); err != nil {
+		return sample, err
+	}
+	normalizeStats(&sample, nil)
+	return sample, nil
+}
+
+// diskCategories summarizes system df like docker system df does
+func diskCategories(du types.DiskUsage) []diskCategory {
+	// Layers shared between images count once in the total, and only the
+	// unshared part of an unused image is reclaimable
+	images := diskCategory{name: "Images", count: len(du.Images), size: du.LayersSize}
+	for _, img := range du.Images {
+		if img.Containers != 0 {
+			continue
+		}
+		// SharedSize is -1 when the daemon didn't compute it
+		unshared := img.Size
+		if img.SharedSize > 0 {
+			unshared -= img.SharedSize
+		}
+		images.reclaimable += unshared
+	}
+
+	containers := diskCategory{name: "Containers", count: len(du.Containers)}
+	for _, c := range du.Containers {
+		containers.size += c.SizeRw
+		if !containerStateIsActive(c) {
+			containers.reclaimable += c.SizeRw
+		}
+	}
+
+	volumes := diskCategory{name: "Volumes", count: len(du.Volumes)}
+	for _, v := range du.Volumes {
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+		volumes.size += v.UsageData.Size
+		if v.UsageData.RefCount == 0 {
+			volumes.reclaimable += v.UsageData.Size
+		}
+	}
+
+	cache := diskCategory{name: "Build cache", count: len(du.BuildCache)}
+	for _, record := range du.BuildCache {
+		if record.Shared {
+			continue
+		}
+		cache.size += record.Size
+		if !record.InUse {
+			cache.reclaimable += record.Size
+		}
+	}
+
+	return []diskCategory{images, containers, volumes, cache}
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case dashboardLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			// Keep showing the last snapshot under the error
+			m.refreshErr.record(msg.err, time.Now())
+			return m, nil
+		}
+		m.refreshErr.clear()
+		m.snapshot = msg.snapshot
+		return m, nil
+
+	case dashboardTickMsg:
+		// A refresh slower than the interval just skips a beat
+		if m.loading {
+			return m, dashboardTick()
+		}
+		m.loading = true
+		return m, tea.Batch(m.load(), dashboardTick())
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, m.load()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m dashboardModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if small := tooSmallView(m.width, m.height, 50, 14); small != "" {
+		return small
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("DASHBOARD"))
+	sb.WriteString("\n")
+
+	s := m.snapshot
+	if s == nil {
+		if m.refreshErr.active() {
+			sb.WriteString(errorStyle.Render(m.refreshErr.String()))
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(helpStyle.Render("Connecting to Docker..."))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(helpStyle.Render("r: refresh | q: quit"))
+		return sb.String()
+	}
+
+	// Daemon
+	sb.WriteString(fmt.Sprintf("Docker %s (API %s) %s %s %s %d CPUs %s %s memory\n",
+		s.version, s.apiVersion, glyphs.divider, s.os, glyphs.divider, s.cpus, glyphs.divider, formatSize(s.memTotal)))
+	sb.WriteString(fmt.Sprintf("Containers: %s running, %d paused, %d stopped %s %d images\n\n",
+		selectedStyle.Render(fmt.Sprint(s.running)), s.paused, s.stopped, glyphs.divider, s.images))
+
+	// Aggregate usage, against everything the host has
+	var totalCPU float64
+	var totalMem uint64
+	for _, u := range s.usage {
+		totalCPU += u.cpu
+		totalMem += u.memUsed
+	}
+	barWidth := max(m.width-30, 10)
+	cpuCeiling := float64(max(s.cpus, 1) * 100)
+	memFraction := 0.0
+	if s.memTotal > 0 {
+		memFraction = float64(totalMem) / float64(s.memTotal)
+	}
+	sb.WriteString("  CPU " + renderProgressBar(totalCPU/cpuCeiling, barWidth, false))
+	sb.WriteString(fmt.Sprintf(" %.1f%% of %d CPUs\n", totalCPU, s.cpus))
+	sb.WriteString("  MEM " + renderProgressBar(memFraction, barWidth, false))
+	sb.WriteString(fmt.Sprintf(" %s / %s\n\n", formatSize(int64(totalMem)), formatSize(s.memTotal)))
+
+	// Warnings come first when there are any, they're what needs attention
+	var warnings []string
+	for _, name := range s.unhealthy {
+		warnings = append(warnings, fmt.Sprintf("%s %s is unhealthy", glyphs.warn, name))
+	}
+	for _, warning := range s.warnings {
+		warnings = append(warnings, fmt.Sprintf("%s %s", glyphs.warn, warning))
+	}
+	for _, warning := range warnings {
+		sb.WriteString(errorStyle.Render(ellipsize(warning, m.width)))
+		sb.WriteString("\n")
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n")
+	}
+
+	// Disk
+	if s.disk != nil {
+		sb.WriteString(titleStyle.Render("DISK"))
+		sb.WriteString("\n")
+		for _, d := range s.disk {
+			sb.WriteString(fmt.Sprintf("  %-12s %5d  %10s", d.name, d.count, formatSize(d.size)))
+			sb.WriteString(helpStyle.Render(fmt.Sprintf("  %s reclaimable", formatSize(d.reclaimable))))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Busiest containers, as many as fit above the footer
+	used := strings.Count(sb.String(), "\n")
+	rows := m.height - used - 4
+	if len(s.usage) > 0 && rows > 0 {
+		sb.WriteString(titleStyle.Render("TOP CONTAINERS"))
+		sb.WriteString("\n")
+		nameWidth := max(m.width-40, 10)
+		for _, u := range s.usage[:min(rows, len(s.usage))] {
+			sb.WriteString(fmt.Sprintf("  %-*s %7.1f%%  %10s", nameWidth, ellipsize(u.name, nameWidth), u.cpu, formatSize(int64(u.memUsed))))
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	if m.refreshErr.active() {
+		sb.WriteString(errorStyle.Render(m.refreshErr.String()))
+	} else {
+		sb.WriteString(helpStyle.Render("Updated " + formatClock(s.fetched)))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(helpStyle.Render("r: refresh | q: quit"))
+
+	return sb.String()
+}
+
+// PrintDashboard opens the resource overview: daemon info, aggregate CPU and
+// memory, disk usage, and warnings for unhealthy containers
+func PrintDashboard(args []string) {
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newProgram(dashboardModel{cli: cli, ctx: ctx, loading: true}, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+	updateState(rememberView)
+}
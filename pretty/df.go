@@ -0,0 +1,293 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// dfSummary is one line of the disk usage report
+type dfSummary struct {
+	name        string
+	total       int
+	active      int
+	size        int64
+	reclaimable int64
+}
+
+// dfRow is one item in a verbose disk usage table
+type dfRow struct {
+	name    string
+	columns []string // shown between the name and the size
+	size    int64    // -1 when the daemon doesn't know
+	created time.Time
+}
+
+// PrintSystemDiskUsage shows the space images, containers, volumes, and the
+// build cache take up and how much of it could be reclaimed, like docker
+// system df; -v lists every item with its size
+func PrintSystemDiskUsage(args []string) {
+	verbose := false
+	sortBy := "size"
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-v" || arg == "--verbose":
+			verbose = true
+		case arg == "--sort" && i+1 < len(args):
+			i++
+			sortBy = args[i]
+		case strings.HasPrefix(arg, "--sort="):
+			sortBy = strings.TrimPrefix(arg, "--sort=")
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			fmt.Println("Usage: dockit df [-v] [--sort size|name|created]")
+			os.Exit(1)
+		}
+	}
+	if sortBy != "size" && sortBy != "name" && sortBy != "created" {
+		fmt.Fprintf(os.Stderr, "Error: --sort must be size, name, or created\n")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	usage, err := systemDiskUsage(context.Background(), cli)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing disk usage: %v\n", err)
+		os.Exit(1)
+	}
+
+	renderDiskSummary(summarizeDiskUsage(usage))
+	if verbose {
+		for _, table := range diskUsageTables(usage) {
+			sortDiskRows(table.rows, sortBy)
+			renderDiskTable(table.title, table.headers, table.rows)
+		}
+	}
+	fmt.Println()
+	gray.Println("(reclaim space with 'dockit prune')")
+}
+
+// systemDiskUsage asks the daemon for the size of everything it stores,
+// which it works out on the spot and can take a while on a busy host
+func systemDiskUsage(ctx context.Context, cli *client.Client) (types.DiskUsage, error) {
+	return cli.DiskUsage(ctx, types.DiskUsageOptions{})
+}
+
+// summarizeDiskUsage totals each kind the way docker system df does:
+// images in use by a container count as active, and only the layers no
+// container needs are reclaimable
+func summarizeDiskUsage(usage types.DiskUsage) []dfSummary {
+	images := dfSummary{name: "Images", total: len(usage.Images), size: usage.LayersSize}
+	var used int64
+	for _, img := range usage.Images {
+		if img.Containers > 0 {
+			images.active++
+			used += img.Size
+		}
+	}
+	if used < images.size {
+		images.reclaimable = images.size - used
+	}
+
+	containers := dfSummary{name: "Containers", total: len(usage.Containers)}
+	for _, c := range usage.Containers {
+		containers.size += c.SizeRw
+		if containerStateIsActive(c) {
+			containers.active++
+		} else {
+			containers.reclaimable += c.SizeRw
+		}
+	}
+
+	volumes := dfSummary{name: "Local volumes", total: len(usage.Volumes)}
+	for _, v := range usage.Volumes {
+		if v.UsageData == nil {
+			continue
+		}
+		if v.UsageData.Size > 0 {
+			volumes.size += v.UsageData.Size
+		}
+		if v.UsageData.RefCount > 0 {
+			volumes.active++
+		} else if v.UsageData.Size > 0 {
+			volumes.reclaimable += v.UsageData.Size
+		}
+	}
+
+	cache := dfSummary{name: "Build cache", total: len(usage.BuildCache)}
+	for _, record := range usage.BuildCache {
+		if record.InUse {
+			cache.active++
+		}
+		if !record.Shared {
+			cache.size += record.Size
+			if !record.InUse {
+				cache.reclaimable += record.Size
+			}
+		}
+	}
+
+	return []dfSummary{images, containers, volumes, cache}
+}
+
+func renderDiskSummary(summaries []dfSummary) {
+	fmt.Println()
+	cyan.Println("DISK USAGE")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	gray.Printf("%-15s %6s %7s %10s %12s\n", "TYPE", "TOTAL", "ACTIVE", "SIZE", "RECLAIMABLE")
+
+	var size, reclaimable int64
+	for _, s := range summaries {
+		size += s.size
+		reclaimable += s.reclaimable
+
+		percent := 0.0
+		if s.size > 0 {
+			percent = float64(s.reclaimable) / float64(s.size)
+		}
+		fmt.Printf("%-15s %6d %7d %10s ", s.name, s.total, s.active, formatSize(s.size))
+		reclaimColor := gray
+		if s.reclaimable > 0 {
+			reclaimColor = yellow
+		}
+		reclaimColor.Printf("%12s", formatSize(s.reclaimable))
+		fmt.Printf(" %s", renderProgressBar(percent, 20, false))
+		gray.Printf(" %3.0f%%\n", percent*100)
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %s", formatSize(size))
+	if reclaimable > 0 {
+		yellow.Printf(", %s reclaimable\n", formatSize(reclaimable))
+	} else {
+		fmt.Println()
+	}
+}
+
+type dfTable struct {
+	title   string
+	headers []string
+	rows    []dfRow
+}
+
+// diskUsageTables lists every item of each kind for -v
+func diskUsageTables(usage types.DiskUsage) []dfTable {
+	images := dfTable{title: "IMAGES", headers: []string{"ID", "CONTAINERS", "SHARED", "UNIQUE"}}
+	for _, img := range usage.Images {
+		name := "<none>"
+		if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+			name = strings.Join(img.RepoTags, ", ")
+		}
+		shared, unique := "-", "-"
+		if img.SharedSize >= 0 {
+			shared, unique = formatSize(img.SharedSize), formatSize(img.Size-img.SharedSize)
+		}
+		images.rows = append(images.rows, dfRow{
+			name:    name,
+			columns: []string{formatID(img.ID, false), fmt.Sprint(img.Containers), shared, unique},
+			size:    img.Size,
+			created: time.Unix(img.Created, 0),
+		})
+	}
+
+	containers := dfTable{title: "CONTAINERS", headers: []string{"IMAGE", "STATE", "", ""}}
+	for _, c := range usage.Containers {
+		name := formatID(c.ID, false)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		containers.rows = append(containers.rows, dfRow{
+			name:    name,
+			columns: []string{ellipsize(c.Image, 24), c.State, "", ""},
+			size:    c.SizeRw,
+			created: time.Unix(c.Created, 0),
+		})
+	}
+
+	volumes := dfTable{title: "LOCAL VOLUMES", headers: []string{"DRIVER", "LINKS", "", ""}}
+	for _, v := range usage.Volumes {
+		row := dfRow{name: v.Name, size: -1, columns: []string{v.Driver, "-", "", ""}}
+		if v.UsageData != nil {
+			row.size = v.UsageData.Size
+			row.columns[1] = fmt.Sprint(v.UsageData.RefCount)
+		}
+		if created, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil {
+			row.created = created
+		}
+		volumes.rows = append(volumes.rows, row)
+	}
+
+	cache := dfTable{title: "BUILD CACHE", headers: []string{"TYPE", "LAST USED", "IN USE", "SHARED"}}
+	for _, record := range usage.BuildCache {
+		lastUsed := "never"
+		if record.LastUsedAt != nil {
+			lastUsed = formatTime(*record.LastUsedAt)
+		}
+		name := formatID(record.ID, false)
+		if record.Description != "" {
+			name += " " + record.Description
+		}
+		cache.rows = append(cache.rows, dfRow{
+			name:    name,
+			columns: []string{record.Type, lastUsed, yesNo(record.InUse), yesNo(record.Shared)},
+			size:    record.Size,
+			created: record.CreatedAt,
+		})
+	}
+
+	return []dfTable{images, containers, volumes, cache}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func sortDiskRows(rows []dfRow, sortBy string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		switch sortBy {
+		case "name":
+			return rows[i].name < rows[j].name
+		case "created":
+			return rows[i].created.After(rows[j].created)
+		default:
+			return rows[i].size > rows[j].size
+		}
+	})
+}
+
+func renderDiskTable(title string, headers []string, rows []dfRow) {
+	fmt.Println()
+	cyan.Printf("%s (%d)\n", title, len(rows))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	if len(rows) == 0 {
+		gray.Println("None")
+		return
+	}
+
+	gray.Printf("%-34s %-24s %-12s %-10s %-10s %10s\n", "NAME", headers[0], headers[1], headers[2], headers[3], "SIZE")
+	for _, row := range rows {
+		size := "unknown"
+		if row.size >= 0 {
+			size = formatSize(row.size)
+		}
+		fmt.Printf("%-34s ", ellipsize(row.name, 34))
+		gray.Printf("%-24s %-12s %-10s %-10s ", ellipsize(row.columns[0], 24), ellipsize(row.columns[1], 12), row.columns[2], row.columns[3])
+		fmt.Printf("%10s\n", size)
+	}
+}
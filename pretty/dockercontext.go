@@ -0,0 +1,335 @@
+package pretty
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
+)
+
+// dockerContext is a Docker CLI context: a named daemon endpoint
+type dockerContext struct {
+	Name          string
+	Description   string
+	Host          string
+	SkipTLSVerify bool
+	tlsDir        string
+}
+
+// activeContext and activeHost are set by the global --context and --host flags
+var (
+	activeContext string
+	activeHost    string
+)
+
+// SetContext selects the Docker context used by all commands, like docker --context
+func SetContext(name string) {
+	activeContext = name
+}
+
+// SetHost selects the daemon used by all commands, like docker --host
+func SetHost(host string) {
+	activeHost = host
+}
+
+// DockerCommand returns a docker CLI command that targets the same daemon as
+// dockit's own API calls
+func DockerCommand(args ...string) *exec.Cmd {
+	switch {
+	case activeHost != "":
+		args = append([]string{"--host", activeHost}, args...)
+	case activeContext != "":
+		args = append([]string{"--context", activeContext}, args...)
+	}
+	return exec.Command("docker", args...)
+}
+
+// dockitCommand returns a command running this dockit against the same
+// daemon, for views that open other views
+func dockitCommand(args ...string) *exec.Cmd {
+	self, err := os.Executable()
+	if err != nil {
+		self = os.Args[0]
+	}
+	switch {
+	case activeHost != "":
+		args = append([]string{"--host", activeHost}, args...)
+	case activeContext != "":
+		args = append([]string{"--context", activeContext}, args...)
+	}
+	return exec.Command(self, args...)
+}
+
+// newClient connects to the daemon selected by --host, --context,
+// DOCKER_CONTEXT, DOCKER_HOST, or the Docker CLI's current context, in that order
+func newClient() (*client.Client, error) {
+	if activeHost != "" {
+		return clientForHost(activeHost, "", false)
+	}
+	return clientForContext(currentContextName())
+}
+
+// clientForContext connects to the endpoint of the named context
+func clientForContext(name string) (*client.Client, error) {
+	if name == "default" {
+		// DOCKER_HOST, DOCKER_CERT_PATH, and DOCKER_TLS_VERIFY, like client.FromEnv
+		host := os.Getenv(client.EnvOverrideHost)
+		if host == "" {
+			host = client.DefaultDockerHost
+		}
+		certPath := os.Getenv(client.EnvOverrideCertPath)
+		return clientForHost(host, certPath, certPath != "" && os.Getenv(client.EnvTLSVerify) == "")
+	}
+
+	ctx, err := loadContext(name)
+	if err != nil {
+		return nil, err
+	}
+	return clientForHost(ctx.Host, ctx.tlsDir, ctx.SkipTLSVerify)
+}
+
+// clientForHost connects to a tcp://, unix://, npipe://, or ssh:// daemon address
+func clientForHost(host, tlsDir string, skipVerify bool) (*client.Client, error) {
+	// The client keeps this http.Client, so its transport can be wrapped
+	// for API tracing once the client has configured it
+	transport := &http.Transport{MaxIdleConns: 6, IdleConnTimeout: 30 * time.Second}
+	httpClient := &http.Client{Transport: transport, CheckRedirect: client.CheckRedirect}
+	opts := []client.Opt{client.WithHTTPClient(httpClient), client.WithVersionFromEnv(), client.WithAPIVersionNegotiation()}
+
+	if strings.HasPrefix(host, "ssh://") {
+		// Tunnel the API through `docker system dial-stdio` on the remote host
+		opts = append(opts, client.WithHost("http://docker.example.com"), client.WithDialContext(dialSSH(host)))
+		return tracedClient(httpClient, opts)
+	}
+
+	if tlsDir != "" || skipVerify {
+		options := tlsconfig.Options{InsecureSkipVerify: skipVerify}
+		for file, target := range map[string]*string{"ca.pem": &options.CAFile, "cert.pem": &options.CertFile, "key.pem": &options.KeyFile} {
+			if path := filepath.Join(tlsDir, file); tlsDir != "" && fileExists(path) {
+				*target = path
+			}
+		}
+		tlsConfig, err := tlsconfig.Client(options)
+		if err != nil {
+			return nil, fmt.Errorf("error loading TLS config: %v", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	opts = append(opts, client.WithHost(host))
+	return tracedClient(httpClient, opts)
+}
+
+// tracedClient creates a client on httpClient and records its API calls
+func tracedClient(httpClient *http.Client, opts []client.Opt) (*client.Client, error) {
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = &tracingTransport{next: httpClient.Transport}
+	return cli, nil
+}
+
+// currentContextName returns the context selected by --context or
+// DOCKER_CONTEXT; DOCKER_HOST then wins over the Docker CLI config's
+// currentContext, as it does for the docker CLI
+func currentContextName() string {
+	if activeContext != "" {
+		return activeContext
+	}
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name
+	}
+	if activeHost != "" || os.Getenv(client.EnvOverrideHost) != "" {
+		return "default"
+	}
+
+	data, err := os.ReadFile(filepath.Join(dockerConfigDir(), "config.json"))
+	if err == nil {
+		var cliConfig struct {
+			CurrentContext string `json:"currentContext"`
+		}
+		if json.Unmarshal(data, &cliConfig) == nil && cliConfig.CurrentContext != "" {
+			return cliConfig.CurrentContext
+		}
+	}
+	return "default"
+}
+
+// dockerConfigDir returns $DOCKER_CONFIG or ~/.docker
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker")
+}
+
+// contextMeta is the meta.json stored for each context
+type contextMeta struct {
+	Name     string `json:"Name"`
+	Metadata struct {
+		Description string `json:"Description"`
+	} `json:"Metadata"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// listContexts returns the default context followed by every context in
+// ~/.docker/contexts, sorted by name
+func listContexts() ([]dockerContext, error) {
+	defaultHost := os.Getenv(client.EnvOverrideHost)
+	if defaultHost == "" {
+		defaultHost = client.DefaultDockerHost
+	}
+	contexts := []dockerContext{{Name: "default", Description: "Current DOCKER_HOST based configuration", Host: defaultHost}}
+
+	metaDir := filepath.Join(dockerConfigDir(), "contexts", "meta")
+	entries, err := os.ReadDir(metaDir)
+	if os.IsNotExist(err) {
+		return contexts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var named []dockerContext
+	for _, entry := range entries {
+		ctx, err := readContext(entry.Name())
+		if err == nil {
+			named = append(named, ctx)
+		}
+	}
+	sort.Slice(named, func(i, j int) bool { return named[i].Name < named[j].Name })
+	return append(contexts, named...), nil
+}
+
+// loadContext reads the named context; contexts are stored under the
+// SHA-256 of their name
+func loadContext(name string) (dockerContext, error) {
+	sum := sha256.Sum256([]byte(name))
+	ctx, err := readContext(hex.EncodeToString(sum[:]))
+	if err != nil {
+		return dockerContext{}, fmt.Errorf("context %q not found", name)
+	}
+	return ctx, nil
+}
+
+func readContext(id string) (dockerContext, error) {
+	data, err := os.ReadFile(filepath.Join(dockerConfigDir(), "contexts", "meta", id, "meta.json"))
+	if err != nil {
+		return dockerContext{}, err
+	}
+
+	var meta contextMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return dockerContext{}, err
+	}
+	endpoint, ok := meta.Endpoints["docker"]
+	if !ok {
+		return dockerContext{}, fmt.Errorf("context %q has no docker endpoint", meta.Name)
+	}
+
+	ctx := dockerContext{
+		Name:          meta.Name,
+		Description:   meta.Metadata.Description,
+		Host:          endpoint.Host,
+		SkipTLSVerify: endpoint.SkipTLSVerify,
+	}
+	if tlsDir := filepath.Join(dockerConfigDir(), "contexts", "tls", id, "docker"); fileExists(tlsDir) {
+		ctx.tlsDir = tlsDir
+	}
+	return ctx, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// dialSSH returns a dialer that runs `docker system dial-stdio` over ssh,
+// the same way the Docker CLI reaches ssh:// hosts
+func dialSSH(host string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		u, err := url.Parse(host)
+		if err != nil {
+			return nil, err
+		}
+
+		var args []string
+		if u.User != nil {
+			args = append(args, "-l", u.User.Username())
+		}
+		if port := u.Port(); port != "" {
+			args = append(args, "-p", port)
+		}
+		args = append(args, "--", u.Hostname(), "docker", "system", "dial-stdio")
+
+		cmd := exec.Command("ssh", args...)
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("error running ssh: %v", err)
+		}
+		return &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+	}
+}
+
+// commandConn is a net.Conn over a command's stdin and stdout
+type commandConn struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	closeOnce sync.Once
+}
+
+func (c *commandConn) Read(p []byte) (int, error)  { return c.stdout.Read(p) }
+func (c *commandConn) Write(p []byte) (int, error) { return c.stdin.Write(p) }
+
+// CloseWrite half-closes the connection, which the Docker client uses to
+// signal EOF on attached stdin
+func (c *commandConn) CloseWrite() error { return c.stdin.Close() }
+
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		c.stdout.Close()
+		c.cmd.Process.Kill()
+		c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr                { return dummyAddr{} }
+func (c *commandConn) RemoteAddr() net.Addr               { return dummyAddr{} }
+func (c *commandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type dummyAddr struct{}
+
+func (dummyAddr) Network() string { return "ssh" }
+func (dummyAddr) String() string  { return "ssh" }
@@ -0,0 +1,125 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+type doctorResult struct {
+	ok      bool
+	title   string
+	details []string
+}
+
+// doctorChecks are run in order by PrintDoctor
+var doctorChecks = []func(context.Context, *client.Client) doctorResult{
+	checkDaemon,
+	checkTrustedImages,
+	checkStaleContainers,
+}
+
+// PrintDoctor runs health checks against the Docker environment and reports issues
+func PrintDoctor(args []string) {
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	// Print header
+	fmt.Println()
+	cyan.Println("DOCTOR")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	issues := 0
+	for _, check := range doctorChecks {
+		result := check(ctx, cli)
+		if result.ok {
+			green.Print(glyphs.ok + " ")
+		} else {
+			yellow.Print(glyphs.warn + " ")
+			issues++
+		}
+		fmt.Println(result.title)
+		for _, detail := range result.details {
+			gray.Printf("  %s %s\n", glyphs.detail, detail)
+		}
+	}
+
+	fmt.Println()
+	if issues == 0 {
+		green.Println("No issues found")
+		return
+	}
+	yellow.Printf("%d issue(s) found\n", issues)
+	os.Exit(1)
+}
+
+func checkDaemon(ctx context.Context, cli *client.Client) doctorResult {
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return doctorResult{title: "Docker daemon unreachable", details: []string{err.Error()}}
+	}
+	return doctorResult{ok: true, title: fmt.Sprintf("Docker daemon reachable (version %s, API %s)", version.Version, version.APIVersion)}
+}
+
+func checkTrustedImages(ctx context.Context, cli *client.Client) doctorResult {
+	allowlist := trustedRegistries()
+	if len(allowlist) == 0 {
+		return doctorResult{ok: true, title: "No trusted registry allowlist configured (set trusted_registries in the config file)"}
+	}
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return doctorResult{title: "Could not list containers", details: []string{err.Error()}}
+	}
+
+	var untrusted []string
+	for _, c := range containers {
+		if !isTrustedImage(c.Image, allowlist) {
+			untrusted = append(untrusted, fmt.Sprintf("%s (%s)", strings.TrimPrefix(c.Names[0], "/"), c.Image))
+		}
+	}
+
+	if len(untrusted) == 0 {
+		return doctorResult{ok: true, title: "All containers use images from trusted registries"}
+	}
+	return doctorResult{
+		title:   fmt.Sprintf("%d container(s) use images from outside the trusted registries", len(untrusted)),
+		details: untrusted,
+	}
+}
+
+func checkStaleContainers(ctx context.Context, cli *client.Client) doctorResult {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return doctorResult{title: "Could not list containers", details: []string{err.Error()}}
+	}
+	images, err := loadImageIndex(ctx, cli)
+	if err != nil {
+		return doctorResult{title: "Could not list images", details: []string{err.Error()}}
+	}
+
+	var stale []string
+	for _, c := range containers {
+		if reason := images.staleReason(ctx, cli, c); reason != "" {
+			stale = append(stale, fmt.Sprintf("%s: %s (dockit recreate %s)", strings.TrimPrefix(c.Names[0], "/"), reason, strings.TrimPrefix(c.Names[0], "/")))
+		}
+	}
+
+	if len(stale) == 0 {
+		return doctorResult{ok: true, title: "All containers run the current image for their tag"}
+	}
+	return doctorResult{
+		title:   fmt.Sprintf("%d container(s) run an outdated image", len(stale)),
+		details: stale,
+	}
+}
@@ -0,0 +1,212 @@
+package pretty
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// diskUsageRow is one thing taking up space in a container
+type diskUsageRow struct {
+	kind   string // "layer", "volume", "bind", "tmpfs"
+	target string // mount destination, or a label for the writable layer
+	source string
+	size   int64 // -1 when unknown
+	note   string
+}
+
+// PrintDiskUsage ranks a container's writable layer and mounts by size
+func PrintDiskUsage(args []string) {
+	var containerID string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			containerID = arg
+		}
+	}
+	if containerID == "" {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit du CONTAINER")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	// Sizes are only computed when asked for
+	info, _, err := cli.ContainerInspectWithRaw(ctx, containerID, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error inspecting container: %v\n", err)
+		os.Exit(1)
+	}
+	running := info.State != nil && info.State.Running
+
+	rows := []diskUsageRow{{kind: "layer", target: "writable layer", size: -1}}
+	if info.SizeRw != nil {
+		rows[0].size = *info.SizeRw
+	}
+
+	var destinations []string
+	for _, m := range info.Mounts {
+		row := diskUsageRow{kind: string(m.Type), target: m.Destination, source: m.Source, size: -1}
+		if m.Name != "" {
+			row.source = m.Name
+		}
+		rows = append(rows, row)
+		destinations = append(destinations, m.Destination)
+	}
+
+	// Measure mounts from inside the container, falling back to the
+	// daemon's volume sizes when it isn't running or has no du
+	measured := map[string]int64{}
+	var measureErr error
+	if running && len(destinations) > 0 {
+		measured, measureErr = execDiskUsage(ctx, cli, info.ID, destinations)
+	}
+	volumeSizes := map[string]int64{}
+	if usage, err := cli.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}}); err == nil {
+		for _, v := range usage.Volumes {
+			if v.UsageData != nil && v.UsageData.Size >= 0 {
+				volumeSizes[v.Name] = v.UsageData.Size
+			}
+		}
+	}
+
+	for i := range rows[1:] {
+		row := &rows[i+1]
+		if size, ok := measured[row.target]; ok {
+			row.size = size
+			continue
+		}
+		if size, ok := volumeSizes[row.source]; ok && row.kind == "volume" {
+			row.size = size
+			row.note = "from docker system df"
+			continue
+		}
+		switch {
+		case !running:
+			row.note = "start the container to measure"
+		case measureErr != nil:
+			row.note = measureErr.Error()
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].size > rows[j].size })
+
+	renderDiskUsage(strings.TrimPrefix(info.Name, "/"), rows)
+}
+
+// execDiskUsage runs du inside the container and returns the size in bytes
+// of each path it could measure
+func execDiskUsage(ctx context.Context, cli *client.Client, containerID string, paths []string) (map[string]int64, error) {
+	exec, err := cli.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          append([]string{"du", "-sk"}, paths...),
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader); err != nil {
+		return nil, err
+	}
+
+	// du prints "KB<tab>path"; unreadable subdirectories only add warnings
+	sizes := map[string]int64{}
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		kb, path, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(kb, 10, 64); err == nil {
+			sizes[path] = n * 1024
+		}
+	}
+	if len(sizes) == 0 {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = "du is not available in the container"
+		}
+		return nil, fmt.Errorf("%s", message)
+	}
+	return sizes, nil
+}
+
+// renderDiskUsage prints the rows with a bar relative to the largest
+func renderDiskUsage(name string, rows []diskUsageRow) {
+	// Print header
+	fmt.Println()
+	cyan.Printf("DISK USAGE: %s\n", name)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	var largest, total int64
+	for _, row := range rows {
+		if row.size > largest {
+			largest = row.size
+		}
+		if row.size > 0 {
+			total += row.size
+		}
+	}
+
+	barWidth := 20
+	for _, row := range rows {
+		size := "unknown"
+		bar := strings.Repeat(glyphs.barEmpty, barWidth)
+		if row.size >= 0 {
+			size = formatSize(row.size)
+			filled := 0
+			if largest > 0 {
+				filled = int(row.size * int64(barWidth) / largest)
+			}
+			bar = strings.Repeat(glyphs.barFull, filled) + strings.Repeat(glyphs.barEmpty, barWidth-filled)
+		}
+
+		target := ellipsize(row.target, 35)
+		targetPadded := target + strings.Repeat(" ", 35-len(target))
+		kindPadded := row.kind + strings.Repeat(" ", max(0, 6-len(row.kind)))
+
+		// Print main line
+		fmt.Printf("%10s ", size)
+		cyan.Print(bar)
+		gray.Print(" " + glyphs.divider + " ")
+		fmt.Print(kindPadded)
+		gray.Print(" " + glyphs.divider + " ")
+		blue.Println(targetPadded)
+
+		if row.source != "" {
+			gray.Printf("  %s Source: %s\n", glyphs.detail, row.source)
+		}
+		if row.note != "" {
+			gray.Printf("  %s %s\n", glyphs.detail, row.note)
+		}
+	}
+
+	// Summary
+	fmt.Println()
+	fmt.Printf("Total: %s across %d locations\n", formatSize(total), len(rows))
+}
@@ -0,0 +1,51 @@
+package pretty
+
+import (
+	"fmt"
+	"time"
+)
+
+// errorBanner collapses repeated errors from background refreshes into a
+// single line with an occurrence count and the time range they span
+type errorBanner struct {
+	message string
+	count   int
+	first   time.Time
+	last    time.Time
+}
+
+// record adds an occurrence of err; a different error starts a new banner
+func (b *errorBanner) record(err error, now time.Time) {
+	if b.count > 0 && err.Error() == b.message {
+		b.count++
+		b.last = now
+		return
+	}
+	*b = errorBanner{message: err.Error(), count: 1, first: now, last: now}
+}
+
+// clear hides the banner once a refresh succeeds
+func (b *errorBanner) clear() {
+	*b = errorBanner{}
+}
+
+func (b errorBanner) active() bool {
+	return b.count > 0
+}
+
+// retryDelay backs off as the same error keeps recurring, up to 30s
+func (b errorBanner) retryDelay() time.Duration {
+	delay := time.Second << min(b.count, 5)
+	if delay > 30*time.Second {
+		delay = 30 * time.Second
+	}
+	return delay
+}
+
+func (b errorBanner) String() string {
+	if b.count == 1 {
+		return fmt.Sprintf("%s %s (at %s)", glyphs.failed, b.message, formatClock(b.first))
+	}
+	return fmt.Sprintf("%s %s (%dx, %s to %s)", glyphs.failed, b.message, b.count,
+		formatClock(b.first), formatClock(b.last))
+}
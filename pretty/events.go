@@ -0,0 +1,69 @@
+package pretty
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+)
+
+// PrintEvents opens a live feed of Docker events
+func PrintEvents(args []string) {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		printEventsUsage()
+		return
+	}
+
+	// Filters are handed to the Docker API as-is, like docker events
+	filterArgs := filters.NewArgs()
+	since := "10m"
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var filter string
+		switch {
+		case arg == "--since" && i+1 < len(args):
+			i++
+			since = args[i]
+			continue
+		case strings.HasPrefix(arg, "--since="):
+			since = strings.TrimPrefix(arg, "--since=")
+			continue
+		case (arg == "-f" || arg == "--filter") && i+1 < len(args):
+			i++
+			filter = args[i]
+		case strings.HasPrefix(arg, "--filter="):
+			filter = strings.TrimPrefix(arg, "--filter=")
+		default:
+			continue
+		}
+		if err := addFilter(filterArgs, filter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := LaunchEventsTUI(filterArgs, since); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printEventsUsage() {
+	fmt.Println("Usage: dockit events [OPTIONS]")
+	fmt.Println()
+	fmt.Println("Live feed of container, image, volume, and network events")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --since TIME       Show events since a timestamp or duration (default 10m)")
+	fmt.Println("  -f, --filter K=V   Filter events, like docker events (e.g. type=container)")
+	fmt.Println("  --format FORMAT    Print events with docker events instead of the TUI")
+	fmt.Println()
+	fmt.Println("Interactive TUI Controls:")
+	fmt.Println("  1-4             Show/hide container, image, volume, network events")
+	fmt.Println("  ↑↓ / j k        Move the cursor (stops following new events)")
+	fmt.Println("  g / G           Jump to oldest/newest event (G follows again)")
+	fmt.Println("  enter / i       Open the affected resource's inspect JSON in $PAGER")
+	fmt.Println("  ctrl+t          Show the recent Docker API calls with durations and status")
+	fmt.Println("  q / Esc         Quit")
+}
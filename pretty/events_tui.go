@@ -0,0 +1,427 @@
+package pretty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+)
+
+// eventTypes are the event types the feed can show or hide, in the order of
+// their toggle keys
+var eventTypes = []events.Type{
+	events.ContainerEventType,
+	events.ImageEventType,
+	events.VolumeEventType,
+	events.NetworkEventType,
+}
+
+var eventTypeStyles = map[events.Type]lipgloss.Style{
+	events.ContainerEventType: lipgloss.NewStyle().Foreground(lipgloss.Color("#00d7ff")),
+	events.ImageEventType:     lipgloss.NewStyle().Foreground(lipgloss.Color("#5f87ff")),
+	events.VolumeEventType:    lipgloss.NewStyle().Foreground(lipgloss.Color("#ffaf00")),
+	events.NetworkEventType:   lipgloss.NewStyle().Foreground(lipgloss.Color("#af87ff")),
+}
+
+// eventActionStyles color the actions worth noticing in a busy feed
+var eventActionStyles = map[events.Action]lipgloss.Style{
+	events.ActionStart:   lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff87")),
+	events.ActionCreate:  lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff87")),
+	events.ActionPull:    lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff87")),
+	events.ActionDie:     errorStyle,
+	events.ActionKill:    errorStyle,
+	events.ActionOOM:     errorStyle,
+	events.ActionDestroy: errorStyle,
+	events.ActionDelete:  errorStyle,
+	events.ActionRemove:  errorStyle,
+}
+
+// eventMsg delivers one event from the stream
+type eventMsg struct {
+	event events.Message
+	gen   int
+}
+
+// eventStreamErrMsg reports that the events stream broke
+type eventStreamErrMsg struct {
+	err error
+	gen int
+}
+
+// eventsRetryMsg reconnects the events stream after an error
+type eventsRetryMsg struct {
+	gen int
+}
+
+type eventsModel struct {
+	events     []events.Message
+	shown      []int // indexes into events whose type isn't hidden
+	hidden     map[events.Type]bool
+	cursor     int  // position in shown
+	offset     int  // first visible position in shown
+	tail       bool // keep the cursor on the newest event
+	width      int
+	height     int
+	filters    filters.Args
+	since      string
+	cli        *client.Client
+	ctx        context.Context
+	stream     <-chan events.Message
+	errs       <-chan error
+	streamGen  int
+	refreshErr errorBanner // repeated failures reconnecting the stream
+	err        error
+	tracing    bool // show the API calls debug panel
+}
+
+func (m eventsModel) Init() tea.Cmd {
+	return m.waitForEvent()
+}
+
+// connect opens the events stream, resuming after the newest event seen
+func (m *eventsModel) connect() {
+	since := m.since
+	if len(m.events) > 0 {
+		next := time.Unix(0, m.events[len(m.events)-1].TimeNano+1)
+		since = fmt.Sprintf("%d.%09d", next.Unix(), next.Nanosecond())
+	}
+	m.streamGen++
+	m.stream, m.errs = m.cli.Events(m.ctx, events.ListOptions{Since: since, Filters: m.filters})
+}
+
+func (m *eventsModel) waitForEvent() tea.Cmd {
+	stream, errs, gen := m.stream, m.errs, m.streamGen
+	return func() tea.Msg {
+		select {
+		case event := <-stream:
+			return eventMsg{event: event, gen: gen}
+		case err := <-errs:
+			return eventStreamErrMsg{err: err, gen: gen}
+		}
+	}
+}
+
+func (m eventsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampOffset()
+		return m, nil
+
+	case eventMsg:
+		if msg.gen != m.streamGen {
+			return m, nil
+		}
+		m.refreshErr.clear()
+		m.events = append(m.events, msg.event)
+		if !m.hidden[msg.event.Type] {
+			m.shown = append(m.shown, len(m.events)-1)
+		}
+		if m.tail {
+			m.cursor = max(0, len(m.shown)-1)
+		}
+		m.clampOffset()
+		return m, m.waitForEvent()
+
+	case eventStreamErrMsg:
+		if msg.gen != m.streamGen || m.ctx.Err() != nil {
+			return m, nil
+		}
+		m.refreshErr.record(msg.err, time.Now())
+		gen := m.streamGen
+		return m, tea.Tick(m.refreshErr.retryDelay(), func(time.Time) tea.Msg {
+			return eventsRetryMsg{gen: gen}
+		})
+
+	case eventsRetryMsg:
+		if msg.gen != m.streamGen {
+			return m, nil
+		}
+		m.connect()
+		return m, m.waitForEvent()
+
+	case inspectReadyMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		return m, openExternal(pagerCommand(), msg.data, "dockit-inspect-*.json")
+
+	case externalDoneMsg:
+		m.err = msg.err
+		return m, nil
+
+	case tea.KeyMsg:
+		m.err = nil
+		if m.tracing {
+			// Any key closes the debug panel
+			m.tracing = false
+			return m, nil
+		}
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "ctrl+t":
+			m.tracing = true
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.tail = false
+			}
+		case "down", "j":
+			if m.cursor < len(m.shown)-1 {
+				m.cursor++
+			}
+			m.tail = m.cursor >= len(m.shown)-1
+		case "home", "g":
+			m.cursor = 0
+			m.tail = false
+		case "end", "G":
+			m.cursor = max(0, len(m.shown)-1)
+			m.tail = true
+		case "1", "2", "3", "4":
+			t := eventTypes[msg.String()[0]-'1']
+			m.hidden[t] = !m.hidden[t]
+			m.rebuildShown()
+		case "enter", "i":
+			return m, m.inspectSelected()
+		}
+		m.clampOffset()
+	}
+
+	return m, nil
+}
+
+// rebuildShown recomputes the visible events after a type is toggled,
+// keeping the cursor on the same event where possible
+func (m *eventsModel) rebuildShown() {
+	selected := -1
+	if m.cursor < len(m.shown) {
+		selected = m.shown[m.cursor]
+	}
+
+	m.shown = m.shown[:0]
+	m.cursor = 0
+	for i, event := range m.events {
+		if m.hidden[event.Type] {
+			continue
+		}
+		if i <= selected {
+			m.cursor = len(m.shown)
+		}
+		m.shown = append(m.shown, i)
+	}
+	if m.tail {
+		m.cursor = max(0, len(m.shown)-1)
+	}
+}
+
+func (m *eventsModel) listHeight() int {
+	// Title (2 lines with margin), type toggles, and the help or error line
+	return max(1, m.height-4)
+}
+
+// clampOffset scrolls just enough to keep the cursor visible
+func (m *eventsModel) clampOffset() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.shown)-height))
+}
+
+// inspectSelected fetches the raw inspect JSON of the selected event's resource
+func (m *eventsModel) inspectSelected() tea.Cmd {
+	if m.cursor >= len(m.shown) {
+		return nil
+	}
+	event := m.events[m.shown[m.cursor]]
+	ctx, cli, id := m.ctx, m.cli, event.Actor.ID
+
+	return func() tea.Msg {
+		var data []byte
+		var err error
+		switch event.Type {
+		case events.ContainerEventType:
+			_, data, err = cli.ContainerInspectWithRaw(ctx, id, false)
+		case events.ImageEventType:
+			var raw bytes.Buffer
+			_, err = cli.ImageInspect(ctx, id, client.ImageInspectWithRawResponse(&raw))
+			data = raw.Bytes()
+		case events.VolumeEventType:
+			_, data, err = cli.VolumeInspectWithRaw(ctx, id)
+		case events.NetworkEventType:
+			_, data, err = cli.NetworkInspectWithRaw(ctx, id, network.InspectOptions{})
+		default:
+			return inspectReadyMsg{err: fmt.Errorf("can't inspect %s events", event.Type)}
+		}
+		if err != nil {
+			return inspectReadyMsg{err: fmt.Errorf("%s %s: %v", event.Type, eventName(event), err)}
+		}
+
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return inspectReadyMsg{err: err}
+		}
+		return inspectReadyMsg{data: out.Bytes()}
+	}
+}
+
+func (m eventsModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+	if small := tooSmallView(m.width, m.height, 50, 8); small != "" {
+		return small
+	}
+	if m.tracing {
+		return renderAPITrace(m.height)
+	}
+
+	var sb strings.Builder
+
+	title := "EVENTS"
+	if m.tail {
+		title += " [FOLLOW]"
+	}
+	sb.WriteString(titleStyle.Render(title))
+	sb.WriteString("\n")
+
+	// Events
+	height := m.listHeight()
+	end := min(m.offset+height, len(m.shown))
+	for pos := m.offset; pos < end; pos++ {
+		line := m.renderEvent(m.events[m.shown[pos]])
+		if pos == m.cursor {
+			sb.WriteString(cursorStyle.Render(glyphs.cursor+" ") + line)
+		} else {
+			sb.WriteString("  " + line)
+		}
+		sb.WriteString("\n")
+	}
+	for i := end - m.offset; i < height; i++ {
+		sb.WriteString("\n")
+	}
+
+	// Type toggles
+	var toggles []string
+	for i, t := range eventTypes {
+		label := fmt.Sprintf("%d:%s", i+1, t)
+		if m.hidden[t] {
+			toggles = append(toggles, helpStyle.Strikethrough(true).Render(label))
+		} else {
+			toggles = append(toggles, eventTypeStyles[t].Render(label))
+		}
+	}
+	sb.WriteString(strings.Join(toggles, " "))
+	sb.WriteString(helpStyle.Render(fmt.Sprintf("  %d events", len(m.shown))))
+	sb.WriteString("\n")
+
+	// Errors, or help
+	switch {
+	case m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+	case m.refreshErr.active():
+		sb.WriteString(errorStyle.Render(m.refreshErr.String()))
+	default:
+		sb.WriteString(helpStyle.Render("q: quit | " + glyphs.arrows + ": move | g/G: oldest/newest | 1-4: toggle types | enter: inspect"))
+	}
+
+	return sb.String()
+}
+
+// renderEvent formats one event as time, type, action, resource, and details
+func (m *eventsModel) renderEvent(event events.Message) string {
+	timestamp := formatStamp(time.Unix(0, event.TimeNano))
+
+	typeStyle, ok := eventTypeStyles[event.Type]
+	if !ok {
+		typeStyle = helpStyle
+	}
+
+	// Exec and health actions carry details after a colon
+	action := string(event.Action)
+	base, _, _ := strings.Cut(action, ":")
+	actionText := fmt.Sprintf("%-14s", ellipsize(action, 14))
+	if style, ok := eventActionStyles[events.Action(base)]; ok {
+		actionText = style.Render(actionText)
+	}
+
+	line := fmt.Sprintf("%s %s %s %s",
+		helpStyle.Render(timestamp),
+		typeStyle.Render(fmt.Sprintf("%-9s", event.Type)),
+		actionText,
+		eventName(event))
+
+	// A few attributes that explain the event
+	var details []string
+	attrs := event.Actor.Attributes
+	if event.Type == events.ContainerEventType && attrs["image"] != "" {
+		details = append(details, attrs["image"])
+	}
+	if code, ok := attrs["exitCode"]; ok {
+		details = append(details, "exit "+code)
+	}
+	if event.Type == events.NetworkEventType && attrs["container"] != "" {
+		details = append(details, "container "+formatID(attrs["container"], false))
+	}
+	if len(details) > 0 {
+		line += helpStyle.Render("  " + strings.Join(details, ", "))
+	}
+
+	return line
+}
+
+// eventName is the resource's name when the event carries one, else its ID
+func eventName(event events.Message) string {
+	if name := event.Actor.Attributes["name"]; name != "" {
+		return name
+	}
+	if event.Type == events.ContainerEventType || event.Type == events.NetworkEventType {
+		return formatID(event.Actor.ID, false)
+	}
+	return event.Actor.ID
+}
+
+// LaunchEventsTUI shows a live feed of Docker events matching filterArgs,
+// starting with those since the given time
+func LaunchEventsTUI(filterArgs filters.Args, since string) error {
+	cli, err := newClient()
+	if err != nil {
+		return fmt.Errorf("error creating Docker client: %v", err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	model := eventsModel{
+		hidden:  map[events.Type]bool{},
+		tail:    true,
+		filters: filterArgs,
+		since:   since,
+		cli:     cli,
+		ctx:     ctx,
+	}
+	model.connect()
+
+	p := newProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package pretty
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// execValueFlags are docker exec flags that take a separate value argument
+var execValueFlags = map[string]bool{
+	"-e": true, "--env": true,
+	"--env-file":    true,
+	"-u":            true,
+	"--user":        true,
+	"-w":            true,
+	"--workdir":     true,
+	"--detach-keys": true,
+}
+
+// RunExec runs docker exec, optionally capturing output to a file with --output
+func RunExec(args []string) {
+	flags, outputPath, containerName, command := parseExecArgs(args)
+
+	flags, notes := pipeStdinFlags(flags)
+	for _, note := range notes {
+		gray.Fprintf(os.Stderr, "%s\n", note)
+	}
+
+	dockerArgs := append([]string{"exec"}, flags...)
+	if containerName != "" {
+		dockerArgs = append(append(dockerArgs, containerName), command...)
+	}
+
+	// Hand docker the stdin file itself rather than copying through a pipe, so
+	// EOF reaches docker directly and it half-closes the container's stdin
+	cmd := DockerCommand(dockerArgs...)
+	cmd.Stdin = os.Stdin
+
+	if outputPath == "" {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		os.Exit(runCommand(cmd))
+	}
+
+	// Tee output so the user still sees it live
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+	started := time.Now()
+	exitCode := runCommand(cmd)
+	capture := execCapture{
+		container: containerName,
+		command:   command,
+		started:   started,
+		duration:  time.Since(started),
+		exitCode:  exitCode,
+		stdout:    stdout.Bytes(),
+		stderr:    stderr.Bytes(),
+	}
+
+	if err := os.WriteFile(outputPath, capture.report(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+		os.Exit(1)
+	}
+	gray.Fprintf(os.Stderr, "Output saved to %s (exit code %d)\n", outputPath, exitCode)
+
+	os.Exit(exitCode)
+}
+
+// execCapture is the output of a finished one-shot exec and what ran it
+type execCapture struct {
+	container string
+	command   []string
+	started   time.Time
+	duration  time.Duration
+	exitCode  int
+	stdout    []byte
+	stderr    []byte
+}
+
+// report is the capture as saved for a bug report: a metadata header, then
+// stdout and stderr
+func (c execCapture) report() []byte {
+	var sb strings.Builder
+	sb.WriteString("# dockit exec capture\n")
+	sb.WriteString(fmt.Sprintf("# container: %s\n", c.container))
+	sb.WriteString(fmt.Sprintf("# command:   %s\n", strings.Join(c.command, " ")))
+	sb.WriteString(fmt.Sprintf("# started:   %s\n", c.started.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("# duration:  %s\n", c.duration.Round(time.Millisecond)))
+	sb.WriteString(fmt.Sprintf("# exit code: %d\n", c.exitCode))
+	sb.WriteString("\n## stdout\n")
+	sb.Write(c.stdout)
+	sb.WriteString("\n## stderr\n")
+	sb.Write(c.stderr)
+	return []byte(sb.String())
+}
+
+// parseExecArgs strips dockit's --output flag from the exec options and
+// identifies the container and the command being run
+func parseExecArgs(args []string) (flags []string, outputPath, containerName string, command []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--output":
+			if i+1 < len(args) {
+				outputPath = args[i+1]
+				i++
+			}
+			continue
+		case strings.HasPrefix(arg, "--output="):
+			outputPath = strings.TrimPrefix(arg, "--output=")
+			continue
+		case execValueFlags[arg]:
+			flags = append(flags, arg)
+			if i+1 < len(args) {
+				flags = append(flags, args[i+1])
+				i++
+			}
+			continue
+		case strings.HasPrefix(arg, "-"):
+			flags = append(flags, arg)
+			continue
+		}
+
+		// First positional argument is the container, the rest is the command
+		containerName = arg
+		command = args[i+1:]
+		break
+	}
+
+	return flags, outputPath, containerName, command
+}
+
+// runCommand runs cmd and returns its exit code
+func runCommand(cmd *exec.Cmd) int {
+	if err := cmd.Run(); err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			return exitError.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "Error running docker command: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package pretty
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// externalDoneMsg reports that an external viewer or editor exited
+type externalDoneMsg struct {
+	err error
+}
+
+// pagerCommand returns the configured pager: the config, then $PAGER, then less
+func pagerCommand() string {
+	if config.Viewer.Pager != "" {
+		return config.Viewer.Pager
+	}
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+	return "less -R"
+}
+
+// editorCommand returns the configured editor: the config, then $EDITOR, then vi
+func editorCommand() string {
+	if config.Viewer.Editor != "" {
+		return config.Viewer.Editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return "vi"
+}
+
+// openExternal suspends the TUI and shows data in program. A program whose
+// last argument is "-" (e.g. "code -") reads the data on stdin; otherwise it
+// is written to a temp file whose path is appended to the command.
+func openExternal(program string, data []byte, pattern string) tea.Cmd {
+	fields := strings.Fields(program)
+	if len(fields) == 0 {
+		return func() tea.Msg { return externalDoneMsg{err: fmt.Errorf("no viewer configured")} }
+	}
+
+	cleanup := func() {}
+	var cmd *exec.Cmd
+	if fields[len(fields)-1] == "-" {
+		cmd = exec.Command(fields[0], fields[1:]...)
+		cmd.Stdin = bytes.NewReader(data)
+	} else {
+		file, err := os.CreateTemp("", pattern)
+		if err != nil {
+			return func() tea.Msg { return externalDoneMsg{err: err} }
+		}
+		_, err = file.Write(data)
+		file.Close()
+		if err != nil {
+			os.Remove(file.Name())
+			return func() tea.Msg { return externalDoneMsg{err: err} }
+		}
+		cleanup = func() { os.Remove(file.Name()) }
+		cmd = exec.Command(fields[0], append(fields[1:], file.Name())...)
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		cleanup()
+		if err != nil {
+			err = fmt.Errorf("%s: %v", fields[0], err)
+		}
+		return externalDoneMsg{err: err}
+	})
+}
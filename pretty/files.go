@@ -0,0 +1,269 @@
+package pretty
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	// maxListEntries bounds how much of a directory's archive is read to
+	// list it, since the archive API always includes subdirectories
+	maxListEntries = 20000
+
+	// maxViewBytes is how much of a file the viewer loads
+	maxViewBytes = 512 * 1024
+)
+
+// fileEntry is a file or directory inside a container
+type fileEntry struct {
+	name   string
+	size   int64
+	mode   os.FileMode
+	isDir  bool
+	link   string // symlink target
+	parent string
+}
+
+func (e fileEntry) path() string {
+	return path.Join(e.parent, e.name)
+}
+
+// PrintFiles opens the container file browser
+func PrintFiles(args []string) {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit files CONTAINER [PATH]")
+		os.Exit(1)
+	}
+
+	dir := "/"
+	if len(positional) > 1 {
+		dir = path.Clean("/" + positional[1])
+	}
+
+	if err := LaunchFilesTUI(positional[0], dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// archiveChildren maps the names in a path's archive to paths relative to
+// the archived directory; the first entry is the directory itself
+type archiveChildren struct {
+	prefix string
+}
+
+func (a *archiveChildren) relative(name string) (string, bool) {
+	clean := path.Clean(name)
+	if a.prefix == "" {
+		a.prefix = clean + "/"
+		if clean == "/" {
+			a.prefix = "/"
+		}
+		return "", false
+	}
+	if a.prefix == "./" {
+		return clean, true
+	}
+	return strings.CutPrefix(clean, a.prefix)
+}
+
+// listDir lists a container directory through the archive API. Reading stops
+// after maxListEntries archive entries, in which case truncated is set.
+func listDir(ctx context.Context, cli *client.Client, containerID, dir string) (entries []fileEntry, truncated bool, err error) {
+	reader, _, err := cli.CopyFromContainer(ctx, containerID, dir)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	var names archiveChildren
+	for read := 0; ; read++ {
+		if read == maxListEntries {
+			truncated = true
+			break
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		rel, ok := names.relative(header.Name)
+		if !ok || rel == "" || strings.Contains(rel, "/") {
+			// The directory itself, or something deeper than its children
+			continue
+		}
+		entries = append(entries, fileEntry{
+			name:   rel,
+			size:   header.Size,
+			mode:   header.FileInfo().Mode(),
+			isDir:  header.Typeflag == tar.TypeDir,
+			link:   header.Linkname,
+			parent: dir,
+		})
+	}
+
+	// Directories first, then by name
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].isDir != entries[j].isDir {
+			return entries[i].isDir
+		}
+		return entries[i].name < entries[j].name
+	})
+	return entries, truncated, nil
+}
+
+// readContainerFile returns up to maxViewBytes of a container file
+func readContainerFile(ctx context.Context, cli *client.Client, containerID, file string) (data []byte, truncated bool, err error) {
+	reader, stat, err := cli.CopyFromContainer(ctx, containerID, file)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	if stat.Mode.IsDir() {
+		return nil, false, fmt.Errorf("%s is a directory", file)
+	}
+
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return nil, false, err
+	}
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, tr, maxViewBytes+1)
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	if n > maxViewBytes {
+		return buf.Bytes()[:maxViewBytes], true, nil
+	}
+	return buf.Bytes(), false, nil
+}
+
+// downloadPath copies a container file or directory into destDir on the
+// host, returning where it was written and how many files it contained
+func downloadPath(ctx context.Context, cli *client.Client, containerID, src, destDir string) (string, int, error) {
+	target := filepath.Join(destDir, path.Base(src))
+	if _, err := os.Lstat(target); err == nil {
+		return "", 0, fmt.Errorf("%s already exists", target)
+	}
+
+	reader, _, err := cli.CopyFromContainer(ctx, containerID, src)
+	if err != nil {
+		return "", 0, err
+	}
+	defer reader.Close()
+
+	count, err := extractTar(reader, destDir)
+	return target, count, err
+}
+
+// extractTar unpacks an archive into destDir, refusing entries that would
+// land outside it. Everything is written through an os.Root, so a symlink
+// from earlier in the archive can't carry a later entry out of destDir.
+func extractTar(r io.Reader, destDir string) (int, error) {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return 0, err
+	}
+	root, err := os.OpenRoot(destDir)
+	if err != nil {
+		return 0, err
+	}
+	defer root.Close()
+
+	count := 0
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if name == "" {
+			continue
+		}
+		target := filepath.FromSlash(name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = mkdirAllIn(root, target, header.FileInfo().Mode().Perm()|0700)
+		case tar.TypeReg:
+			if err = mkdirAllIn(root, filepath.Dir(target), 0755); err != nil {
+				break
+			}
+			var file *os.File
+			file, err = root.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, header.FileInfo().Mode().Perm())
+			if err != nil {
+				break
+			}
+			_, err = io.Copy(file, tr)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err == nil {
+				count++
+			}
+		case tar.TypeSymlink:
+			// Links are kept as links; a dangling target isn't an error, since
+			// anything written through one later still goes through root.
+			// os.Root can't make links yet, but mkdirAllIn has checked the
+			// parent resolves inside destDir.
+			if err = mkdirAllIn(root, filepath.Dir(target), 0755); err != nil {
+				break
+			}
+			err = os.Symlink(header.Linkname, filepath.Join(destDir, target))
+		}
+		if err != nil {
+			return count, fmt.Errorf("extracting %s: %w", header.Name, err)
+		}
+	}
+}
+
+// mkdirAllIn makes dir and any missing parents under root. One that exists
+// already has to be a directory inside root; root won't follow a symlink out.
+func mkdirAllIn(root *os.Root, dir string, perm os.FileMode) error {
+	if dir == "." {
+		return nil
+	}
+	if err := mkdirAllIn(root, filepath.Dir(dir), perm); err != nil {
+		return err
+	}
+	err := root.Mkdir(dir, perm)
+	if err == nil || !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	info, err := root.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
@@ -0,0 +1,503 @@
+package pretty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/client"
+)
+
+var (
+	dirStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#5f87ff")).Bold(true)
+	linkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#5fd7af"))
+	commentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+	stringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#87ff5f"))
+	keyStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#00d7ff"))
+)
+
+// dirLoadedMsg delivers a directory listing
+type dirLoadedMsg struct {
+	dir       string
+	entries   []fileEntry
+	truncated bool
+	err       error
+}
+
+// fileLoadedMsg delivers the contents of a file to view
+type fileLoadedMsg struct {
+	file      string
+	data      []byte
+	truncated bool
+	err       error
+}
+
+// downloadDoneMsg reports a finished download
+type downloadDoneMsg struct {
+	target string
+	count  int
+	err    error
+}
+
+type filesModel struct {
+	containerID string
+	name        string
+	cli         *client.Client
+	ctx         context.Context
+	dir         string
+	entries     []fileEntry
+	truncated   bool
+	cursor      int
+	offset      int
+	cursorByDir map[string]int // restores the cursor when going back up
+	loading     bool
+	status      string
+	err         error
+	width       int
+	height      int
+
+	// Copy-to prompt
+	prompting bool
+	copySrc   string
+	destInput textinput.Model
+
+	// File viewer
+	viewing      string
+	lines        []string
+	viewTrunc    bool
+	binary       bool
+	scrollOffset int
+}
+
+func (m filesModel) Init() tea.Cmd {
+	return m.loadDir(m.dir)
+}
+
+func (m *filesModel) loadDir(dir string) tea.Cmd {
+	m.loading = true
+	ctx, cli, id := m.ctx, m.cli, m.containerID
+	return func() tea.Msg {
+		entries, truncated, err := listDir(ctx, cli, id, dir)
+		return dirLoadedMsg{dir: dir, entries: entries, truncated: truncated, err: err}
+	}
+}
+
+func (m *filesModel) loadFile(file string) tea.Cmd {
+	m.loading = true
+	ctx, cli, id := m.ctx, m.cli, m.containerID
+	return func() tea.Msg {
+		data, truncated, err := readContainerFile(ctx, cli, id, file)
+		return fileLoadedMsg{file: file, data: data, truncated: truncated, err: err}
+	}
+}
+
+// openLink opens a symlink's target, which may be a directory or a file
+func (m *filesModel) openLink(target string) tea.Cmd {
+	m.loading = true
+	ctx, cli, id := m.ctx, m.cli, m.containerID
+	return func() tea.Msg {
+		stat, err := cli.ContainerStatPath(ctx, id, target)
+		if err != nil {
+			return fileLoadedMsg{file: target, err: err}
+		}
+		if stat.Mode.IsDir() {
+			entries, truncated, err := listDir(ctx, cli, id, target)
+			return dirLoadedMsg{dir: target, entries: entries, truncated: truncated, err: err}
+		}
+		data, truncated, err := readContainerFile(ctx, cli, id, target)
+		return fileLoadedMsg{file: target, data: data, truncated: truncated, err: err}
+	}
+}
+
+func (m *filesModel) download(src, destDir string) tea.Cmd {
+	m.status = "Downloading " + src + "..."
+	ctx, cli, id := m.ctx, m.cli, m.containerID
+	return func() tea.Msg {
+		target, count, err := downloadPath(ctx, cli, id, src, destDir)
+		return downloadDoneMsg{target: target, count: count, err: err}
+	}
+}
+
+func (m filesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampOffset()
+		return m, nil
+
+	case dirLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.cursorByDir[m.dir] = m.cursor
+		m.dir = msg.dir
+		m.entries = msg.entries
+		m.truncated = msg.truncated
+		m.cursor = min(m.cursorByDir[msg.dir], max(0, len(m.entries)-1))
+		m.offset = 0
+		m.clampOffset()
+		return m, nil
+
+	case fileLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.viewing = msg.file
+		m.viewTrunc = msg.truncated
+		m.binary = bytes.IndexByte(msg.data[:min(len(msg.data), 8000)], 0) >= 0
+		m.lines = strings.Split(strings.ReplaceAll(string(msg.data), "\t", "    "), "\n")
+		m.scrollOffset = 0
+		return m, nil
+
+	case downloadDoneMsg:
+		m.status = ""
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Saved %s (%d files)", msg.target, msg.count)
+		return m, nil
+
+	case tea.KeyMsg:
+		m.err = nil
+		if m.prompting {
+			return m, m.updatePrompt(msg)
+		}
+		if m.viewing != "" {
+			return m, m.updateViewer(msg)
+		}
+		return m, m.updateList(msg)
+	}
+
+	return m, nil
+}
+
+func (m *filesModel) updateList(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "pgup":
+		m.cursor = max(0, m.cursor-m.listHeight())
+	case "pgdown":
+		m.cursor = max(0, min(len(m.entries)-1, m.cursor+m.listHeight()))
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(0, len(m.entries)-1)
+	case "enter", "right", "l":
+		if m.loading || len(m.entries) == 0 {
+			return nil
+		}
+		entry := m.entries[m.cursor]
+		if entry.isDir {
+			return m.loadDir(entry.path())
+		}
+		if entry.link != "" {
+			target := entry.link
+			if !path.IsAbs(target) {
+				target = path.Join(entry.parent, target)
+			}
+			return m.openLink(target)
+		}
+		return m.loadFile(entry.path())
+	case "backspace", "left", "h":
+		if m.loading || m.dir == "/" {
+			return nil
+		}
+		return m.loadDir(path.Dir(m.dir))
+	case "d":
+		if len(m.entries) > 0 {
+			return m.download(m.entries[m.cursor].path(), ".")
+		}
+	case "y":
+		if len(m.entries) > 0 {
+			return m.promptCopy(m.entries[m.cursor].path())
+		}
+	}
+	m.clampOffset()
+	return nil
+}
+
+func (m *filesModel) updateViewer(msg tea.KeyMsg) tea.Cmd {
+	maxScroll := max(0, len(m.lines)-m.listHeight())
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+	case "q", "esc", "backspace", "left", "h":
+		m.viewing = ""
+		m.lines = nil
+	case "up", "k":
+		m.scrollOffset = max(0, m.scrollOffset-1)
+	case "down", "j":
+		m.scrollOffset = min(maxScroll, m.scrollOffset+1)
+	case "pgup":
+		m.scrollOffset = max(0, m.scrollOffset-m.listHeight())
+	case "pgdown":
+		m.scrollOffset = min(maxScroll, m.scrollOffset+m.listHeight())
+	case "home", "g":
+		m.scrollOffset = 0
+	case "end", "G":
+		m.scrollOffset = maxScroll
+	case "d":
+		return m.download(m.viewing, ".")
+	case "y":
+		return m.promptCopy(m.viewing)
+	case "v":
+		return openExternal(pagerCommand(), []byte(strings.Join(m.lines, "\n")), "dockit-file-*"+path.Ext(m.viewing))
+	}
+	return nil
+}
+
+// promptCopy asks for the host directory to copy src into
+func (m *filesModel) promptCopy(src string) tea.Cmd {
+	m.prompting = true
+	m.copySrc = src
+	m.destInput.SetValue("")
+	m.destInput.Focus()
+	return textinput.Blink
+}
+
+func (m *filesModel) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+	case "esc":
+		m.prompting = false
+		m.destInput.Blur()
+		return nil
+	case "enter":
+		m.prompting = false
+		m.destInput.Blur()
+		dest := strings.TrimSpace(m.destInput.Value())
+		if dest == "" {
+			dest = "."
+		}
+		return m.download(m.copySrc, dest)
+	}
+
+	var cmd tea.Cmd
+	m.destInput, cmd = m.destInput.Update(msg)
+	return cmd
+}
+
+func (m *filesModel) listHeight() int {
+	// Title (2 lines with margin), status line, and help
+	return max(1, m.height-4)
+}
+
+// clampOffset scrolls just enough to keep the cursor visible
+func (m *filesModel) clampOffset() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+func (m filesModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+	if small := tooSmallView(m.width, m.height, 40, 8); small != "" {
+		return small
+	}
+	if m.viewing != "" {
+		return m.renderViewer()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("FILES: %s:%s", m.name, m.dir)))
+	sb.WriteString("\n")
+
+	height := m.listHeight()
+	end := min(m.offset+height, len(m.entries))
+	for i := m.offset; i < end; i++ {
+		entry := m.entries[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+
+		name := entry.name
+		size := formatSize(entry.size)
+		switch {
+		case entry.isDir:
+			name = dirStyle.Render(name + "/")
+			size = ""
+		case entry.link != "":
+			name = linkStyle.Render(name) + helpStyle.Render(" -> "+entry.link)
+			size = ""
+		}
+		sb.WriteString(fmt.Sprintf("%s%s %9s  %s\n", cursor, helpStyle.Render(entry.mode.String()), size, name))
+	}
+	for i := end - m.offset; i < height; i++ {
+		sb.WriteString("\n")
+	}
+
+	// Status line
+	switch {
+	case m.prompting:
+		sb.WriteString(searchBarStyle.Render("Copy "+path.Base(m.copySrc)+" to: ") + m.destInput.View())
+	case m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+	case m.loading:
+		sb.WriteString(helpStyle.Render("Loading..."))
+	case m.status != "":
+		sb.WriteString(selectedStyle.Render(m.status))
+	case m.truncated:
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("%d entries (listing stopped early; directory is very large)", len(m.entries))))
+	default:
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("%d entries", len(m.entries))))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(helpStyle.Render("q: quit | " + glyphs.arrows + ": move | enter: open | backspace: up | d: download to current directory | y: copy to..."))
+	return sb.String()
+}
+
+func (m *filesModel) renderViewer() string {
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("FILES: %s:%s", m.name, m.viewing)))
+	sb.WriteString("\n")
+
+	height := m.listHeight()
+	if m.binary {
+		sb.WriteString(helpStyle.Render("Binary file; press d to download it"))
+		sb.WriteString(strings.Repeat("\n", height))
+	} else {
+		numberWidth := len(fmt.Sprint(len(m.lines)))
+		lang := highlightLanguage(m.viewing)
+		end := min(m.scrollOffset+height, len(m.lines))
+		for i := m.scrollOffset; i < end; i++ {
+			number := helpStyle.Render(fmt.Sprintf("%*d ", numberWidth, i+1))
+			sb.WriteString(number + highlightLine(lang, m.lines[i]) + "\n")
+		}
+		for i := end - m.scrollOffset; i < height; i++ {
+			sb.WriteString("\n")
+		}
+	}
+
+	// Status line
+	switch {
+	case m.prompting:
+		sb.WriteString(searchBarStyle.Render("Copy "+path.Base(m.copySrc)+" to: ") + m.destInput.View())
+	case m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+	case m.status != "":
+		sb.WriteString(selectedStyle.Render(m.status))
+	case m.viewTrunc:
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("Showing the first %s; download for the full file", formatSize(maxViewBytes))))
+	default:
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("%d lines", len(m.lines))))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(helpStyle.Render("q: back | " + glyphs.arrows + ": scroll | g/G: top/bottom | v: pager | d: download | y: copy to..."))
+	return sb.String()
+}
+
+// highlightLang describes the little syntax the viewer colors
+type highlightLang struct {
+	comment string         // line comment prefix
+	key     *regexp.Regexp // leading key of key: value or key = value lines
+}
+
+var (
+	yamlKeyPattern   = regexp.MustCompile(`^(\s*-?\s*)([\w.-]+)(\s*:)`)
+	assignKeyPattern = regexp.MustCompile(`^(\s*)([\w.-]+)(\s*=)`)
+	stringPattern    = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'[^']*'`)
+)
+
+// highlightLanguage picks the comment and key syntax from a file's name
+func highlightLanguage(file string) highlightLang {
+	base := path.Base(file)
+	switch ext := strings.ToLower(path.Ext(base)); {
+	case ext == ".yml" || ext == ".yaml":
+		return highlightLang{comment: "#", key: yamlKeyPattern}
+	case ext == ".ini" || ext == ".toml" || ext == ".env" || ext == ".properties" || ext == ".conf" || ext == ".cnf" || base == ".env":
+		return highlightLang{comment: "#", key: assignKeyPattern}
+	case ext == ".sh" || ext == ".py" || ext == ".rb" || base == "Dockerfile" || base == "Makefile":
+		return highlightLang{comment: "#"}
+	case ext == ".go" || ext == ".js" || ext == ".ts" || ext == ".java" || ext == ".c" || ext == ".h" || ext == ".rs" || ext == ".json":
+		return highlightLang{comment: "//"}
+	}
+	return highlightLang{comment: "#"}
+}
+
+// highlightLine colors comments, leading keys, and quoted strings
+func highlightLine(lang highlightLang, line string) string {
+	if lang.comment != "" && strings.HasPrefix(strings.TrimSpace(line), lang.comment) {
+		return commentStyle.Render(line)
+	}
+
+	prefix := ""
+	if lang.key != nil {
+		if match := lang.key.FindStringSubmatchIndex(line); match != nil {
+			prefix = line[match[2]:match[3]] + keyStyle.Render(line[match[4]:match[5]]) + line[match[6]:match[7]]
+			line = line[match[1]:]
+		}
+	}
+	return prefix + stringPattern.ReplaceAllStringFunc(line, func(s string) string {
+		return stringStyle.Render(s)
+	})
+}
+
+// LaunchFilesTUI browses a container's filesystem starting at dir
+func LaunchFilesTUI(containerID, dir string) error {
+	cli, err := newClient()
+	if err != nil {
+		return fmt.Errorf("error creating Docker client: %v", err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("error inspecting container: %v", err)
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "host directory (default: current directory)"
+	ti.CharLimit = 256
+	ti.Width = 50
+
+	model := filesModel{
+		containerID: info.ID,
+		name:        strings.TrimPrefix(info.Name, "/"),
+		cli:         cli,
+		ctx:         ctx,
+		dir:         dir,
+		cursorByDir: map[string]int{},
+		destInput:   ti,
+	}
+
+	p := newProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %v", err)
+	}
+
+	return nil
+}
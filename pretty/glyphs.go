@@ -0,0 +1,138 @@
+package pretty
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// glyphSet holds the icons and box-drawing characters used in output
+type glyphSet struct {
+	rule     string // header underline
+	divider  string // column separator
+	running  string
+	stopped  string
+	paused   string
+	failed   string
+	ok       string
+	warn     string
+	detail   string // prefix for indented detail lines
+	clock    string
+	pull     string
+	logs     string
+	prune    string
+	bell     string
+	cursor   string
+	barFull  string
+	barEmpty string
+	arrows   string // scroll keys in help text
+	sortUp   string // sort direction in table headers
+	sortDown string
+	next     string // separator between ordered steps
+	spark    string // sparkline levels, lowest first
+}
+
+var unicodeGlyphs = glyphSet{
+	rule:     "─",
+	divider:  "│",
+	running:  "●",
+	stopped:  "○",
+	paused:   "⏸",
+	failed:   "✖",
+	ok:       "✔",
+	warn:     "⚠",
+	detail:   "↪",
+	clock:    "⏱",
+	pull:     "⬇ ",
+	logs:     "📋 ",
+	prune:    "🧹 ",
+	bell:     "🔔 ",
+	cursor:   "▸",
+	barFull:  "█",
+	barEmpty: "░",
+	arrows:   "↑↓",
+	sortUp:   "↑",
+	sortDown: "↓",
+	next:     "→",
+	spark:    "▁▂▃▄▅▆▇█",
+}
+
+// asciiGlyphs is used on terminals that can't draw the Unicode set, such as
+// the legacy Windows console
+var asciiGlyphs = glyphSet{
+	rule:     "-",
+	divider:  "|",
+	running:  "*",
+	stopped:  "o",
+	paused:   "=",
+	failed:   "x",
+	ok:       "+",
+	warn:     "!",
+	detail:   ">",
+	clock:    "~",
+	pull:     "",
+	logs:     "",
+	prune:    "",
+	bell:     "! ",
+	cursor:   ">",
+	barFull:  "#",
+	barEmpty: ".",
+	arrows:   "j/k",
+	sortUp:   "^",
+	sortDown: "v",
+	next:     "->",
+	spark:    "_.-=+*#@",
+}
+
+// glyphs is the active set, chosen by applyRenderProfile
+var glyphs = unicodeGlyphs
+
+// applyRenderProfile picks the glyph set from DOCKIT_RENDER or the config
+// ("auto", "unicode", or "ascii"), detecting the terminal for "auto"
+func applyRenderProfile() {
+	profile := config.Render
+	if value := os.Getenv("DOCKIT_RENDER"); value != "" {
+		profile = value
+	}
+
+	switch strings.ToLower(profile) {
+	case "ascii":
+		glyphs = asciiGlyphs
+	case "unicode":
+		glyphs = unicodeGlyphs
+	default:
+		if unicodeSupported() {
+			glyphs = unicodeGlyphs
+		} else {
+			glyphs = asciiGlyphs
+		}
+	}
+}
+
+// unicodeSupported guesses whether the terminal can render the Unicode glyphs
+func unicodeSupported() bool {
+	term := os.Getenv("TERM")
+	if term == "dumb" || term == "linux" {
+		// The Linux virtual console only has a small built-in font
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		// Legacy conhost lacks most of these glyphs; newer hosts identify themselves
+		for _, name := range []string{"WT_SESSION", "TERM_PROGRAM", "ConEmuANSI"} {
+			if os.Getenv(name) != "" {
+				return true
+			}
+		}
+		return term != ""
+	}
+
+	// Without a UTF-8 locale multi-byte glyphs come out garbled
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// groupParallel is how many containers a group stop or start works on at
+// once unless --parallel says otherwise
+const groupParallel = 5
+
+// containerGroup picks containers by compose project and labels; a
+// container has to match all of them
+type containerGroup struct {
+	project string
+	labels  []string // KEY or KEY=VALUE
+}
+
+// filters is the group as a container list filter
+func (g containerGroup) filters() filters.Args {
+	args := filters.NewArgs()
+	if g.project != "" {
+		args.Add("label", composeProjectLabel+"="+g.project)
+	}
+	for _, label := range g.labels {
+		args.Add("label", label)
+	}
+	return args
+}
+
+// String describes the group for titles, e.g. "project shop, label tier=web"
+func (g containerGroup) String() string {
+	var parts []string
+	if g.project != "" {
+		parts = append(parts, "project "+g.project)
+	}
+	for _, label := range g.labels {
+		parts = append(parts, "label "+label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseGroupArgs reads --project, --label, and --parallel, and with
+// withTimeout -t, exiting with usage on anything else
+func parseGroupArgs(args []string, usage string, withTimeout bool) (group containerGroup, parallel int, timeout *int) {
+	fail := func(format string, a ...any) {
+		fmt.Fprintf(os.Stderr, "Error: "+format+"\n", a...)
+		fmt.Println("Usage: " + usage)
+		os.Exit(1)
+	}
+	parallel = groupParallel
+	for i := 0; i < len(args); i++ {
+		name, value, inline := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(name, "--") {
+			name, value, inline = args[i], "", false
+		}
+		takesValue := name == "--project" || name == "--label" || name == "--parallel" || withTimeout && (name == "-t" || name == "--timeout")
+		if takesValue && !inline {
+			if i+1 >= len(args) {
+				fail("%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch {
+		case name == "--project":
+			group.project = value
+		case name == "--label":
+			group.labels = append(group.labels, value)
+		case name == "--parallel":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				fail("invalid --parallel %q (use a number of containers, 1 or more)", value)
+			}
+			parallel = n
+		case takesValue:
+			seconds, err := parseStopTimeout(value)
+			if err != nil {
+				fail("%v", err)
+			}
+			timeout = &seconds
+		default:
+			fail("unknown option %q", args[i])
+		}
+	}
+	if group.project == "" && len(group.labels) == 0 {
+		fail("--project or --label required")
+	}
+	return group, parallel, timeout
+}
+
+// listGroup lists the group's containers, running ones only unless all
+func listGroup(ctx context.Context, cli *client.Client, group containerGroup, all bool) []container.Summary {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: all, Filters: group.filters()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+	return containers
+}
+
+// StopGroup stops the running containers of a compose project or matching
+// labels with the live stop list, a few at a time
+func StopGroup(args []string) {
+	group, parallel, timeout := parseGroupArgs(args, "dockit stop (--project NAME | --label KEY[=VALUE])... [--parallel N] [-t TIMEOUT]", true)
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	containers := listGroup(ctx, cli, group, false)
+	if len(containers) == 0 {
+		gray.Printf("No running containers in %s\n", group)
+		return
+	}
+
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+
+	fmt.Println()
+	title := fmt.Sprintf("STOPPING %d CONTAINERS (%s, %d at a time)", len(ids), group, parallel)
+	targets := stopContainers(ctx, cli, title, ids, parallel, timeout)
+	if printStopSummary(targets) {
+		os.Exit(1)
+	}
+}
+
+// StartGroup starts the stopped containers of a compose project or matching
+// labels, a few at a time, reporting each as it finishes
+func StartGroup(args []string) {
+	group, parallel, _ := parseGroupArgs(args, "dockit start (--project NAME | --label KEY[=VALUE])... [--parallel N]", false)
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	containers := listGroup(ctx, cli, group, true)
+	if len(containers) == 0 {
+		gray.Printf("No containers in %s\n", group)
+		return
+	}
+
+	var pending []container.Summary
+	running := 0
+	for _, c := range containers {
+		// Paused and restarting containers are up already; start refuses them
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" {
+			running++
+			continue
+		}
+		pending = append(pending, c)
+	}
+	if len(pending) == 0 {
+		gray.Printf("All %d containers in %s are running already\n", running, group)
+		return
+	}
+
+	fmt.Println()
+	cyan.Printf("STARTING %d CONTAINERS (%s, %d at a time)\n", len(pending), group, parallel)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	// Results print as they come in, so a slow one doesn't hold up the rest
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallel)
+	started, failed, step := 0, 0, 0
+	for _, c := range pending {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(c container.Summary) {
+			defer wg.Done()
+			name := formatID(c.ID, false)
+			if len(c.Names) > 0 {
+				name = strings.TrimPrefix(c.Names[0], "/")
+			}
+			err := cli.ContainerStart(ctx, c.ID, container.StartOptions{})
+			<-slots
+
+			mu.Lock()
+			defer mu.Unlock()
+			step++
+			gray.Printf("[%d/%d] ", step, len(pending))
+			fmt.Printf("%s ", name)
+			if err != nil {
+				red.Print(glyphs.failed + " ")
+				red.Println(err)
+				failed++
+				return
+			}
+			green.Print(glyphs.ok + " ")
+			gray.Println("started")
+			started++
+		}(c)
+	}
+	wg.Wait()
+
+	fmt.Println()
+	green.Printf("%d started", started)
+	if failed > 0 {
+		red.Printf(", %d failed", failed)
+	}
+	if running > 0 {
+		gray.Printf(", %d already running", running)
+	}
+	fmt.Println()
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
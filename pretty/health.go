@@ -0,0 +1,112 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrintHealth shows a container's healthcheck status and its most recent probes
+func PrintHealth(args []string) {
+	limit := 5
+	var containerID string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-n" || arg == "--tail") && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "Error: invalid probe count %q\n", args[i])
+				os.Exit(1)
+			}
+			limit = n
+		case !strings.HasPrefix(arg, "-"):
+			containerID = arg
+		}
+	}
+
+	if containerID == "" {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit health [-n PROBES] CONTAINER")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	info, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error inspecting container: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Print header
+	fmt.Println()
+	cyan.Printf("HEALTH: %s\n", strings.TrimPrefix(info.Name, "/"))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	if info.Config != nil && info.Config.Healthcheck != nil && len(info.Config.Healthcheck.Test) > 0 {
+		test := info.Config.Healthcheck.Test
+		if test[0] == "CMD" || test[0] == "CMD-SHELL" {
+			test = test[1:]
+		}
+		printDetail("Check", strings.Join(test, " "))
+		if interval := info.Config.Healthcheck.Interval; interval > 0 {
+			printDetail("Interval", interval.String())
+		}
+	}
+
+	if info.State == nil || info.State.Health == nil {
+		gray.Println("No healthcheck configured for this container")
+		return
+	}
+	health := info.State.Health
+
+	gray.Printf("  %-12s ", "Status:")
+	healthColor(health.Status).Println(health.Status)
+	printDetail("Failures", strconv.Itoa(health.FailingStreak)+" in a row")
+
+	// Most recent probes, newest last
+	probes := health.Log
+	if len(probes) > limit {
+		probes = probes[len(probes)-limit:]
+	}
+
+	fmt.Println()
+	cyan.Println("RECENT PROBES")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	if len(probes) == 0 {
+		gray.Println("No probes have run yet")
+		return
+	}
+
+	for _, probe := range probes {
+		statusColor, indicator := green, glyphs.ok
+		if probe.ExitCode != 0 {
+			statusColor, indicator = red, glyphs.failed
+		}
+
+		statusColor.Print(indicator)
+		fmt.Print(" ")
+		gray.Print(formatTime(probe.Start))
+		gray.Print(" " + glyphs.divider + " ")
+		statusColor.Printf("exit %-3d", probe.ExitCode)
+		gray.Print(" " + glyphs.divider + " ")
+		gray.Println(probe.End.Sub(probe.Start).Round(time.Millisecond))
+
+		for _, line := range strings.Split(strings.TrimSpace(probe.Output), "\n") {
+			if line != "" {
+				gray.Printf("  %s %s\n", glyphs.detail, line)
+			}
+		}
+	}
+}
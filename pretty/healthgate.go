@@ -0,0 +1,247 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// healthGatePoll is how often the gate checks on its containers
+const healthGatePoll = 500 * time.Millisecond
+
+// gateState is where one container stands against the gate
+type gateState struct {
+	name   string
+	status string // what the stream and the failure list show
+	ready  bool   // running and healthy, running without a healthcheck, or completed
+	stuck  bool   // exited and won't be restarted, so waiting longer can't help
+	probe  string // the last probe's output when unhealthy
+}
+
+// HealthGate waits until every container of a compose project or label
+// selection is running and healthy, printing each status change, and exits
+// non-zero listing the containers that didn't make it. Meant for scripts
+// that need a stack up before running tests against it.
+func HealthGate(args []string) {
+	timeout := 120 * time.Second
+	var project string
+	var labels []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-t" || arg == "--timeout") && i+1 < len(args):
+			i++
+			timeout = parseGateTimeout(args[i])
+		case strings.HasPrefix(arg, "--timeout="):
+			timeout = parseGateTimeout(strings.TrimPrefix(arg, "--timeout="))
+		case arg == "--project" && i+1 < len(args):
+			i++
+			project = args[i]
+		case strings.HasPrefix(arg, "--project="):
+			project = strings.TrimPrefix(arg, "--project=")
+		case (arg == "-l" || arg == "--label") && i+1 < len(args):
+			i++
+			labels = append(labels, args[i])
+		case strings.HasPrefix(arg, "--label="):
+			labels = append(labels, strings.TrimPrefix(arg, "--label="))
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown argument %s\n", arg)
+			os.Exit(1)
+		}
+	}
+	if project == "" && len(labels) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: --project or --label required\n")
+		fmt.Println("Usage: dockit healthgate [--project NAME] [--label KEY=VALUE]... [--timeout 120s]")
+		os.Exit(1)
+	}
+
+	filterArgs := filters.NewArgs()
+	var selection []string
+	if project != "" {
+		filterArgs.Add("label", composeProjectLabel+"="+project)
+		selection = append(selection, "project "+project)
+	}
+	for _, label := range labels {
+		filterArgs.Add("label", label)
+		selection = append(selection, "label "+label)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	fmt.Println()
+	cyan.Printf("HEALTH GATE: %s\n", strings.Join(selection, ", "))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	// Containers are listed again on every poll, so ones compose is still
+	// creating join the gate as they appear
+	start := time.Now()
+	deadline := start.Add(timeout)
+	seen := map[string]string{} // container name to the last status printed
+	var states []gateState
+	for {
+		states, err = checkGate(ctx, cli, filterArgs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking containers: %v\n", err)
+			os.Exit(1)
+		}
+
+		ready, stuck := 0, 0
+		for _, state := range states {
+			if seen[state.name] != state.status {
+				seen[state.name] = state.status
+				printGateChange(time.Since(start), state)
+			}
+			switch {
+			case state.ready:
+				ready++
+			case state.stuck:
+				stuck++
+			}
+		}
+
+		if len(states) > 0 && ready == len(states) {
+			fmt.Println()
+			green.Printf("%s All %d containers ready ", glyphs.ok, len(states))
+			gray.Printf("(%s)\n", time.Since(start).Round(100*time.Millisecond))
+			return
+		}
+		if len(states) > 0 && ready+stuck == len(states) {
+			printGateFailures(states, "have exited")
+			os.Exit(1)
+		}
+		if time.Now().After(deadline) {
+			if len(states) == 0 {
+				fmt.Println()
+				red.Printf("%s No containers match after %s\n", glyphs.failed, timeout)
+				os.Exit(1)
+			}
+			printGateFailures(states, "not ready after "+timeout.String())
+			os.Exit(1)
+		}
+		time.Sleep(healthGatePoll)
+	}
+}
+
+func parseGateTimeout(value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid timeout %q\n", value)
+		os.Exit(1)
+	}
+	return d
+}
+
+// checkGate lists the matching containers and works out each one's state,
+// sorted by name
+func checkGate(ctx context.Context, cli *client.Client, filterArgs filters.Args) ([]gateState, error) {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true, Filters: filterArgs})
+	if err != nil {
+		return nil, err
+	}
+
+	var states []gateState
+	for _, c := range containers {
+		info, err := cli.ContainerInspect(ctx, c.ID)
+		if client.IsErrNotFound(err) {
+			// Removed since the list, as compose does when recreating
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, gateStateOf(info))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].name < states[j].name })
+	return states, nil
+}
+
+// gateStateOf judges one container. One that exited 0 without a restart
+// policy is a one-shot job, like a migration, and counts as ready.
+func gateStateOf(info container.InspectResponse) gateState {
+	state := gateState{name: strings.TrimPrefix(info.Name, "/")}
+	s := info.State
+	if s == nil {
+		state.status = "unknown"
+		return state
+	}
+
+	// Whether the daemon will start it again after this exit
+	restarts := false
+	if info.HostConfig != nil {
+		policy := info.HostConfig.RestartPolicy
+		restarts = policy.IsAlways() || policy.IsUnlessStopped() || (policy.IsOnFailure() && s.ExitCode != 0)
+	}
+	switch {
+	case s.Restarting:
+		state.status = fmt.Sprintf("restarting (exit code %d)", s.ExitCode)
+	case s.Running && s.Health == nil:
+		state.status = "running"
+		state.ready = true
+	case s.Running && s.Health.Status == "healthy":
+		state.status = "healthy"
+		state.ready = true
+	case s.Running && s.Health.Status == "unhealthy":
+		state.status = fmt.Sprintf("unhealthy (%d failures in a row)", s.Health.FailingStreak)
+		if probes := s.Health.Log; len(probes) > 0 {
+			state.probe = strings.TrimSpace(probes[len(probes)-1].Output)
+		}
+	case s.Running:
+		state.status = "running, " + s.Health.Status
+	case s.Status == "created":
+		state.status = "created, not started"
+	case s.ExitCode == 0 && !restarts:
+		state.status = "completed"
+		state.ready = true
+	default:
+		state.status = fmt.Sprintf("%s (exit code %d)", s.Status, s.ExitCode)
+		state.stuck = !restarts
+	}
+	return state
+}
+
+// printGateChange streams one status change with the time since the start
+func printGateChange(elapsed time.Duration, state gateState) {
+	statusColor, indicator := yellow, glyphs.clock
+	switch {
+	case state.ready:
+		statusColor, indicator = green, glyphs.ok
+	case state.stuck || strings.HasPrefix(state.status, "unhealthy"):
+		statusColor, indicator = red, glyphs.failed
+	}
+	gray.Printf("%7s  ", fmt.Sprintf("%.1fs", elapsed.Seconds()))
+	fmt.Printf("%-30s ", ellipsize(state.name, 30))
+	statusColor.Printf("%s %s\n", indicator, state.status)
+}
+
+// printGateFailures lists the containers that aren't ready, with why
+func printGateFailures(states []gateState, reason string) {
+	var failed []gateState
+	for _, state := range states {
+		if !state.ready {
+			failed = append(failed, state)
+		}
+	}
+
+	fmt.Println()
+	red.Printf("%s %d of %d containers %s:\n", glyphs.failed, len(failed), len(states), reason)
+	for _, state := range failed {
+		fmt.Printf("  %-30s ", ellipsize(state.name, 30))
+		red.Println(state.status)
+		if state.probe != "" {
+			gray.Printf("  %s last probe: %s\n", glyphs.detail, ellipsize(strings.ReplaceAll(state.probe, "\n", " "), 80))
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// removeUnusedImages is the images picker's D action: it lists every image
+// no container was created from, running or stopped, and after a
+// confirmation removes them all, reporting the space freed. Marked rows
+// don't matter; protected images are kept.
+func removeUnusedImages(ctx context.Context, cli *client.Client, _ []bulkItem) error {
+	// Listed afresh, so a container created since the picker opened keeps
+	// its image
+	images, err := cli.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return err
+	}
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+	used := imagesInUse(containers)
+
+	var unused []image.Summary
+	protected := 0
+	var estimate int64
+	for _, img := range images {
+		if used[img.ID] {
+			continue
+		}
+		if isProtected(img.ID, img.Labels) || anyTagProtected(img.RepoTags) {
+			protected++
+			continue
+		}
+		unused = append(unused, img)
+		// Layers shared with other images stay, so this errs high
+		estimate += img.Size
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i].Size > unused[j].Size })
+
+	fmt.Println()
+	cyan.Println("UNUSED IMAGES")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	if len(unused) == 0 {
+		gray.Print("No unused images")
+		if protected > 0 {
+			gray.Printf(" (%d protected)", protected)
+		}
+		fmt.Println()
+		return nil
+	}
+	for _, img := range unused {
+		fmt.Printf("  %s %-50s %10s\n", glyphs.detail, ellipsize(imageLabel(img), 50), formatSize(img.Size))
+	}
+	fmt.Println()
+	fmt.Printf("%d image(s), up to %s", len(unused), formatSize(estimate))
+	if protected > 0 {
+		yellow.Printf(" (%d protected, kept)", protected)
+	}
+	fmt.Println()
+
+	if !promptYesNo(bufio.NewReader(os.Stdin), fmt.Sprintf("Remove these %d image(s)?", len(unused))) {
+		gray.Println("Cancelled")
+		return nil
+	}
+
+	// The daemon's own layer total before and after gives the space really
+	// freed, shared layers and all
+	before, beforeErr := systemDiskUsage(ctx, cli)
+
+	fmt.Println()
+	removed, failed := 0, 0
+	for i, img := range unused {
+		gray.Printf("[%d/%d] ", i+1, len(unused))
+		fmt.Printf("%s ", imageLabel(img))
+		// Forced so an image with several tags goes in one call; nothing
+		// uses it, so only its tags are lost
+		if _, err := cli.ImageRemove(ctx, img.ID, image.RemoveOptions{Force: true, PruneChildren: true}); err != nil {
+			red.Print(glyphs.failed + " ")
+			red.Println(err)
+			failed++
+			continue
+		}
+		green.Print(glyphs.ok + " ")
+		gray.Println("removed")
+		removed++
+	}
+
+	fmt.Println()
+	green.Printf("%d removed", removed)
+	if failed > 0 {
+		red.Printf(", %d failed", failed)
+	}
+	after, afterErr := systemDiskUsage(ctx, cli)
+	if removed > 0 && beforeErr == nil && afterErr == nil && before.LayersSize >= after.LayersSize {
+		fmt.Print(", freed ")
+		green.Println(formatSize(before.LayersSize - after.LayersSize))
+	} else {
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("some images were not removed")
+	}
+	return nil
+}
+
+// imageLabel names an image by its tags, or its short ID when it has none
+func imageLabel(img image.Summary) string {
+	if len(img.RepoTags) == 0 || img.RepoTags[0] == "<none>:<none>" {
+		return formatID(img.ID, false)
+	}
+	return strings.Join(img.RepoTags, ", ")
+}
+
+// anyTagProtected reports whether a protect pattern covers one of tags
+func anyTagProtected(tags []string) bool {
+	for _, tag := range tags {
+		if isProtected(tag, nil) {
+			return true
+		}
+	}
+	return false
+}
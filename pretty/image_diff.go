@@ -0,0 +1,194 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/fatih/color"
+)
+
+// imageLayer is one filesystem layer of an image with the build step that
+// made it, when the history can be matched up
+type imageLayer struct {
+	digest    string
+	size      int64 // -1 when unknown
+	createdBy string
+}
+
+// DiffImages shows which layers two images share and which each adds, with
+// the size difference, e.g. to see why a new tag of an image grew
+func DiffImages(args []string) {
+	var refs []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			os.Exit(1)
+		}
+		refs = append(refs, arg)
+	}
+	if len(refs) != 2 {
+		fmt.Fprintf(os.Stderr, "Error: two images required\n")
+		fmt.Println("Usage: dockit image diff IMAGE IMAGE")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	if err := printLayerDiff(context.Background(), cli, refs[0], refs[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printLayerDiff compares two images' layers. Layers only share storage up to
+// the first one that differs, since each is stored on top of the ones below
+// it, so the common prefix is what is shared.
+func printLayerDiff(ctx context.Context, cli *client.Client, a, b string) error {
+	left, leftLayers, err := imageLayers(ctx, cli, a)
+	if err != nil {
+		return err
+	}
+	right, rightLayers, err := imageLayers(ctx, cli, b)
+	if err != nil {
+		return err
+	}
+
+	shared := 0
+	for shared < len(leftLayers) && shared < len(rightLayers) && leftLayers[shared].digest == rightLayers[shared].digest {
+		shared++
+	}
+
+	leftName, rightName := imageRefName(left, a), imageRefName(right, b)
+	if leftName == rightName {
+		// Both tags of one image; the names asked for tell them apart
+		leftName, rightName = a, b
+	}
+	fmt.Println()
+	cyan.Printf("LAYER DIFF: %s %s %s\n", leftName, glyphs.next, rightName)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	delta := right.Size - left.Size
+	gray.Printf("  %-12s ", "Size:")
+	fmt.Printf("%s %s %s ", formatSize(left.Size), glyphs.next, formatSize(right.Size))
+	switch {
+	case delta > 0:
+		red.Printf("(+%s)\n", formatSize(delta))
+	case delta < 0:
+		green.Printf("(-%s)\n", formatSize(-delta))
+	default:
+		gray.Println("(same)")
+	}
+	printDetail("Layers", fmt.Sprintf("%d shared, %d only in %s, %d only in %s", shared, len(leftLayers)-shared, leftName, len(rightLayers)-shared, rightName))
+
+	printLayerSection("SHARED", leftLayers[:shared], " ", gray)
+	printLayerSection("ONLY IN "+leftName, leftLayers[shared:], "-", red)
+	printLayerSection("ONLY IN "+rightName, rightLayers[shared:], "+", green)
+	return nil
+}
+
+// imageLayers inspects an image and lists its layers, oldest first
+func imageLayers(ctx context.Context, cli *client.Client, ref string) (image.InspectResponse, []imageLayer, error) {
+	info, err := cli.ImageInspect(ctx, ref)
+	if err != nil {
+		return info, nil, err
+	}
+	history, err := cli.ImageHistory(ctx, ref)
+	if err != nil {
+		return info, nil, err
+	}
+
+	layers := make([]imageLayer, 0, len(info.RootFS.Layers))
+	steps := layerSteps(history, len(info.RootFS.Layers))
+	for i, digest := range info.RootFS.Layers {
+		layer := imageLayer{digest: digest, size: -1}
+		if steps != nil {
+			layer.size = steps[i].Size
+			layer.createdBy = steps[i].CreatedBy
+		}
+		layers = append(layers, layer)
+	}
+	return info, layers, nil
+}
+
+// layerSteps matches the history's build steps to an image's n layers,
+// oldest first. The history API doesn't say which steps made a layer, only
+// their size, so the steps with a size are taken as the layers; nil when
+// that doesn't add up.
+func layerSteps(history []image.HistoryResponseItem, n int) []image.HistoryResponseItem {
+	// The history comes newest first
+	var all, sized []image.HistoryResponseItem
+	for i := len(history) - 1; i >= 0; i-- {
+		all = append(all, history[i])
+		if history[i].Size > 0 {
+			sized = append(sized, history[i])
+		}
+	}
+	switch n {
+	case len(sized):
+		return sized
+	case len(all):
+		return all
+	}
+	return nil
+}
+
+// imageRefName is how the diff names an image: its first tag, or the
+// reference it was asked for
+func imageRefName(info image.InspectResponse, ref string) string {
+	if len(info.RepoTags) > 0 {
+		return info.RepoTags[0]
+	}
+	return formatID(ref, false)
+}
+
+func printLayerSection(title string, layers []imageLayer, sign string, signColor *color.Color) {
+	var total int64
+	known := true
+	for _, layer := range layers {
+		if layer.size < 0 {
+			known = false
+		}
+		if layer.size > 0 {
+			total += layer.size
+		}
+	}
+
+	fmt.Println()
+	cyan.Printf("%s (%d", title, len(layers))
+	if known && len(layers) > 0 {
+		cyan.Printf(", %s", formatSize(total))
+	}
+	cyan.Println(")")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	if len(layers) == 0 {
+		gray.Println("None")
+		return
+	}
+	for _, layer := range layers {
+		size := "?"
+		if layer.size >= 0 {
+			size = formatSize(layer.size)
+		}
+		signColor.Printf("%s ", sign)
+		gray.Printf("%s ", formatID(layer.digest, false))
+		fmt.Printf("%10s  ", size)
+		gray.Println(ellipsize(cleanCreatedBy(layer.createdBy), 60))
+	}
+}
+
+// cleanCreatedBy trims the shell wrapper the classic builder puts around
+// each step, leaving the Dockerfile instruction
+func cleanCreatedBy(createdBy string) string {
+	createdBy = strings.TrimPrefix(createdBy, "/bin/sh -c #(nop) ")
+	createdBy = strings.TrimPrefix(createdBy, "/bin/sh -c ")
+	return strings.Join(strings.Fields(createdBy), " ")
+}
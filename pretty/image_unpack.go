@@ -0,0 +1,192 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// UnpackImage copies files out of an image without running it, through a
+// container that is created, copied from, and removed again
+func UnpackImage(args []string) {
+	quiet := false
+	output := "."
+	var imageRef string
+	var paths []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-q" || arg == "--quiet":
+			quiet = true
+		case arg == "--path" && i+1 < len(args):
+			i++
+			paths = append(paths, args[i])
+		case strings.HasPrefix(arg, "--path="):
+			paths = append(paths, strings.TrimPrefix(arg, "--path="))
+		case (arg == "-o" || arg == "--output") && i+1 < len(args):
+			i++
+			output = args[i]
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			os.Exit(1)
+		default:
+			imageRef = arg
+		}
+	}
+	if imageRef == "" || len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: an image and at least one --path are required\n")
+		fmt.Println("Usage: dockit image unpack [-q] [-o DEST] IMAGE --path PATH [--path PATH...]")
+		fmt.Println("PATH may be a glob, like /usr/local/bin/* or /etc/nginx/*.conf")
+		os.Exit(1)
+	}
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			fmt.Fprintf(os.Stderr, "Error: %s must be an absolute path in the image\n", p)
+			os.Exit(1)
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	showProgress := !quiet && isTerminal(os.Stdout)
+	if showProgress {
+		preparePalette()
+	}
+
+	id, err := createUnpackContainer(ctx, cli, imageRef, quiet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating a container from %s: %v\n", imageRef, err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, src := range paths {
+		label := imageRef + ":" + src + " " + glyphs.next + " " + output
+		copied, err := unpackPath(ctx, cli, id, src, output, progressPrinter(label, showProgress))
+		if showProgress {
+			fmt.Print("\r\033[K")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error copying %s: %v\n", label, err)
+			failed = true
+			continue
+		}
+		if !quiet {
+			green.Printf("%s ", glyphs.ok)
+			fmt.Printf("Copied %s ", label)
+			gray.Printf("(%s)\n", formatSize(copied))
+		}
+	}
+
+	// os.Exit skips deferred calls, so the container goes first
+	if err := cli.ContainerRemove(ctx, id, container.RemoveOptions{Force: true}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error removing container %s: %v\n", formatID(id, false), err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// createUnpackContainer creates a container to copy from, pulling the image
+// first if it isn't present. It is never started, so its command only has
+// to satisfy images with neither an entrypoint nor a command.
+func createUnpackContainer(ctx context.Context, cli *client.Client, imageRef string, quiet bool) (string, error) {
+	config := &container.Config{Image: imageRef, Entrypoint: []string{"/dockit-unpack"}}
+	created, err := cli.ContainerCreate(ctx, config, nil, nil, nil, "")
+	if client.IsErrNotFound(err) {
+		if !quiet {
+			gray.Printf("Pulling %s...\n", imageRef)
+		}
+		reader, pullErr := cli.ImagePull(ctx, imageRef, image.PullOptions{RegistryAuth: registryAuth(imageRef)})
+		if pullErr != nil {
+			return "", pullErr
+		}
+		_, pullErr = io.Copy(io.Discard, reader)
+		reader.Close()
+		if pullErr != nil {
+			return "", pullErr
+		}
+		created, err = cli.ContainerCreate(ctx, config, nil, nil, nil, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// unpackPath copies src out of the container into dst like dockit cp. A
+// glob copies the directory above its first wildcard into a staging
+// directory and moves each match from there into dst, which must be a
+// directory.
+func unpackPath(ctx context.Context, cli *client.Client, id, src, dst string, progress func(*countingReader, int64, <-chan struct{})) (int64, error) {
+	if !strings.ContainsAny(src, "*?[") {
+		return copyFromContainer(ctx, cli, id, src, dst, progress)
+	}
+
+	// The part of the path before the first segment with a wildcard
+	base := "/"
+	for _, segment := range strings.Split(strings.Trim(src, "/"), "/") {
+		if strings.ContainsAny(segment, "*?[") {
+			break
+		}
+		base = path.Join(base, segment)
+	}
+	if _, err := path.Match(src, src); err != nil {
+		return 0, fmt.Errorf("invalid pattern %s", src)
+	}
+	if base == "/" {
+		// Copying the whole filesystem to pick from it would be an export
+		return 0, fmt.Errorf("put a directory before the wildcard in %s", src)
+	}
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return 0, err
+	}
+	staging, err := os.MkdirTemp(dst, ".dockit-unpack-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(staging)
+
+	copied, err := copyFromContainer(ctx, cli, id, base, staging, progress)
+	if err != nil {
+		return copied, err
+	}
+
+	// The copy of base sits in staging under base's own name
+	root := filepath.Join(staging, path.Base(base))
+	pattern := filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(src, base)))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return copied, err
+	}
+	if len(matches) == 0 {
+		return copied, fmt.Errorf("nothing in the image matches %s", src)
+	}
+	for _, match := range matches {
+		target := filepath.Join(dst, filepath.Base(match))
+		if _, err := os.Lstat(target); err == nil {
+			return copied, fmt.Errorf("%s already exists", target)
+		}
+		if err := os.Rename(match, target); err != nil {
+			return copied, err
+		}
+	}
+	return copied, nil
+}
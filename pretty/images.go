@@ -4,16 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
-	"github.com/docker/docker/client"
 )
 
 // PrintImages displays Docker images in a pretty format
 func PrintImages(args []string) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := newClient()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
 		os.Exit(1)
@@ -22,33 +24,115 @@ func PrintImages(args []string) {
 
 	ctx := context.Background()
 
-	images, err := cli.ImageList(ctx, image.ListOptions{All: false})
+	format, args, err := parseOutputFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Parse flags; filters are handed to the Docker API as-is, like docker images
+	fullIDs := config.Defaults.FullIDs
+	showAll := false
+	showDigests := false
+	filterArgs := filters.NewArgs()
+	sortBy := ""
+	interactive := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--no-trunc":
+			fullIDs = true
+		case arg == "-i" || arg == "--interactive":
+			interactive = true
+		case arg == "-a" || arg == "--all":
+			showAll = true
+		case arg == "--digests":
+			showDigests = true
+		case (arg == "-f" || arg == "--filter") && i+1 < len(args):
+			i++
+			if err := addFilter(filterArgs, args[i]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case strings.HasPrefix(arg, "--filter="):
+			if err := addFilter(filterArgs, strings.TrimPrefix(arg, "--filter=")); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case arg == "--sort" && i+1 < len(args):
+			i++
+			sortBy = args[i]
+		case strings.HasPrefix(arg, "--sort="):
+			sortBy = strings.TrimPrefix(arg, "--sort=")
+		}
+	}
+
+	images, err := cli.ImageList(ctx, image.ListOptions{All: showAll, Filters: filterArgs})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error listing images: %v\n", err)
 		os.Exit(1)
 	}
 
+	if err := sortImages(images, sortBy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if interactive {
+		// Images no container was created from are marked unused
+		containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+			os.Exit(1)
+		}
+		reload := func() ([]bulkItem, error) {
+			images, err := cli.ImageList(ctx, image.ListOptions{All: showAll, Filters: filterArgs})
+			if err != nil {
+				return nil, err
+			}
+			// sortBy was checked when the list was first sorted
+			sortImages(images, sortBy)
+			containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+			if err != nil {
+				return nil, err
+			}
+			return imageBulkItems(images, imagesInUse(containers)), nil
+		}
+		runBulk(ctx, cli, "images", imageBulkItems(images, imagesInUse(containers)), imageActions, reload)
+		return
+	}
+
+	if format.enabled() {
+		records := []imageRecord{}
+		for _, img := range images {
+			records = append(records, newImageRecord(img))
+		}
+		printRecords(format, records)
+		return
+	}
+
 	if len(images) == 0 {
 		gray.Println("No images found")
 		return
 	}
 
+	renderImages(images, fullIDs, showDigests)
+}
+
+// renderImages prints the pretty image list
+func renderImages(images []image.Summary, fullIDs, showDigests bool) {
 	// Print header
 	fmt.Println()
 	cyan.Println("IMAGES")
-	cyan.Println(strings.Repeat("─", 90))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
 
 	var totalSize int64
 
 	// Print images
 	for _, img := range images {
-		// Image ID (short)
-		imageID := img.ID
-		if strings.HasPrefix(imageID, "sha256:") {
-			imageID = imageID[7:19] // Get first 12 chars after sha256:
-		}
-		idWidth := 12
-		idPadded := imageID + strings.Repeat(" ", idWidth-len(imageID))
+		// Image ID (short unless --no-trunc)
+		imageID := formatID(img.ID, fullIDs)
+		idPadded := imageID + strings.Repeat(" ", max(0, idWidth(fullIDs)-len(imageID)))
 
 		// Get repository and tag
 		repoTag := "<none>:<none>"
@@ -67,17 +151,31 @@ func PrintImages(args []string) {
 		sizePadded := size + strings.Repeat(" ", sizeWidth-len(size))
 
 		// Format created time
-		created := formatCreatedTime(img.Created)
+		created := formatTime(time.Unix(img.Created, 0))
 
 		// Print main line
 		gray.Print(idPadded)
-		gray.Print(" │ ")
+		gray.Print(" " + glyphs.divider + " ")
 		blue.Print(repoPadded)
-		gray.Print(" │ ")
+		gray.Print(" " + glyphs.divider + " ")
 		green.Print(sizePadded)
-		gray.Print("│ ")
+		gray.Print(glyphs.divider + " ")
 		gray.Println(created)
 
+		// Every other tag pointing at this image
+		if len(img.RepoTags) > 1 {
+			gray.Printf("  %s Tags: %s\n", glyphs.detail, strings.Join(img.RepoTags[1:], ", "))
+		}
+
+		if showDigests {
+			for _, digest := range img.RepoDigests {
+				gray.Printf("  %s Digest: %s\n", glyphs.detail, digest)
+			}
+			if len(img.RepoDigests) == 0 {
+				gray.Printf("  %s Digest: <none>\n", glyphs.detail)
+			}
+		}
+
 		fmt.Println()
 		totalSize += img.Size
 	}
@@ -90,6 +188,34 @@ func PrintImages(args []string) {
 	fmt.Println()
 }
 
+// sortImages orders images by size (largest first), created (newest first), or name
+func sortImages(images []image.Summary, by string) error {
+	name := func(img image.Summary) string {
+		if len(img.RepoTags) == 0 {
+			// Untagged images sort last
+			return "~" + img.ID
+		}
+		return img.RepoTags[0]
+	}
+
+	var less func(a, b image.Summary) bool
+	switch by {
+	case "":
+		return nil
+	case "size":
+		less = func(a, b image.Summary) bool { return a.Size > b.Size }
+	case "created":
+		less = func(a, b image.Summary) bool { return a.Created > b.Created }
+	case "name":
+		less = func(a, b image.Summary) bool { return name(a) < name(b) }
+	default:
+		return fmt.Errorf("unknown sort key %q (expected size, created, or name)", by)
+	}
+
+	sort.SliceStable(images, func(i, j int) bool { return less(images[i], images[j]) })
+	return nil
+}
+
 func formatSize(size int64) string {
 	const unit = 1024
 	if size < unit {
@@ -102,25 +228,3 @@ func formatSize(size int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
-
-func formatCreatedTime(timestamp int64) string {
-	created := time.Unix(timestamp, 0)
-	duration := time.Since(created)
-
-	switch {
-	case duration < time.Minute:
-		return fmt.Sprintf("%d seconds ago", int(duration.Seconds()))
-	case duration < time.Hour:
-		return fmt.Sprintf("%d minutes ago", int(duration.Minutes()))
-	case duration < 24*time.Hour:
-		return fmt.Sprintf("%d hours ago", int(duration.Hours()))
-	case duration < 7*24*time.Hour:
-		return fmt.Sprintf("%d days ago", int(duration.Hours()/24))
-	case duration < 30*24*time.Hour:
-		return fmt.Sprintf("%d weeks ago", int(duration.Hours()/(24*7)))
-	case duration < 365*24*time.Hour:
-		return fmt.Sprintf("%d months ago", int(duration.Hours()/(24*30)))
-	default:
-		return fmt.Sprintf("%d years ago", int(duration.Hours()/(24*365)))
-	}
-}
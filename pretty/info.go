@@ -0,0 +1,196 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/system"
+)
+
+// PrintSystemInfo shows the daemon's /info as sections: the engine and
+// host, storage, runtimes, registries, daemon settings, and its warnings
+func PrintSystemInfo(args []string) {
+	for _, arg := range args {
+		fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+		fmt.Println("Usage: dockit info [--format TEMPLATE]")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	info, err := cli.Info(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting system info: %v\n", err)
+		os.Exit(1)
+	}
+	// The API version is only in /version; info still stands without it
+	apiVersion := ""
+	if version, err := cli.ServerVersion(ctx); err == nil {
+		apiVersion = version.APIVersion
+	}
+
+	printInfoEngine(info, apiVersion)
+	printInfoStorage(info)
+	printInfoRuntimes(info)
+	printInfoRegistries(info)
+	printInfoDaemon(info)
+	printInfoWarnings(info.Warnings)
+}
+
+func printInfoHeader(title string) {
+	fmt.Println()
+	cyan.Println(title)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+}
+
+func printInfoEngine(info system.Info, apiVersion string) {
+	printInfoHeader("ENGINE")
+	version := info.ServerVersion
+	if apiVersion != "" {
+		version += " (API " + apiVersion + ")"
+	}
+	printDetail("Version", version)
+	printDetail("Host", info.Name)
+	printDetail("OS", fmt.Sprintf("%s, %s/%s", info.OperatingSystem, info.OSType, info.Architecture))
+	printDetail("Kernel", info.KernelVersion)
+	printDetail("Resources", fmt.Sprintf("%d CPUs, %s memory", info.NCPU, formatSize(info.MemTotal)))
+	printDetail("Containers", fmt.Sprintf("%d (%d running, %d paused, %d stopped)", info.Containers, info.ContainersRunning, info.ContainersPaused, info.ContainersStopped))
+	printDetail("Images", fmt.Sprint(info.Images))
+}
+
+func printInfoStorage(info system.Info) {
+	printInfoHeader("STORAGE")
+	printDetail("Driver", info.Driver)
+	for _, status := range info.DriverStatus {
+		gray.Printf("  %s %s: ", glyphs.detail, status[0])
+		fmt.Println(status[1])
+	}
+	printDetail("Root dir", info.DockerRootDir)
+	printDetail("Logging", info.LoggingDriver)
+}
+
+func printInfoRuntimes(info system.Info) {
+	printInfoHeader("RUNTIMES")
+	cgroups := orDash(info.CgroupDriver)
+	if info.CgroupVersion != "" {
+		cgroups += ", v" + info.CgroupVersion
+	}
+	printDetail("Cgroups", cgroups)
+
+	names := make([]string, 0, len(info.Runtimes))
+	for name := range info.Runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if name == info.DefaultRuntime {
+			names[i] += " (default)"
+		}
+	}
+	printDetail("Runtimes", orDash(strings.Join(names, ", ")))
+	if info.InitBinary != "" {
+		printDetail("Init", info.InitBinary)
+	}
+	if info.Isolation != "" {
+		printDetail("Isolation", string(info.Isolation))
+	}
+	if len(info.SecurityOptions) > 0 {
+		printDetail("Security", strings.Join(securityOptionNames(info.SecurityOptions), ", "))
+	}
+}
+
+// securityOptionNames shortens the daemon's name=seccomp,profile=builtin
+// entries to seccomp (builtin)
+func securityOptionNames(options []string) []string {
+	var names []string
+	for _, option := range options {
+		var name string
+		var extras []string
+		for _, field := range strings.Split(option, ",") {
+			key, value, _ := strings.Cut(field, "=")
+			if key == "name" {
+				name = value
+			} else if value != "" {
+				extras = append(extras, value)
+			}
+		}
+		if name == "" {
+			name = option
+		} else if len(extras) > 0 {
+			name += " (" + strings.Join(extras, ", ") + ")"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func printInfoRegistries(info system.Info) {
+	printInfoHeader("REGISTRIES")
+	printDetail("Index", info.IndexServerAddress)
+
+	var mirrors, insecure []string
+	if config := info.RegistryConfig; config != nil {
+		mirrors = config.Mirrors
+		for _, cidr := range config.InsecureRegistryCIDRs {
+			insecure = append(insecure, cidr.String())
+		}
+		for name, index := range config.IndexConfigs {
+			if index != nil && !index.Secure {
+				insecure = append(insecure, name)
+			}
+		}
+		sort.Strings(insecure)
+	}
+	printDetail("Mirrors", orDash(strings.Join(mirrors, ", ")))
+	if len(insecure) > 0 {
+		gray.Printf("  %-12s ", "Insecure:")
+		yellow.Println(strings.Join(insecure, ", "))
+	}
+	if info.HTTPProxy != "" || info.HTTPSProxy != "" {
+		printDetail("Proxy", fmt.Sprintf("http %s, https %s", orDash(info.HTTPProxy), orDash(info.HTTPSProxy)))
+		if info.NoProxy != "" {
+			printDetail("No proxy", info.NoProxy)
+		}
+	}
+}
+
+func printInfoDaemon(info system.Info) {
+	printInfoHeader("DAEMON")
+	gray.Printf("  %-12s ", "Live restore:")
+	if info.LiveRestoreEnabled {
+		green.Println("enabled")
+	} else {
+		fmt.Println("disabled (containers stop when the daemon does)")
+	}
+	swarm := string(info.Swarm.LocalNodeState)
+	if swarm == "" {
+		swarm = "inactive"
+	}
+	printDetail("Swarm", swarm)
+	printDetail("Debug", yesNo(info.Debug))
+	printDetail("Experimental", yesNo(info.ExperimentalBuild))
+	if len(info.Labels) > 0 {
+		printDetail("Labels", strings.Join(info.Labels, ", "))
+	}
+}
+
+func printInfoWarnings(warnings []string) {
+	printInfoHeader(fmt.Sprintf("WARNINGS (%d)", len(warnings)))
+	if len(warnings) == 0 {
+		green.Println(glyphs.ok + " None")
+		return
+	}
+	for _, warning := range warnings {
+		yellow.Print(glyphs.warn + " ")
+		fmt.Println(strings.TrimPrefix(warning, "WARNING: "))
+	}
+}
@@ -0,0 +1,409 @@
+package pretty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// inspectKinds are tried in this order when no --type is given, as docker
+// inspect does
+var inspectKinds = []string{"container", "image", "volume", "network"}
+
+// inspectTarget is whatever a name turned out to be, with the daemon's JSON
+type inspectTarget struct {
+	kind      string
+	raw       []byte
+	container container.InspectResponse
+	image     image.InspectResponse
+	volume    volume.Volume
+	network   network.Inspect
+}
+
+// Inspect shows a container, image, volume, or network by name or ID as a
+// sectioned summary, working out which it is; --raw prints the daemon's JSON
+func Inspect(args []string) {
+	raw, showSecrets := false, false
+	fullIDs := config.Defaults.FullIDs
+	kinds := inspectKinds
+	var names []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--raw":
+			raw = true
+		case arg == "--no-trunc":
+			fullIDs = true
+		case arg == "--show-secrets":
+			showSecrets = true
+		case arg == "--type" && i+1 < len(args):
+			i++
+			kinds = inspectTypeKinds(args[i])
+		case strings.HasPrefix(arg, "--type="):
+			kinds = inspectTypeKinds(strings.TrimPrefix(arg, "--type="))
+		case !strings.HasPrefix(arg, "-"):
+			names = append(names, arg)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			os.Exit(1)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: name or ID required\n")
+		fmt.Println("Usage: dockit inspect [--raw] [--show-secrets] [--type container|image|volume|network] NAME...")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	var targets []inspectTarget
+	for _, name := range names {
+		target, others, err := findInspectTarget(ctx, cli, name, kinds)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !raw && len(others) > 0 {
+			for i, kind := range others {
+				if kind == "image" {
+					others[i] = "an image"
+				} else {
+					others[i] = "a " + kind
+				}
+			}
+			yellow.Printf("%s %s is also %s; showing the %s (use --type to pick)\n", glyphs.warn, name, strings.Join(others, " and "), target.kind)
+		}
+		targets = append(targets, target)
+	}
+
+	// Like docker inspect, the raw form is one JSON array for every name
+	if raw {
+		var entries []string
+		for _, target := range targets {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, bytes.TrimSpace(target.raw), "    ", "    "); err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			entries = append(entries, "    "+buf.String())
+		}
+		fmt.Printf("[\n%s\n]\n", strings.Join(entries, ",\n"))
+		return
+	}
+
+	for _, target := range targets {
+		switch target.kind {
+		case "container":
+			printContainerInspect(target.container, fullIDs, showSecrets)
+		case "image":
+			// The hints still stand on the config alone without the history
+			history, _ := cli.ImageHistory(ctx, target.image.ID)
+			printImageInspect(target.image, history, fullIDs, showSecrets)
+		case "volume":
+			if err := printVolumeDetails(ctx, cli, target.volume, fullIDs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+				os.Exit(1)
+			}
+		case "network":
+			printNetworkInspect(target.network, fullIDs)
+		}
+	}
+}
+
+func inspectTypeKinds(kind string) []string {
+	for _, k := range inspectKinds {
+		if k == kind {
+			return []string{k}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Error: --type must be container, image, volume, or network, not %q\n", kind)
+	os.Exit(1)
+	return nil
+}
+
+// findInspectTarget looks name up as each kind in turn, returning the first
+// match and the other kinds it also names
+func findInspectTarget(ctx context.Context, cli *client.Client, name string, kinds []string) (inspectTarget, []string, error) {
+	var found *inspectTarget
+	var others []string
+	for _, kind := range kinds {
+		target := inspectTarget{kind: kind}
+		var err error
+		switch kind {
+		case "container":
+			target.container, target.raw, err = cli.ContainerInspectWithRaw(ctx, name, false)
+		case "image":
+			var buf bytes.Buffer
+			target.image, err = cli.ImageInspect(ctx, name, client.ImageInspectWithRawResponse(&buf))
+			target.raw = buf.Bytes()
+		case "volume":
+			target.volume, target.raw, err = cli.VolumeInspectWithRaw(ctx, name)
+		case "network":
+			target.network, target.raw, err = cli.NetworkInspectWithRaw(ctx, name, network.InspectOptions{})
+		}
+		if client.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return target, nil, fmt.Errorf("inspecting %s %s: %v", kind, name, err)
+		}
+		if found == nil {
+			found = &target
+		} else {
+			others = append(others, kind)
+		}
+	}
+	if found == nil {
+		what := kinds[len(kinds)-1]
+		if len(kinds) > 1 {
+			what = strings.Join(kinds[:len(kinds)-1], ", ") + ", or " + what
+		}
+		return inspectTarget{}, nil, fmt.Errorf("no %s named %s", what, name)
+	}
+	return *found, others, nil
+}
+
+// printInspectSection prints a titled list of key/value pairs sorted by key,
+// or nothing when there are none
+// printEnvSection shows the environment with secret-looking values masked
+// unless showSecrets is set
+func printEnvSection(env map[string]string, showSecrets bool) {
+	if !showSecrets {
+		env = maskSecrets(env)
+	}
+	printInspectSection("ENVIRONMENT", env)
+}
+
+func printInspectSection(title string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(values))
+	width := 0
+	for key := range values {
+		keys = append(keys, key)
+		width = max(width, len(key))
+	}
+	sort.Strings(keys)
+	width = min(width, 40)
+
+	fmt.Println()
+	cyan.Println(title)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	for _, key := range keys {
+		gray.Printf("  %-*s  ", width, ellipsize(key, 40))
+		fmt.Println(values[key])
+	}
+}
+
+func printContainerInspect(info container.InspectResponse, fullIDs, showSecrets bool) {
+	if info.Config == nil {
+		info.Config = &container.Config{}
+	}
+	if info.HostConfig == nil {
+		info.HostConfig = &container.HostConfig{}
+	}
+
+	fmt.Println()
+	cyan.Printf("CONTAINER: %s\n", strings.TrimPrefix(info.Name, "/"))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	printDetail("ID", formatID(info.ID, fullIDs))
+	image := info.Config.Image
+	if info.Image != "" {
+		image += " (" + formatID(info.Image, fullIDs) + ")"
+	}
+	printDetail("Image", image)
+	if created, err := time.Parse(time.RFC3339Nano, info.Created); err == nil {
+		printDetail("Created", formatTime(created))
+	}
+	if s := info.State; s != nil {
+		statusColor, indicator := stateStyle(s.Status)
+		gray.Printf("  %-12s ", "State:")
+		statusColor.Print(indicator + " " + s.Status)
+		if started, err := time.Parse(time.RFC3339Nano, s.StartedAt); err == nil && s.Running {
+			gray.Printf(" since %s", formatTime(started))
+		} else if finished, err := time.Parse(time.RFC3339Nano, s.FinishedAt); err == nil && !s.Running && !finished.IsZero() {
+			gray.Printf(" with code %d, %s", s.ExitCode, formatTime(finished))
+		}
+		fmt.Println()
+		if s.Health != nil {
+			printDetail("Health", fmt.Sprintf("%s (%d failures in a row)", s.Health.Status, s.Health.FailingStreak))
+		}
+	}
+	if info.RestartCount > 0 {
+		printDetail("Restarts", fmt.Sprint(info.RestartCount))
+	}
+	if len(info.Config.Entrypoint) > 0 {
+		printDetail("Entrypoint", strings.Join(info.Config.Entrypoint, " "))
+	}
+	if len(info.Config.Cmd) > 0 {
+		printDetail("Command", strings.Join(info.Config.Cmd, " "))
+	}
+	if info.Config.WorkingDir != "" {
+		printDetail("Workdir", info.Config.WorkingDir)
+	}
+	if info.Config.User != "" {
+		printDetail("User", info.Config.User)
+	}
+	if policy := info.HostConfig.RestartPolicy.Name; policy != "" {
+		printDetail("Restart", string(policy))
+	}
+	printDetail("Memory", formatLimit(info.HostConfig.Memory))
+	printDetail("CPUs", formatCPUs(info.HostConfig.NanoCPUs))
+	if project := info.Config.Labels[composeProjectLabel]; project != "" {
+		printDetail("Compose", project+"/"+info.Config.Labels[composeServiceLabel])
+	}
+
+	printInspectSection("PORTS", portMap(info.HostConfig.PortBindings))
+	printInspectSection("MOUNTS", mountMap(info.Mounts))
+
+	networks := map[string]string{}
+	if info.NetworkSettings != nil {
+		aliases := networkMap(info)
+		for name, endpoint := range info.NetworkSettings.Networks {
+			address := orDash(endpoint.IPAddress)
+			if aliases := aliases[name]; aliases != "connected" {
+				address += "  aliases: " + aliases
+			}
+			networks[name] = address
+		}
+	}
+	printInspectSection("NETWORKS", networks)
+	printEnvSection(envMap(info.Config.Env), showSecrets)
+	printInspectSection("LABELS", info.Config.Labels)
+}
+
+func printImageInspect(img image.InspectResponse, history []image.HistoryResponseItem, fullIDs, showSecrets bool) {
+	title := formatID(img.ID, false)
+	if len(img.RepoTags) > 0 {
+		title = img.RepoTags[0]
+	}
+	fmt.Println()
+	cyan.Printf("IMAGE: %s\n", title)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	printDetail("ID", formatID(img.ID, fullIDs))
+	if len(img.RepoTags) > 0 {
+		printDetail("Tags", strings.Join(img.RepoTags, ", "))
+	}
+	for _, digest := range img.RepoDigests {
+		printDetail("Digest", digest)
+	}
+	if created, err := time.Parse(time.RFC3339Nano, img.Created); err == nil {
+		printDetail("Created", formatTime(created))
+	}
+	printDetail("Size", formatSize(img.Size))
+	if img.Os != "" {
+		platform := img.Os + "/" + img.Architecture
+		if img.Variant != "" {
+			platform += "/" + img.Variant
+		}
+		printDetail("Platform", platform)
+	}
+	if len(img.RootFS.Layers) > 0 {
+		printDetail("Layers", fmt.Sprint(len(img.RootFS.Layers)))
+	}
+
+	var env []string
+	var labels map[string]string
+	if cfg := img.Config; cfg != nil {
+		if len(cfg.Entrypoint) > 0 {
+			printDetail("Entrypoint", strings.Join(cfg.Entrypoint, " "))
+		}
+		if len(cfg.Cmd) > 0 {
+			printDetail("Command", strings.Join(cfg.Cmd, " "))
+		}
+		if cfg.WorkingDir != "" {
+			printDetail("Workdir", cfg.WorkingDir)
+		}
+		if cfg.User != "" {
+			printDetail("User", cfg.User)
+		}
+		if len(cfg.ExposedPorts) > 0 {
+			ports := make([]string, 0, len(cfg.ExposedPorts))
+			for port := range cfg.ExposedPorts {
+				ports = append(ports, port)
+			}
+			sort.Strings(ports)
+			printDetail("Exposes", strings.Join(ports, ", "))
+		}
+		env, labels = cfg.Env, cfg.Labels
+	}
+
+	if hints := imageHints(img, history); len(hints) > 0 {
+		fmt.Println()
+		cyan.Println("HINTS")
+		cyan.Println(strings.Repeat(glyphs.rule, 90))
+		for _, hint := range hints {
+			yellow.Print(glyphs.warn + " ")
+			fmt.Println(hint)
+		}
+	}
+
+	printEnvSection(envMap(env), showSecrets)
+	printInspectSection("LABELS", labels)
+}
+
+func printNetworkInspect(n network.Inspect, fullIDs bool) {
+	fmt.Println()
+	cyan.Printf("NETWORK: %s\n", n.Name)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	printDetail("ID", formatID(n.ID, fullIDs))
+	printDetail("Driver", n.Driver)
+	printDetail("Scope", n.Scope)
+	if !n.Created.IsZero() {
+		printDetail("Created", formatTime(n.Created))
+	}
+	var flags []string
+	if n.Internal {
+		flags = append(flags, "internal")
+	}
+	if n.Attachable {
+		flags = append(flags, "attachable")
+	}
+	if n.EnableIPv6 {
+		flags = append(flags, "IPv6")
+	}
+	if len(flags) > 0 {
+		printDetail("Flags", strings.Join(flags, ", "))
+	}
+	for _, pool := range n.IPAM.Config {
+		subnet := pool.Subnet
+		if pool.Gateway != "" {
+			subnet += " via " + pool.Gateway
+		}
+		printDetail("Subnet", subnet)
+	}
+
+	containers := map[string]string{}
+	for id, endpoint := range n.Containers {
+		name := endpoint.Name
+		if name == "" {
+			name = formatID(id, fullIDs)
+		}
+		containers[name] = orDash(endpoint.IPv4Address)
+	}
+	printInspectSection("CONTAINERS", containers)
+	printInspectSection("OPTIONS", n.Options)
+	printInspectSection("LABELS", n.Labels)
+}
@@ -0,0 +1,151 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// LabelContainer adds and removes a container's labels. Docker can't change
+// labels in place, so the container is recreated with the merged set after
+// the user confirms.
+func LabelContainer(args []string) {
+	yes := false
+	var positional []string
+	for _, arg := range args {
+		switch arg {
+		case "-y", "--yes":
+			yes = true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: container and at least one label change required\n")
+		fmt.Println("Usage: dockit label [-y] CONTAINER KEY=VALUE... KEY-...")
+		os.Exit(1)
+	}
+
+	set := map[string]string{}
+	var remove []string
+	for _, change := range positional[1:] {
+		if key, value, ok := strings.Cut(change, "="); ok && key != "" {
+			set[key] = value
+		} else if key, ok := strings.CutSuffix(change, "-"); ok && key != "" {
+			remove = append(remove, key)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %q should be KEY=VALUE to set or KEY- to remove\n", change)
+			os.Exit(1)
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	info, err := cli.ContainerInspect(ctx, positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error inspecting container: %v\n", err)
+		os.Exit(1)
+	}
+	name := strings.TrimPrefix(info.Name, "/")
+
+	labels := maps.Clone(info.Config.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	changes := mergeLabels(labels, set, remove)
+	if len(changes) == 0 {
+		gray.Printf("%s already has these labels\n", name)
+		return
+	}
+
+	fmt.Println()
+	cyan.Printf("LABELS: %s\n", name)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	for _, change := range changes {
+		if strings.HasPrefix(change, "-") {
+			red.Println("  " + change)
+		} else {
+			green.Println("  " + change)
+		}
+	}
+	fmt.Println()
+
+	// Recreating keeps the volumes, anonymous ones included, but drops
+	// anything written outside them, so always ask
+	if !yes {
+		if !isTerminal(os.Stdin) {
+			fmt.Fprintf(os.Stderr, "Error: changing labels recreates the container; pass -y to confirm\n")
+			os.Exit(1)
+		}
+		yellow.Printf("%s Changing labels recreates %s; its volumes are kept, but files changed outside them are lost.\n", glyphs.warn, name)
+		if !promptYesNo(bufio.NewReader(os.Stdin), "Recreate "+name+"?") {
+			gray.Println("Cancelled")
+			return
+		}
+	}
+
+	if err := relabelContainer(ctx, cli, info.ID, labels); err != nil {
+		red.Print(glyphs.failed + " ")
+		fmt.Printf("%s: ", name)
+		red.Println(err)
+		os.Exit(1)
+	}
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Recreated %s with %d label change(s)\n", name, len(changes))
+}
+
+// mergeLabels applies the changes to labels and describes each one that
+// makes a difference, as "+key=value" or "-key"
+func mergeLabels(labels, set map[string]string, remove []string) []string {
+	var changes []string
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if current, ok := labels[key]; ok && current == set[key] {
+			continue
+		}
+		labels[key] = set[key]
+		changes = append(changes, fmt.Sprintf("+%s=%s", key, set[key]))
+	}
+	for _, key := range remove {
+		if _, ok := labels[key]; !ok {
+			continue
+		}
+		delete(labels, key)
+		changes = append(changes, "-"+key)
+	}
+	return changes
+}
+
+// relabelContainer recreates the container with the given labels on the
+// same image it runs now, even if its tag has since moved, keeping its
+// volumes through replaceContainer
+func relabelContainer(ctx context.Context, cli *client.Client, containerID string, labels map[string]string) error {
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	containerConfig := info.Config
+	containerConfig.Labels = labels
+	if current, err := cli.ImageInspect(ctx, containerConfig.Image); err != nil || current.ID != info.Image {
+		containerConfig.Image = info.Image
+	}
+	return replaceContainer(ctx, cli, info, containerConfig)
+}
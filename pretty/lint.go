@@ -0,0 +1,291 @@
+package pretty
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/image"
+)
+
+const (
+	// lintMaxLayers is how many layer-making steps an image can have before
+	// lint and inspect suggest combining some
+	lintMaxLayers = 20
+
+	// lintRunStreak is how many RUN instructions in a row lint flags
+	lintRunStreak = 3
+
+	// hugeLayerSize is the layer size inspect calls out as worth a look
+	hugeLayerSize = 500 << 20
+)
+
+// dockerfileInstruction is one instruction with its continuation lines
+// joined, and the line it starts on
+type dockerfileInstruction struct {
+	line    int
+	command string // upper case, e.g. RUN
+	args    string
+}
+
+// lintFinding is an issue lint reports against a line of the Dockerfile
+type lintFinding struct {
+	line    int
+	message string
+}
+
+// LintDockerfile checks a Dockerfile for common issues: missing USER, package
+// installs that leave their caches behind, unpinned base images, too many
+// layers, and secrets in ENV or ARG
+func LintDockerfile(args []string) {
+	path := "."
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			fmt.Println("Usage: dockit lint [PATH]")
+			os.Exit(1)
+		}
+		path = arg
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "Dockerfile")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading Dockerfile: %v\n", err)
+		os.Exit(1)
+	}
+	findings := lintInstructions(parseDockerfile(string(data)))
+
+	fmt.Println()
+	cyan.Printf("LINT: %s\n", path)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	if len(findings) == 0 {
+		green.Println(glyphs.ok + " No issues found")
+		return
+	}
+	for _, finding := range findings {
+		yellow.Print(glyphs.warn + " ")
+		gray.Printf("line %-4d ", finding.line)
+		fmt.Println(finding.message)
+	}
+	fmt.Println()
+	yellow.Printf("%d issue(s) found\n", len(findings))
+	os.Exit(1)
+}
+
+// parseDockerfile splits a Dockerfile into instructions, joining lines that
+// end in a backslash and dropping comments
+func parseDockerfile(text string) []dockerfileInstruction {
+	var instructions []dockerfileInstruction
+	var current []string
+	start := 0
+	flush := func() {
+		command, rest, _ := strings.Cut(strings.Join(current, " "), " ")
+		instructions = append(instructions, dockerfileInstruction{line: start, command: strings.ToUpper(command), args: strings.TrimSpace(rest)})
+		current = nil
+	}
+	for i, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if current == nil {
+			start = i + 1
+		}
+		current = append(current, strings.TrimSpace(strings.TrimSuffix(trimmed, "\\")))
+		if !strings.HasSuffix(trimmed, "\\") {
+			flush()
+		}
+	}
+	if current != nil {
+		flush()
+	}
+	return instructions
+}
+
+// lintInstructions returns the findings for a parsed Dockerfile, by line
+func lintInstructions(instructions []dockerfileInstruction) []lintFinding {
+	var findings []lintFinding
+	add := func(line int, format string, args ...any) {
+		findings = append(findings, lintFinding{line: line, message: fmt.Sprintf(format, args...)})
+	}
+
+	stages := map[string]bool{}
+	finalFrom := -1 // index of the last FROM
+	streak := 0
+	for i, inst := range instructions {
+		if inst.command == "RUN" {
+			streak++
+			if streak == lintRunStreak {
+				add(instructions[i-lintRunStreak+1].line, "%d or more RUN instructions in a row each make a layer; chain them with &&", lintRunStreak)
+			}
+		} else {
+			streak = 0
+		}
+
+		switch inst.command {
+		case "FROM":
+			finalFrom = i
+			base, stage := parseFrom(inst.args)
+			if stage != "" {
+				stages[strings.ToLower(stage)] = true
+			}
+			if message := unpinnedBase(base, stages); message != "" {
+				add(inst.line, "%s", message)
+			}
+		case "RUN":
+			for _, message := range packageCacheIssues(inst.args) {
+				add(inst.line, "%s", message)
+			}
+		case "ENV":
+			values := parseKeyValues(inst.args)
+			for _, name := range slices.Sorted(maps.Keys(values)) {
+				if values[name] != "" && isSecretEnv(name) {
+					add(inst.line, "ENV %s bakes a secret into the image; pass it at run time instead", name)
+				}
+			}
+		case "ARG":
+			for _, name := range slices.Sorted(maps.Keys(parseKeyValues(inst.args))) {
+				if isSecretEnv(name) {
+					add(inst.line, "ARG %s is kept in the image history; use a build secret (RUN --mount=type=secret)", name)
+				}
+			}
+		}
+	}
+
+	if finalFrom >= 0 {
+		final := instructions[finalFrom:]
+		user, layers := "", 0
+		for _, inst := range final {
+			switch inst.command {
+			case "USER":
+				user = inst.args
+			case "RUN", "COPY", "ADD":
+				layers++
+			}
+		}
+		fromLine := instructions[finalFrom].line
+		switch name, _, _ := strings.Cut(user, ":"); name {
+		case "":
+			add(fromLine, "The final stage never sets USER, so the container runs as root")
+		case "root", "0":
+			add(fromLine, "The final stage ends with USER %s; switch to an unprivileged user", user)
+		}
+		if layers > lintMaxLayers {
+			add(fromLine, "The final stage makes %d layers (more than %d); combine RUN, COPY, and ADD steps", layers, lintMaxLayers)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].line < findings[j].line })
+	return findings
+}
+
+// parseFrom returns a FROM instruction's image and its stage name, if any
+func parseFrom(args string) (base, stage string) {
+	var fields []string
+	for _, field := range strings.Fields(args) {
+		if !strings.HasPrefix(field, "--") {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return "", ""
+	}
+	if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+		stage = fields[2]
+	}
+	return fields[0], stage
+}
+
+// unpinnedBase describes a base image left on latest, or "" when it has
+// another tag or a digest, or isn't a registry image at all
+func unpinnedBase(base string, stages map[string]bool) string {
+	if base == "" || base == "scratch" || strings.Contains(base, "$") || stages[strings.ToLower(base)] {
+		return ""
+	}
+	if strings.Contains(base, "@") {
+		return ""
+	}
+	// A colon before the last slash is a registry port, not a tag
+	name := base[strings.LastIndex(base, "/")+1:]
+	_, tag, tagged := strings.Cut(name, ":")
+	switch {
+	case !tagged:
+		return fmt.Sprintf("FROM %s has no tag, so it builds on whatever latest is at the time", base)
+	case tag == "latest":
+		return fmt.Sprintf("FROM %s pins latest, which changes under you; use a version tag or digest", base)
+	}
+	return ""
+}
+
+// packageCacheIssues flags package installs in a RUN that leave the package
+// index or cache in the layer
+func packageCacheIssues(run string) []string {
+	var issues []string
+	if (strings.Contains(run, "apt-get install") || strings.Contains(run, "apt install")) && !strings.Contains(run, "/var/lib/apt/lists") {
+		issues = append(issues, "apt-get install without rm -rf /var/lib/apt/lists/* leaves the package index in the layer")
+	}
+	if strings.Contains(run, "apk add") && !strings.Contains(run, "--no-cache") {
+		issues = append(issues, "apk add without --no-cache leaves the package index in the layer")
+	}
+	for _, manager := range []string{"yum", "dnf"} {
+		if strings.Contains(run, manager+" install") && !strings.Contains(run, manager+" clean all") {
+			issues = append(issues, fmt.Sprintf("%s install without %s clean all leaves its cache in the layer", manager, manager))
+		}
+	}
+	return issues
+}
+
+// parseKeyValues reads ENV and ARG arguments, either NAME=value pairs or the
+// legacy ENV NAME value form
+func parseKeyValues(args string) map[string]string {
+	values := map[string]string{}
+	fields := strings.Fields(args)
+	if len(fields) > 0 && !strings.Contains(fields[0], "=") {
+		values[fields[0]] = strings.TrimSpace(strings.TrimPrefix(args, fields[0]))
+		return values
+	}
+	for _, field := range fields {
+		name, value, _ := strings.Cut(field, "=")
+		values[name] = strings.Trim(value, `"'`)
+	}
+	return values
+}
+
+// imageHints looks over an image's config and build history for the same
+// kinds of issues lint flags in a Dockerfile
+func imageHints(img image.InspectResponse, history []image.HistoryResponseItem) []string {
+	var hints []string
+	user := ""
+	var env []string
+	if img.Config != nil {
+		user, env = img.Config.User, img.Config.Env
+	}
+	switch name, _, _ := strings.Cut(user, ":"); name {
+	case "", "root", "0":
+		hints = append(hints, "Runs as root; set USER in the Dockerfile")
+	}
+
+	values := envMap(env)
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if values[name] != "" && isSecretEnv(name) {
+			hints = append(hints, fmt.Sprintf("ENV %s looks like a secret baked into the image", name))
+		}
+	}
+
+	if layers := len(img.RootFS.Layers); layers > lintMaxLayers {
+		hints = append(hints, fmt.Sprintf("%d layers (more than %d); combining steps makes pulls faster", layers, lintMaxLayers))
+	}
+	for _, step := range history {
+		if step.Size >= hugeLayerSize {
+			hints = append(hints, fmt.Sprintf("A %s layer from: %s", formatSize(step.Size), ellipsize(cleanCreatedBy(step.CreatedBy), 60)))
+		}
+	}
+	return hints
+}
@@ -0,0 +1,177 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// RunLogin checks credentials against a registry and saves them the way
+// docker login does, in a credential helper when one is configured
+func RunLogin(args []string) {
+	var server, username, password string
+	passwordStdin, list := false, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-u" || arg == "--username") && i+1 < len(args):
+			i++
+			username = args[i]
+		case strings.HasPrefix(arg, "--username="):
+			username = strings.TrimPrefix(arg, "--username=")
+		case (arg == "-p" || arg == "--password") && i+1 < len(args):
+			i++
+			password = args[i]
+		case arg == "--password-stdin":
+			passwordStdin = true
+		case arg == "-l" || arg == "--list":
+			list = true
+		case !strings.HasPrefix(arg, "-"):
+			server = arg
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			fmt.Println("Usage: dockit login [-u USER] [--password-stdin] [SERVER] | dockit login --list")
+			os.Exit(1)
+		}
+	}
+
+	if list {
+		printSavedLogins()
+		return
+	}
+	server = normalizeServer(server)
+
+	reader := bufio.NewReader(os.Stdin)
+	if passwordStdin {
+		if username == "" {
+			fmt.Fprintf(os.Stderr, "Error: --password-stdin requires --username\n")
+			os.Exit(1)
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+			os.Exit(1)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	if username == "" || password == "" {
+		if !isTerminal(os.Stdin) {
+			fmt.Fprintf(os.Stderr, "Error: no terminal to prompt on; pass --username and --password-stdin\n")
+			os.Exit(1)
+		}
+		if username == "" {
+			current, _, _ := lookupCredentials(server)
+			label := "Username: "
+			if current.Username != "" {
+				label = fmt.Sprintf("Username (%s): ", current.Username)
+			}
+			if username = prompt(reader, label); username == "" {
+				username = current.Username
+			}
+		}
+		if password == "" {
+			fmt.Print("Password: ")
+			secret, err := term.ReadPassword(os.Stdin.Fd())
+			fmt.Println()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+				os.Exit(1)
+			}
+			password = string(secret)
+		}
+	}
+	if username == "" || password == "" {
+		fmt.Fprintf(os.Stderr, "Error: username and password required\n")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	auth := registry.AuthConfig{Username: username, Password: password, ServerAddress: server}
+	result, err := cli.RegistryLogin(context.Background(), auth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error logging in to %s: %v\n", server, err)
+		os.Exit(1)
+	}
+	// Registries that hand out an identity token want it instead of the password
+	if result.IdentityToken != "" {
+		auth.Password = ""
+		auth.IdentityToken = result.IdentityToken
+	}
+
+	if err := storeCredentials(server, auth); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving credentials: %v\n", err)
+		os.Exit(1)
+	}
+
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Logged in to %s as %s ", server, username)
+	gray.Printf("(%s)\n", credentialStoreName(server))
+}
+
+// RunLogout removes the saved credentials for a registry
+func RunLogout(args []string) {
+	var server string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			server = arg
+		}
+	}
+	server = normalizeServer(server)
+
+	erased, err := eraseCredentials(server)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error removing credentials: %v\n", err)
+		os.Exit(1)
+	}
+	if !erased {
+		gray.Printf("Not logged in to %s\n", server)
+		return
+	}
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Logged out of %s\n", server)
+}
+
+// printSavedLogins lists the registries with saved credentials
+func printSavedLogins() {
+	servers, err := savedServers()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	cyan.Printf("%-50s %s\n", "REGISTRY", "STORED IN")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+	if len(servers) == 0 {
+		gray.Println("No saved logins")
+		return
+	}
+	for _, server := range sortedServers(servers) {
+		fmt.Printf("%-50s ", ellipsize(server, 50))
+		gray.Println(servers[server])
+	}
+}
+
+// credentialStoreName describes where server's credentials are kept
+func credentialStoreName(server string) string {
+	creds, err := readCredentialsFile()
+	if err == nil {
+		if helper := creds.helperFor(server); helper != "" {
+			return "docker-credential-" + helper
+		}
+	}
+	return dockerConfigFile()
+}
@@ -1,59 +1,254 @@
 package pretty
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 )
 
 // PrintLogs launches the TUI for viewing container logs
 func PrintLogs(args []string) {
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
-		fmt.Println("Usage: dockit logs [OPTIONS] CONTAINER")
-		fmt.Println()
-		fmt.Println("Options:")
-		fmt.Println("  -f, --follow    Follow log output (stream new logs)")
-		fmt.Println()
-		fmt.Println("Interactive TUI Controls:")
-		fmt.Println("  /               Start search")
-		fmt.Println("  n / N           Jump to next/previous match")
-		fmt.Println("  space           Pause/resume log streaming")
-		fmt.Println("  ↑↓ / j k        Scroll up/down")
-		fmt.Println("  PgUp / PgDn     Page up/down")
-		fmt.Println("  g / G           Jump to top/bottom")
-		fmt.Println("  q / Esc         Quit")
-		fmt.Println()
-		fmt.Println("Examples:")
-		fmt.Println("  dockit logs mycontainer          # View logs in interactive TUI")
-		fmt.Println("  dockit logs -f mycontainer       # Follow logs with live updates")
+		printLogsUsage()
 		os.Exit(1)
 	}
 
 	// Parse arguments
-	follow := false
-	var containerID string
+	var flags logFlags
+	var containerIDs []string
+	var project string
+	var labels []string
+	tabs := false
 
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
-		switch arg {
-		case "-f", "--follow":
-			follow = true
+		switch {
+		case arg == "-f" || arg == "--follow":
+			flags.follow = true
+		case (arg == "-n" || arg == "--tail") && i+1 < len(args):
+			i++
+			flags.tail = args[i]
+		case strings.HasPrefix(arg, "--tail="):
+			flags.tail = strings.TrimPrefix(arg, "--tail=")
+		case arg == "--since" && i+1 < len(args):
+			i++
+			flags.since = parseLogTimeFlag("--since", args[i])
+		case strings.HasPrefix(arg, "--since="):
+			flags.since = parseLogTimeFlag("--since", strings.TrimPrefix(arg, "--since="))
+		case arg == "--until" && i+1 < len(args):
+			i++
+			flags.until = parseLogTimeFlag("--until", args[i])
+		case strings.HasPrefix(arg, "--until="):
+			flags.until = parseLogTimeFlag("--until", strings.TrimPrefix(arg, "--until="))
+		case arg == "--tabs":
+			tabs = true
+		case arg == "--project" && i+1 < len(args):
+			i++
+			project = args[i]
+		case strings.HasPrefix(arg, "--project="):
+			project = strings.TrimPrefix(arg, "--project=")
+		case (arg == "-l" || arg == "--label") && i+1 < len(args):
+			i++
+			labels = append(labels, args[i])
+		case strings.HasPrefix(arg, "--label="):
+			labels = append(labels, strings.TrimPrefix(arg, "--label="))
 		default:
 			if !strings.HasPrefix(arg, "-") {
-				containerID = arg
+				containerIDs = append(containerIDs, arg)
 			}
 		}
 	}
 
-	if containerID == "" {
+	if project != "" || len(labels) > 0 {
+		selected, err := selectLogContainers(project, labels)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		containerIDs = append(containerIDs, selected...)
+	}
+
+	if len(containerIDs) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
 		os.Exit(1)
 	}
 
 	// Launch TUI
-	if err := LaunchLogsTUI(containerID, follow); err != nil {
+	if err := LaunchLogsTUI(containerIDs, flags, tabs); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// selectLogContainers resolves a compose project and label selectors to container IDs
+func selectLogContainers(project string, labels []string) ([]string, error) {
+	cli, err := newClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating Docker client: %v", err)
+	}
+	defer cli.Close()
+
+	filterArgs := filters.NewArgs()
+	if project != "" {
+		filterArgs.Add("label", composeProjectLabel+"="+project)
+	}
+	for _, label := range labels {
+		filterArgs.Add("label", label)
+	}
+
+	containers, err := cli.ContainerList(context.Background(), container.ListOptions{All: true, Filters: filterArgs})
+	if err != nil {
+		return nil, fmt.Errorf("error listing containers: %v", err)
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers match the given project or labels")
+	}
+
+	var ids []string
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+// logFlags are the log options given on the command line; ones left unset
+// come from the config
+type logFlags struct {
+	tail   string // "" when not given
+	follow bool
+	since  time.Time // zero when not given
+	until  time.Time
+}
+
+// parseLogTimeFlag reads a --since or --until value: a duration back from
+// now like 90m, an RFC 3339 timestamp, a date, or Unix seconds. Durations
+// are fixed when dockit starts, so every tab and reconnect sees one window.
+func parseLogTimeFlag(flag, value string) time.Time {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d)
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0)
+	}
+	fmt.Fprintf(os.Stderr, "Error: invalid %s value %q (expected a duration like 1h30m, a timestamp, or a date)\n", flag, value)
+	os.Exit(1)
+	return time.Time{}
+}
+
+// logTimeOption formats a --since or --until time for the logs API
+func logTimeOption(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// describeLogWindow summarizes the history a view loaded, like "last 100
+// since 14:05:09", or "" when it is everything
+func describeLogWindow(tails []string, flags logFlags) string {
+	var parts []string
+	var limits []string
+	for _, tail := range tails {
+		if tail != "" && tail != "all" && !slices.Contains(limits, tail) {
+			limits = append(limits, tail)
+		}
+	}
+	if len(limits) > 0 {
+		parts = append(parts, "last "+strings.Join(limits, "/"))
+	}
+	if !flags.since.IsZero() {
+		parts = append(parts, "since "+formatStamp(flags.since))
+	}
+	if !flags.until.IsZero() {
+		parts = append(parts, "until "+formatStamp(flags.until))
+	}
+	return strings.Join(parts, " ")
+}
+
+// logRuleFor returns the first log_defaults rule whose label selector the
+// labels match
+func logRuleFor(labels map[string]string) (LogRule, bool) {
+	for _, rule := range config.LogDefaults {
+		key, value, hasValue := strings.Cut(rule.Label, "=")
+		if actual, ok := labels[key]; ok && (!hasValue || actual == value) {
+			return rule, true
+		}
+	}
+	return LogRule{}, false
+}
+
+// logDefaultsFor returns the tail and follow defaults for a container with
+// labels: its label rule's where set, the config defaults otherwise
+func logDefaultsFor(labels map[string]string) (string, bool) {
+	tail, follow := config.Defaults.LogTail, config.Defaults.LogFollow
+	if rule, ok := logRuleFor(labels); ok {
+		if rule.Tail != "" {
+			tail = rule.Tail
+		}
+		if rule.Follow != nil {
+			follow = *rule.Follow
+		}
+	}
+	return tail, follow
+}
+
+func printLogsUsage() {
+	fmt.Println("Usage: dockit logs [OPTIONS] CONTAINER [CONTAINER...]")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -f, --follow       Follow log output (stream new logs)")
+	fmt.Println("  -n, --tail N       Lines of history to load (\"all\" for everything)")
+	fmt.Println("  --since TIME       Only logs after TIME: a duration back from now (90m), a timestamp, or a date")
+	fmt.Println("  --until TIME       Only logs before TIME, in the same forms")
+	fmt.Println("  --project NAME     Merge logs from every container in a compose project")
+	fmt.Println("  -l, --label K=V    Merge logs from containers with a label (repeatable)")
+	fmt.Println("  --tabs             Open each container in its own tab instead of merging")
+	fmt.Println()
+	fmt.Println("Interactive TUI Controls:")
+	fmt.Println("  /               Start search (up/down recall earlier searches)")
+	fmt.Println("  n / N           Jump to next/previous match")
+	fmt.Println("  space           Pause/resume log streaming")
+	fmt.Println("  f               Toggle follow mode (stream new logs)")
+	fmt.Println("  J               Toggle structured rendering of JSON log lines")
+	fmt.Println("  1-4             Filter JSON lines by level: error, warn, info, debug")
+	fmt.Println("  1-9             Show/hide a container (multiple containers, outside JSON mode)")
+	fmt.Println("  t               Cycle timestamps: hidden, time of day, time ago")
+	fmt.Println("  s               Show only stderr lines (stderr is always shown in red)")
+	fmt.Println("  w               Watch the tab's containers for a pattern; matches out of view ring the bell")
+	fmt.Println("  W               List watched lines that matched and jump to one")
+	fmt.Println("  E               Show/hide lifecycle events (restarts, exits, OOM kills, health changes) between lines")
+	fmt.Println("  #               Show/hide line numbers")
+	fmt.Println("  r               Copy a reference to the top line (NAME@TIMESTAMP:LINE)")
+	fmt.Println("  :               Go to a pasted reference or a line number")
+	fmt.Println("  !               Jump to the log line behind an exited container's probable cause")
+	fmt.Println("  v / e           Open the visible log lines in $PAGER / $EDITOR")
+	fmt.Println("  i               Open the container's inspect JSON in $PAGER")
+	fmt.Println("  c               Switch Docker context and open containers on that daemon")
+	fmt.Println("  ctrl+t          Show the recent Docker API calls with durations and status")
+	fmt.Println("  o / x           Open a tab for more containers / close the current tab")
+	fmt.Println("  tab / alt+1-9   Switch tabs (plain 1-9 works when the tab doesn't use them)")
+	fmt.Println("  ↑↓ / j k        Scroll up/down")
+	fmt.Println("  PgUp / PgDn     Page up/down")
+	fmt.Println("  g / G           Jump to top/bottom")
+	fmt.Println("  q / Esc         Quit")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  dockit logs mycontainer          # View logs in interactive TUI")
+	fmt.Println("  dockit logs -f mycontainer       # Follow logs with live updates")
+	fmt.Println("  dockit logs -f web db            # Merge logs from several containers")
+	fmt.Println("  dockit logs --project myapp      # Merge logs from a compose project")
+	fmt.Println("  dockit logs --tabs web db        # One tab per container")
+}
@@ -0,0 +1,102 @@
+package pretty
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLogLine caps how much of an unterminated line is held before it is
+// passed on as a line of its own
+const maxLogLine = 1024 * 1024
+
+// logReader is a container's log stream and whether the container has a
+// TTY, which decides how the stream is laid out
+type logReader struct {
+	io.ReadCloser
+	tty bool
+}
+
+// demuxLogs splits a container's log stream into lines, passing each to emit
+// with whether it came from stderr, until emit returns false or the stream
+// ends. Streams of containers without a TTY are framed: every chunk has an
+// 8 byte header naming its stream and length, and a chunk can hold part of
+// a line or several. TTY streams are plain text, all of it stdout.
+func demuxLogs(r io.Reader, tty bool, emit func(text string, stderr bool) bool) error {
+	if tty {
+		return splitLogLines(r, emit)
+	}
+
+	reader := bufio.NewReaderSize(r, 32*1024)
+	header := make([]byte, 8)
+	pending := [3][]byte{} // partial lines per stream: stdin, stdout, stderr
+
+	// Lines cut off by the end of the stream are still lines
+	flush := func() {
+		for kind, buf := range pending {
+			if len(buf) > 0 {
+				emit(strings.TrimSuffix(string(buf), "\r"), kind == 2)
+			}
+		}
+	}
+
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			flush()
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		kind := header[0]
+		if kind > 3 || header[1] != 0 || header[2] != 0 || header[3] != 0 {
+			// Not framed after all, so the container has a TTY
+			flush()
+			return splitLogLines(io.MultiReader(bytes.NewReader(header), reader), emit)
+		}
+
+		payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			flush()
+			return err
+		}
+		if kind == 3 {
+			return fmt.Errorf("error from daemon in log stream: %s", strings.TrimSpace(string(payload)))
+		}
+
+		buf := append(pending[kind], payload...)
+		for {
+			end := bytes.IndexByte(buf, '\n')
+			if end < 0 {
+				break
+			}
+			if !emit(strings.TrimSuffix(string(buf[:end]), "\r"), kind == 2) {
+				return nil
+			}
+			buf = buf[end+1:]
+		}
+		if len(buf) > maxLogLine {
+			if !emit(string(buf), kind == 2) {
+				return nil
+			}
+			buf = nil
+		}
+		pending[kind] = append([]byte(nil), buf...)
+	}
+}
+
+// splitLogLines passes each line of an unframed stream to emit as stdout
+func splitLogLines(r io.Reader, emit func(text string, stderr bool) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLine)
+	for scanner.Scan() {
+		if !emit(scanner.Text(), false) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,190 @@
+package pretty
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// logEventActions are the lifecycle events worth seeing between log lines
+var logEventActions = []events.Action{
+	events.ActionStart,
+	events.ActionRestart,
+	events.ActionDie,
+	events.ActionOOM,
+	events.ActionKill,
+	events.ActionStop,
+	events.ActionPause,
+	events.ActionUnPause,
+	events.ActionHealthStatus,
+}
+
+// logEventMsg delivers a lifecycle event for one of a tab's containers
+type logEventMsg struct {
+	event events.Message
+	tab   int
+	gen   int
+}
+
+// logEventsErrMsg reports that a tab's events stream broke
+type logEventsErrMsg struct {
+	err error
+	tab int
+	gen int
+}
+
+// toggleEvents shows or hides lifecycle events between the log lines,
+// subscribing to them the first time
+func (m *logsModel) toggleEvents() tea.Cmd {
+	m.showEvents = !m.showEvents
+	m.rebuildShown()
+	if !m.showEvents || m.eventStream != nil {
+		return nil
+	}
+	m.connectEvents()
+	return m.waitForLogEvent()
+}
+
+// connectEvents subscribes to the tab's container events, starting from the
+// oldest loaded line so earlier restarts show up too, or after the last
+// event seen when reconnecting
+func (m *logsModel) connectEvents() {
+	since := time.Now()
+	for _, line := range m.lines.all() {
+		if line.marker == "" {
+			since = line.timestamp
+			break
+		}
+	}
+	if !m.lastEvent.IsZero() {
+		since = m.lastEvent.Add(time.Nanosecond)
+	}
+
+	filterArgs := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, source := range m.sources {
+		filterArgs.Add("container", source.id)
+	}
+	for _, action := range logEventActions {
+		filterArgs.Add("event", string(action))
+	}
+
+	m.eventsGen++
+	m.eventStream, m.eventErrs = m.cli.Events(m.ctx, events.ListOptions{
+		Since:   fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond()),
+		Filters: filterArgs,
+	})
+}
+
+func (m *logsModel) waitForLogEvent() tea.Cmd {
+	stream, errs, tab, gen := m.eventStream, m.eventErrs, m.tab, m.eventsGen
+	return func() tea.Msg {
+		select {
+		case event := <-stream:
+			return logEventMsg{event: event, tab: tab, gen: gen}
+		case err := <-errs:
+			return logEventsErrMsg{err: err, tab: tab, gen: gen}
+		}
+	}
+}
+
+// insertEvent places an event's marker line among the log lines by its
+// timestamp, keeping indexes into lines pointing at the same lines
+func (m *logsModel) insertEvent(event events.Message) {
+	source := -1
+	for i, s := range m.sources {
+		if s.id == event.Actor.ID {
+			source = i
+			break
+		}
+	}
+	if source < 0 {
+		return
+	}
+
+	at := time.Unix(0, event.TimeNano)
+	m.lastEvent = at
+	line := logLine{
+		timestamp: at,
+		source:    source,
+		marker:    eventMarker(event),
+		action:    event.Action,
+	}
+
+	// Events usually arrive after the lines they follow, so search from the end
+	position := m.lines.len()
+	for position > 0 && m.lines.at(position-1).timestamp.After(at) {
+		position--
+	}
+	position = m.lines.insert(position, line)
+
+	if m.marked >= position {
+		m.marked++
+	}
+	for i := range m.causes {
+		if m.causes[i].line >= position {
+			m.causes[i].line++
+		}
+	}
+
+	// Keep the same lines on screen when the marker lands above them
+	before := m.scrollOffset < len(m.shown) && m.shown[m.scrollOffset] >= position
+	m.rebuildShown()
+	if before && !m.autoScroll && m.lineVisible(line) {
+		m.scrollOffset = min(m.scrollOffset+1, m.maxScroll())
+	}
+}
+
+// eventMarker describes an event for its marker line
+func eventMarker(event events.Message) string {
+	attrs := event.Actor.Attributes
+	at := formatStamp(time.Unix(0, event.TimeNano))
+
+	var text string
+	switch event.Action {
+	case events.ActionStart:
+		text = glyphs.running + " started"
+	case events.ActionRestart:
+		text = glyphs.running + " restarted"
+	case events.ActionDie:
+		text = glyphs.failed + " exited with code " + attrs["exitCode"]
+	case events.ActionOOM:
+		text = glyphs.failed + " killed: out of memory"
+	case events.ActionKill:
+		text = glyphs.failed + " sent signal " + attrs["signal"]
+	case events.ActionStop:
+		text = glyphs.stopped + " stopped"
+	case events.ActionPause:
+		text = glyphs.paused + " paused"
+	case events.ActionUnPause:
+		text = glyphs.running + " unpaused"
+	default:
+		// Health changes arrive as "health_status: unhealthy"
+		_, status, _ := strings.Cut(string(event.Action), ":")
+		status = strings.TrimSpace(status)
+		glyph := glyphs.ok
+		if status != "healthy" {
+			glyph = glyphs.warn
+		}
+		text = glyph + " health: " + status
+	}
+
+	rule := strings.Repeat(glyphs.rule, 3)
+	return fmt.Sprintf("%s %s at %s %s", rule, text, at, rule)
+}
+
+// markerStyle colors a marker line by how bad the event is
+func markerStyle(action events.Action) lipgloss.Style {
+	switch action {
+	case events.ActionDie, events.ActionOOM, events.ActionKill, events.ActionHealthStatusUnhealthy:
+		return errorStyle.Bold(true)
+	case events.ActionStart, events.ActionRestart, events.ActionUnPause, events.ActionHealthStatusHealthy:
+		return selectedStyle.Bold(true)
+	default:
+		return helpStyle.Bold(true)
+	}
+}
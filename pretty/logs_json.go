@@ -0,0 +1,145 @@
+package pretty
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Log levels, ordered by severity; levelNone marks lines without a level
+const (
+	levelNone = iota
+	levelError
+	levelWarn
+	levelInfo
+	levelDebug
+)
+
+var levelNames = map[int]string{
+	levelError: "ERROR",
+	levelWarn:  "WARN",
+	levelInfo:  "INFO",
+	levelDebug: "DEBUG",
+}
+
+var levelStyles = map[int]lipgloss.Style{
+	levelError: lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f5f")).Bold(true),
+	levelWarn:  lipgloss.NewStyle().Foreground(lipgloss.Color("#ffaf00")).Bold(true),
+	levelInfo:  lipgloss.NewStyle().Foreground(lipgloss.Color("#5fafff")),
+	levelDebug: lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")),
+}
+
+// Field names used by common JSON loggers (zap, logrus, pino, bunyan, slog, ...)
+var (
+	jsonLevelKeys = []string{"level", "lvl", "severity", "levelname", "log.level"}
+	jsonMsgKeys   = []string{"msg", "message", "text", "event"}
+	jsonTimeKeys  = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+)
+
+// jsonLog is a parsed JSON log line
+type jsonLog struct {
+	level  int
+	msg    string
+	time   string
+	fields map[string]any // everything else
+}
+
+// parseJSONLog parses a log line that is a JSON object
+func parseJSONLog(text string) (*jsonLog, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return nil, false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(text), &fields); err != nil {
+		return nil, false
+	}
+
+	entry := &jsonLog{fields: fields}
+	if value, ok := takeField(fields, jsonLevelKeys); ok {
+		entry.level = parseLevel(value)
+	}
+	if value, ok := takeField(fields, jsonMsgKeys); ok {
+		entry.msg = fmt.Sprint(value)
+	}
+	if value, ok := takeField(fields, jsonTimeKeys); ok {
+		entry.time = fmt.Sprint(value)
+		if t, ok := parseLogTime(value); ok {
+			entry.time = formatStamp(t)
+		}
+	}
+	return entry, true
+}
+
+// takeField removes and returns the first of keys present in fields
+func takeField(fields map[string]any, keys []string) (any, bool) {
+	for _, k := range keys {
+		if value, ok := fields[k]; ok {
+			delete(fields, k)
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// parseLevel normalizes level names and pino/bunyan numeric levels
+func parseLevel(value any) int {
+	if n, ok := value.(float64); ok {
+		switch {
+		case n >= 50:
+			return levelError
+		case n >= 40:
+			return levelWarn
+		case n >= 30:
+			return levelInfo
+		default:
+			return levelDebug
+		}
+	}
+
+	switch strings.ToLower(fmt.Sprint(value)) {
+	case "error", "err", "fatal", "panic", "critical", "crit", "alert", "emerg", "dpanic":
+		return levelError
+	case "warn", "warning":
+		return levelWarn
+	case "info", "information", "notice":
+		return levelInfo
+	case "debug", "trace", "verbose":
+		return levelDebug
+	}
+	return levelNone
+}
+
+// render formats the entry as "time LEVEL msg key=value ..."
+func (e *jsonLog) render() string {
+	var parts []string
+	if e.time != "" {
+		parts = append(parts, helpStyle.Render(e.time))
+	}
+	if e.level != levelNone {
+		parts = append(parts, levelStyles[e.level].Render(fmt.Sprintf("%-5s", levelNames[e.level])))
+	}
+	if e.msg != "" {
+		parts = append(parts, e.msg)
+	}
+
+	keys := make([]string, 0, len(e.fields))
+	for k := range e.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := e.fields[k]
+		if _, ok := value.(string); !ok {
+			data, _ := json.Marshal(value)
+			value = string(data)
+		}
+		parts = append(parts, helpStyle.Render(k+"=")+fmt.Sprint(value))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,99 @@
+package pretty
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultLevelPatterns find a plain log line's level when the config sets
+// no log_levels; they are tried from error down to debug
+var defaultLevelPatterns = map[string]string{
+	"error": `(?i)\b(error|err|fatal|panic|critical|crit)\b`,
+	"warn":  `(?i)\b(warn|warning)\b`,
+	"info":  `(?i)\b(info|notice)\b`,
+	"debug": `(?i)\b(debug|trace)\b`,
+}
+
+// levelPatternOrder pairs the log_levels keys with their levels, most
+// severe first
+var levelPatternOrder = []struct {
+	name  string
+	level int
+}{
+	{"error", levelError},
+	{"warn", levelWarn},
+	{"info", levelInfo},
+	{"debug", levelDebug},
+}
+
+// lineLevelStyles color whole lines by level; info lines keep the default
+var lineLevelStyles = map[int]lipgloss.Style{
+	levelError: lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f5f")),
+	levelWarn:  lipgloss.NewStyle().Foreground(lipgloss.Color("#ffaf00")),
+	levelDebug: lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")),
+}
+
+// levelPattern is a compiled log_levels entry
+type levelPattern struct {
+	level   int
+	pattern *regexp.Regexp
+}
+
+// levelPatterns is set from the config by compileLevelPatterns
+var levelPatterns []levelPattern
+
+// compileLevelPatterns compiles log_levels over the defaults, so setting
+// one level leaves the others as they were
+func compileLevelPatterns() error {
+	patterns := []levelPattern{}
+	for _, entry := range levelPatternOrder {
+		source := defaultLevelPatterns[entry.name]
+		if custom, ok := config.LogLevels[entry.name]; ok {
+			source = custom
+		}
+		if source == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(source)
+		if err != nil {
+			return fmt.Errorf("%s: %v", entry.name, err)
+		}
+		patterns = append(patterns, levelPattern{level: entry.level, pattern: pattern})
+	}
+	for name := range config.LogLevels {
+		if _, ok := defaultLevelPatterns[name]; !ok {
+			return fmt.Errorf("unknown level %q (use error, warn, info, or debug)", name)
+		}
+	}
+	levelPatterns = patterns
+	return nil
+}
+
+// detectLevel returns a log line's level: a JSON line's level field, or
+// the first log_levels pattern a plain line matches
+func detectLevel(line string) int {
+	if entry, ok := parseJSONLog(line); ok {
+		return entry.level
+	}
+	if levelPatterns == nil {
+		// The config wasn't loaded; the defaults always compile
+		compileLevelPatterns()
+	}
+	for _, p := range levelPatterns {
+		if p.pattern.MatchString(line) {
+			return p.level
+		}
+	}
+	return levelNone
+}
+
+// nextLevelFilter steps a level filter from everything to errors only,
+// then widens it a level at a time back to everything
+func nextLevelFilter(level int) int {
+	if level == levelDebug {
+		return levelNone
+	}
+	return level + 1
+}
@@ -0,0 +1,189 @@
+package pretty
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logRef points at one log line as container-name@timestamp:line. The
+// timestamp is the daemon's, so it finds the same line for anyone reading
+// the container's logs; the line number is the line's position among that
+// container's lines in the viewer and settles lines sharing a timestamp.
+type logRef struct {
+	container string
+	at        time.Time // zero for a bare line number
+	line      int
+}
+
+func (r logRef) String() string {
+	return fmt.Sprintf("%s@%s:%d", r.container, r.at.UTC().Format(time.RFC3339Nano), r.line)
+}
+
+// parseLogRef reads a reference, or a bare line number, as typed into the
+// go to prompt
+func parseLogRef(text string) (logRef, error) {
+	text = strings.TrimSpace(text)
+	if n, err := strconv.Atoi(text); err == nil && n > 0 {
+		return logRef{line: n}, nil
+	}
+
+	name, rest, ok := strings.Cut(text, "@")
+	if !ok || name == "" {
+		return logRef{}, fmt.Errorf("expected NAME@TIMESTAMP:LINE or a line number")
+	}
+	// The timestamp has colons of its own, so the line number follows the last
+	colon := strings.LastIndex(rest, ":")
+	if colon < 0 {
+		return logRef{}, fmt.Errorf("expected NAME@TIMESTAMP:LINE or a line number")
+	}
+	at, err := time.Parse(time.RFC3339Nano, rest[:colon])
+	if err != nil {
+		return logRef{}, fmt.Errorf("invalid timestamp %q", rest[:colon])
+	}
+	line, err := strconv.Atoi(rest[colon+1:])
+	if err != nil || line < 1 {
+		return logRef{}, fmt.Errorf("invalid line number %q", rest[colon+1:])
+	}
+	return logRef{container: name, at: at, line: line}, nil
+}
+
+// splitLogTimestamp takes the timestamp Docker puts before each line's text
+func splitLogTimestamp(raw string) (string, time.Time, bool) {
+	stamp, rest, ok := strings.Cut(raw, " ")
+	if !ok {
+		stamp, rest = raw, ""
+	}
+	at, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return raw, time.Time{}, false
+	}
+	return rest, at, true
+}
+
+// currentRef references the top visible line
+func (m *logsModel) currentRef() (logRef, bool) {
+	line, ok := m.topLine()
+	if !ok {
+		return logRef{}, false
+	}
+	return logRef{container: m.sources[line.source].name, at: line.timestamp, line: line.number}, true
+}
+
+// topLine is the first log line from the top of the view; event markers
+// have no number, so they are passed over
+func (m *logsModel) topLine() (logLine, bool) {
+	for _, index := range m.shown[min(m.scrollOffset, len(m.shown)):] {
+		line := m.lines.at(index)
+		if line.marker == "" {
+			return line, true
+		}
+	}
+	return logLine{}, false
+}
+
+// resolveRef finds the line a reference points at. When the exact line
+// isn't loaded it lands on the first later line from the same container and
+// says so in the returned note.
+func (m *logsModel) resolveRef(ref logRef) (int, string, error) {
+	source := 0
+	if ref.container != "" {
+		source = -1
+		for i, s := range m.sources {
+			if s.name == ref.container {
+				source = i
+				break
+			}
+		}
+		if source < 0 {
+			return 0, "", fmt.Errorf("%s is not in this view (o: open it in a tab)", ref.container)
+		}
+	} else if len(m.sources) > 1 {
+		return 0, "", fmt.Errorf("several containers are merged; use NAME@TIMESTAMP:LINE")
+	}
+
+	if ref.at.IsZero() {
+		for i, line := range m.lines.all() {
+			if line.source == source && line.number == ref.line {
+				return i, "", nil
+			}
+		}
+		return 0, "", fmt.Errorf("no line %d", ref.line)
+	}
+
+	exact, later := -1, -1
+	for i, line := range m.lines.all() {
+		if line.source != source || line.marker != "" {
+			continue
+		}
+		switch {
+		case line.timestamp.Equal(ref.at):
+			if exact < 0 || line.number == ref.line {
+				exact = i
+			}
+		case line.timestamp.After(ref.at) && later < 0:
+			later = i
+		}
+	}
+	switch {
+	case exact >= 0:
+		return exact, "", nil
+	case later < 0:
+		return 0, "", fmt.Errorf("%s has no lines loaded from %s on", ref.container, formatStamp(ref.at))
+	case m.lines.at(later).number == 1:
+		return later, "line is older than the loaded logs; showing the oldest", nil
+	default:
+		return later, "exact line not loaded; showing the next one", nil
+	}
+}
+
+// goToRef scrolls to the referenced line and marks it
+func (m *logsModel) goToRef(text string) {
+	ref, err := parseLogRef(text)
+	if err == nil {
+		var index int
+		var note string
+		index, note, err = m.resolveRef(ref)
+		if err == nil {
+			if !m.lineVisible(m.lines.at(index)) {
+				m.sources[m.lines.at(index).source].hidden = false
+				m.minLevel = levelNone
+				m.rebuildShown()
+			}
+			for position, shown := range m.shown {
+				if shown == index {
+					m.scrollOffset = min(position, m.maxScroll())
+					break
+				}
+			}
+			// The gutter shows the mark and the number the reference named
+			m.marked = index
+			m.numbers = true
+			m.autoScroll = false
+			m.notice = note
+			return
+		}
+	}
+	m.notice = err.Error()
+}
+
+// numberLine puts the line's number in a gutter before its text, with a
+// cursor on the line go to landed on
+func (m *logsModel) numberLine(line logLine, text string) string {
+	if !m.numbers || (text == "" && m.searchPattern != nil) {
+		return text
+	}
+	width := 1
+	for _, count := range m.lineCounts {
+		width = max(width, len(strconv.Itoa(count)))
+	}
+	if line.marker != "" {
+		return strings.Repeat(" ", width+3) + text
+	}
+	gutter := helpStyle.Render(fmt.Sprintf("  %*d ", width, line.number))
+	if m.marked >= 0 && m.lines.at(m.marked).source == line.source && m.lines.at(m.marked).number == line.number {
+		gutter = highlightStyle.Render(fmt.Sprintf("%s %*d", glyphs.cursor, width, line.number)) + " "
+	}
+	return gutter + text
+}
@@ -0,0 +1,170 @@
+package pretty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+const (
+	logMemoryLines = 50000 // most recent lines a tab keeps in memory
+	logSpillBatch  = 10000 // lines moved to the spill file at a time
+	logSpillPage   = 500   // spilled lines read back at a time
+)
+
+// spilledLine is a logLine as stored in the spill file, one JSON object per
+// line; the parsed JSON entry is worked out again when it is read back
+type spilledLine struct {
+	Raw       string        `json:"r,omitempty"`
+	Timestamp time.Time     `json:"t"`
+	Source    int           `json:"s,omitempty"`
+	Number    int           `json:"n,omitempty"`
+	Stderr    bool          `json:"e,omitempty"`
+	Marker    string        `json:"m,omitempty"`
+	Action    events.Action `json:"a,omitempty"`
+}
+
+// logBuffer holds a tab's lines. Past logMemoryLines the oldest are moved
+// to a temporary file, with the offset of each kept in memory, and read
+// back a page at a time when something scrolls or searches through them,
+// so a long follow session keeps its full history in bounded memory.
+type logBuffer struct {
+	recent  []logLine // the lines from spilled on
+	spilled int       // how many lines are in the file
+	file    *os.File
+	offsets []int64 // where each spilled line starts, then the end of the last
+	page    []logLine
+	pageAt  int  // index of page[0]
+	failed  bool // the file couldn't be written, so everything stays in memory
+}
+
+func newLogBuffer() *logBuffer {
+	return &logBuffer{offsets: []int64{0}}
+}
+
+func (b *logBuffer) len() int {
+	return b.spilled + len(b.recent)
+}
+
+// at returns line i, reading it back from the spill file if need be
+func (b *logBuffer) at(i int) logLine {
+	if i >= b.spilled {
+		return b.recent[i-b.spilled]
+	}
+	if i < b.pageAt || i >= b.pageAt+len(b.page) {
+		b.readPage(i - i%logSpillPage)
+	}
+	return b.page[i-b.pageAt]
+}
+
+// all yields every line in order with its index
+func (b *logBuffer) all() iter.Seq2[int, logLine] {
+	return func(yield func(int, logLine) bool) {
+		for i := 0; i < b.len(); i++ {
+			if !yield(i, b.at(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (b *logBuffer) append(line logLine) {
+	b.recent = append(b.recent, line)
+	if len(b.recent) > logMemoryLines && !b.failed {
+		b.spill()
+	}
+}
+
+// insert puts line at position, shifting later lines along, and returns
+// where it went: spilled lines are fixed, so a line that belongs among them
+// goes just after them instead
+func (b *logBuffer) insert(position int, line logLine) int {
+	position = max(position, b.spilled)
+	b.recent = slices.Insert(b.recent, position-b.spilled, line)
+	return position
+}
+
+// spill moves the oldest batch of in-memory lines to the end of the file
+func (b *logBuffer) spill() {
+	if b.file == nil {
+		file, err := os.CreateTemp("", "dockit-logs-*.jsonl")
+		if err != nil {
+			b.failed = true
+			return
+		}
+		b.file = file
+	}
+
+	var buf bytes.Buffer
+	offsets := make([]int64, 0, logSpillBatch)
+	end := b.offsets[len(b.offsets)-1]
+	for _, line := range b.recent[:logSpillBatch] {
+		data, _ := json.Marshal(spilledLine{
+			Raw:       line.raw,
+			Timestamp: line.timestamp,
+			Source:    line.source,
+			Number:    line.number,
+			Stderr:    line.stderr,
+			Marker:    line.marker,
+			Action:    line.action,
+		})
+		buf.Write(data)
+		buf.WriteByte('\n')
+		offsets = append(offsets, end+int64(buf.Len()))
+	}
+	if _, err := b.file.WriteAt(buf.Bytes(), end); err != nil {
+		b.failed = true
+		return
+	}
+
+	b.offsets = append(b.offsets, offsets...)
+	b.spilled += logSpillBatch
+	// Copied so the spilled lines' memory can be freed
+	b.recent = slices.Clone(b.recent[logSpillBatch:])
+}
+
+// readPage loads the spilled lines from start into the page cache. A line
+// that can't be read back stands in as a line saying why.
+func (b *logBuffer) readPage(start int) {
+	end := min(start+logSpillPage, b.spilled)
+	b.page = make([]logLine, 0, end-start)
+	b.pageAt = start
+
+	data := make([]byte, b.offsets[end]-b.offsets[start])
+	_, err := b.file.ReadAt(data, b.offsets[start])
+	for i := start; i < end; i++ {
+		line := logLine{raw: fmt.Sprintf("[dockit: could not read this line back from %s: %v]", b.file.Name(), err)}
+		if err == nil {
+			var stored spilledLine
+			text := data[b.offsets[i]-b.offsets[start] : b.offsets[i+1]-b.offsets[start]]
+			if decodeErr := json.Unmarshal(text, &stored); decodeErr == nil {
+				line = logLine{
+					raw:       stored.Raw,
+					timestamp: stored.Timestamp,
+					source:    stored.Source,
+					number:    stored.Number,
+					stderr:    stored.Stderr,
+					marker:    stored.Marker,
+					action:    stored.Action,
+				}
+				line.entry, _ = parseJSONLog(lineText(line))
+			}
+		}
+		b.page = append(b.page, line)
+	}
+}
+
+// close removes the spill file
+func (b *logBuffer) close() {
+	if b.file != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+		b.file = nil
+	}
+}
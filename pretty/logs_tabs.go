@@ -0,0 +1,625 @@
+package pretty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/client"
+)
+
+var (
+	tabStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#626262")).
+			Padding(0, 1)
+
+	activeTabStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("#00d7ff")).
+			Foreground(lipgloss.Color("#000000")).
+			Bold(true).
+			Padding(0, 1)
+)
+
+// logsSession holds one or more log viewer tabs and routes input to the
+// active one; streams keep running in the background tabs
+type logsSession struct {
+	tabs      []logsModel
+	active    int
+	nextTab   int
+	keys      logsKeyMap
+	cli       *client.Client
+	ctx       context.Context
+	flags     logFlags
+	width     int
+	height    int
+	opening   bool
+	openInput textinput.Model
+	err       error
+
+	// Containers to open once connected; the client is only created when
+	// the TUI starts, so the screen appears before the daemon answers
+	startup  [][]string
+	starting int
+	startErr error
+
+	// API calls debug panel
+	tracing bool
+
+	// Context picker
+	dockerContext string
+	picking       bool
+	contexts      []dockerContext
+	contextCursor int
+
+	// Watched patterns and the W list of lines that matched out of view
+	watches       []logWatch
+	watching      bool
+	watchInput    textinput.Model
+	notifications []logNotification
+	alerting      bool
+	alertCursor   int
+}
+
+// tabOpenedMsg delivers a tab opened in the background
+type tabOpenedMsg struct {
+	tab logsModel
+	err error
+}
+
+// sessionConnectedMsg delivers the client created when the TUI starts
+type sessionConnectedMsg struct {
+	cli *client.Client
+	err error
+}
+
+// startupTabMsg delivers one of the tabs requested on the command line
+type startupTabMsg struct {
+	tab logsModel
+	err error
+}
+
+// contextSwitchedMsg delivers a client connected to a newly picked context
+type contextSwitchedMsg struct {
+	name string
+	cli  *client.Client
+	err  error
+}
+
+// inspectReadyMsg delivers inspect JSON to show in the pager
+type inspectReadyMsg struct {
+	data []byte
+	err  error
+}
+
+func newLogsSession(ctx context.Context, cli *client.Client, flags logFlags) *logsSession {
+	ti := textinput.New()
+	ti.Placeholder = "container names, separated by spaces"
+	ti.CharLimit = 200
+	ti.Width = 50
+
+	watchInput := textinput.New()
+	watchInput.Placeholder = "regular expression"
+	watchInput.CharLimit = 200
+	watchInput.Width = 50
+
+	watches, err := configWatches()
+
+	return &logsSession{
+		keys:      defaultLogsKeyMap(),
+		cli:       cli,
+		ctx:       ctx,
+		flags:     flags,
+		openInput: ti,
+		err:       err,
+
+		dockerContext: currentContextName(),
+		watches:       watches,
+		watchInput:    watchInput,
+	}
+}
+
+// addTab appends a tab and makes it active
+func (s *logsSession) addTab(tab logsModel) {
+	tab.dockerContext = s.dockerContext
+	s.tabs = append(s.tabs, tab)
+	s.active = len(s.tabs) - 1
+	s.nextTab++
+}
+
+func (s *logsSession) Init() tea.Cmd {
+	if s.cli == nil {
+		return func() tea.Msg {
+			cli, err := newClient()
+			return sessionConnectedMsg{cli: cli, err: err}
+		}
+	}
+
+	var cmds []tea.Cmd
+	for _, tab := range s.tabs {
+		cmds = append(cmds, tab.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (s *logsSession) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+		s.height = msg.Height
+		return s, s.resize()
+
+	case logMsg:
+		i := s.tabIndex(msg.tab)
+		before := 0
+		if i >= 0 {
+			before = s.tabs[i].lines.len()
+		}
+		cmd := s.updateTab(i, msg)
+		return s, tea.Batch(cmd, s.checkWatches(i, before))
+
+	case streamEndMsg:
+		return s, s.updateTab(s.tabIndex(msg.tab), msg)
+
+	case sourceStatesMsg:
+		return s, s.updateTab(s.tabIndex(msg.tab), msg)
+
+	case followRetryMsg:
+		return s, s.updateTab(s.tabIndex(msg.tab), msg)
+
+	case logEventMsg:
+		return s, s.updateTab(s.tabIndex(msg.tab), msg)
+
+	case logClockMsg:
+		return s, s.updateTab(s.tabIndex(msg.tab), msg)
+
+	case logEventsErrMsg:
+		return s, s.updateTab(s.tabIndex(msg.tab), msg)
+
+	case inspectReadyMsg:
+		if msg.err != nil {
+			s.err = msg.err
+			return s, s.resize()
+		}
+		return s, openExternal(pagerCommand(), msg.data, "dockit-inspect-*.json")
+
+	case externalDoneMsg:
+		if msg.err != nil {
+			s.err = msg.err
+			return s, s.resize()
+		}
+		return s, nil
+
+	case contextSwitchedMsg:
+		if msg.err != nil {
+			s.err = msg.err
+			return s, s.resize()
+		}
+		return s, s.switchContext(msg.name, msg.cli)
+
+	case sessionConnectedMsg:
+		if msg.err != nil {
+			s.startErr = fmt.Errorf("error creating Docker client: %v", msg.err)
+			return s, tea.Quit
+		}
+		s.cli = msg.cli
+		return s, s.openStartupTabs()
+
+	case startupTabMsg:
+		if msg.err != nil {
+			s.startErr = msg.err
+			return s, tea.Quit
+		}
+		s.starting--
+		s.addTab(msg.tab)
+		// Stay on the first tab, like opening them all up front did
+		s.active = 0
+		return s, tea.Batch(msg.tab.Init(), s.resize())
+
+	case tabOpenedMsg:
+		if msg.err != nil {
+			s.err = msg.err
+			return s, s.resize()
+		}
+		s.addTab(msg.tab)
+		return s, tea.Batch(msg.tab.Init(), s.resize())
+
+	case tea.KeyMsg:
+		if key.Matches(msg, s.keys.Trace) {
+			s.tracing = !s.tracing
+			return s, nil
+		}
+		if s.tracing {
+			if key.Matches(msg, s.keys.Quit) || msg.String() == "esc" {
+				s.tracing = false
+			}
+			return s, nil
+		}
+		if s.picking {
+			return s, s.updateContextPicker(msg)
+		}
+		if s.opening {
+			return s, s.updateOpenPrompt(msg)
+		}
+		if s.watching {
+			return s, s.updateWatchPrompt(msg)
+		}
+		if s.alerting {
+			return s, s.updateNotifications(msg)
+		}
+		if len(s.tabs) == 0 {
+			return s, nil
+		}
+
+		// Keys typed into a tab's search or go to bar belong to the tab
+		if s.tabs[s.active].searchMode || s.tabs[s.active].gotoMode {
+			return s, s.updateTab(s.active, msg)
+		}
+
+		if s.err != nil {
+			// Dismiss the error before handling the key
+			s.err = nil
+			return s, tea.Batch(s.resize(), s.handleKey(msg))
+		}
+		return s, s.handleKey(msg)
+	}
+
+	if len(s.tabs) == 0 {
+		return s, nil
+	}
+	return s, s.updateTab(s.active, msg)
+}
+
+// handleKey applies session keys, passing anything else to the active tab
+func (s *logsSession) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, s.keys.Quit):
+		for i := range s.tabs {
+			s.tabs[i].cleanup()
+		}
+		return tea.Quit
+	case key.Matches(msg, s.keys.OpenTab):
+		s.opening = true
+		s.openInput.SetValue("")
+		s.openInput.Focus()
+		return s.resize()
+	case key.Matches(msg, s.keys.CloseTab):
+		return s.closeTab()
+	case key.Matches(msg, s.keys.Pager):
+		return openExternal(pagerCommand(), s.tabs[s.active].bufferText(), "dockit-logs-*.log")
+	case key.Matches(msg, s.keys.Editor):
+		return openExternal(editorCommand(), s.tabs[s.active].bufferText(), "dockit-logs-*.log")
+	case key.Matches(msg, s.keys.Inspect):
+		return s.inspectActive()
+	case key.Matches(msg, s.keys.Context):
+		return s.openContextPicker()
+	case key.Matches(msg, s.keys.Watch):
+		s.watching = true
+		s.watchInput.SetValue("")
+		s.watchInput.Focus()
+		return s.resize()
+	case key.Matches(msg, s.keys.Alerts):
+		return s.openNotifications()
+	case key.Matches(msg, s.keys.NextTab):
+		s.active = (s.active + 1) % len(s.tabs)
+		return nil
+	case key.Matches(msg, s.keys.PrevTab):
+		s.active = (s.active + len(s.tabs) - 1) % len(s.tabs)
+		return nil
+	}
+
+	if n, ok := s.tabNumber(msg.String()); ok {
+		if n < len(s.tabs) {
+			s.active = n
+		}
+		return nil
+	}
+
+	return s.updateTab(s.active, msg)
+}
+
+// tabNumber maps alt+1..9 to a tab, and plain 1..9 too when the active tab
+// doesn't use digits itself (merged containers or JSON level filters)
+func (s *logsSession) tabNumber(pressed string) (int, bool) {
+	digit := strings.TrimPrefix(pressed, "alt+")
+	if len(digit) != 1 || digit[0] < '1' || digit[0] > '9' {
+		return 0, false
+	}
+	if digit == pressed {
+		tab := s.tabs[s.active]
+		if len(s.tabs) < 2 || tab.structured || len(tab.sources) > 1 {
+			return 0, false
+		}
+	}
+	return int(digit[0] - '1'), true
+}
+
+func (s *logsSession) tabIndex(id int) int {
+	for i, tab := range s.tabs {
+		if tab.tab == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateTab passes msg to tab i, ignoring tabs that have been closed
+func (s *logsSession) updateTab(i int, msg tea.Msg) tea.Cmd {
+	if i < 0 {
+		return nil
+	}
+	model, cmd := s.tabs[i].Update(msg)
+	s.tabs[i] = model.(logsModel)
+	return cmd
+}
+
+// openStartupTabs opens the command line's containers in order
+func (s *logsSession) openStartupTabs() tea.Cmd {
+	var opens []tea.Cmd
+	for _, group := range s.startup {
+		ctx, cli, flags, id, group := s.ctx, s.cli, s.flags, s.nextTab, group
+		s.nextTab++
+		opens = append(opens, func() tea.Msg {
+			tab, err := newLogsModel(ctx, cli, group, flags, id)
+			return startupTabMsg{tab: tab, err: err}
+		})
+	}
+	s.starting = len(opens)
+	s.startup = nil
+	return tea.Sequence(opens...)
+}
+
+func (s *logsSession) updateOpenPrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		s.opening = false
+		if len(s.tabs) == 0 {
+			// Nothing left to show after switching contexts
+			return tea.Quit
+		}
+		return s.resize()
+	case "enter":
+		s.opening = false
+		names := strings.Fields(s.openInput.Value())
+		if len(names) == 0 {
+			if len(s.tabs) == 0 {
+				return tea.Quit
+			}
+			return s.resize()
+		}
+		ctx, cli, flags, id := s.ctx, s.cli, s.flags, s.nextTab
+		s.nextTab++
+		open := func() tea.Msg {
+			tab, err := newLogsModel(ctx, cli, names, flags, id)
+			return tabOpenedMsg{tab: tab, err: err}
+		}
+		return tea.Batch(open, s.resize())
+	}
+
+	var cmd tea.Cmd
+	s.openInput, cmd = s.openInput.Update(msg)
+	return cmd
+}
+
+// openContextPicker lists the Docker contexts to reconnect to
+func (s *logsSession) openContextPicker() tea.Cmd {
+	contexts, err := listContexts()
+	if err != nil {
+		s.err = fmt.Errorf("error reading contexts: %v", err)
+		return s.resize()
+	}
+
+	s.picking = true
+	s.contexts = contexts
+	s.contextCursor = 0
+	for i, c := range contexts {
+		if c.Name == s.dockerContext {
+			s.contextCursor = i
+		}
+	}
+	return s.resize()
+}
+
+func (s *logsSession) updateContextPicker(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case msg.String() == "esc" || key.Matches(msg, s.keys.Quit):
+		s.picking = false
+		return s.resize()
+	case key.Matches(msg, s.keys.Up):
+		s.contextCursor = max(0, s.contextCursor-1)
+	case key.Matches(msg, s.keys.Down):
+		s.contextCursor = min(len(s.contexts)-1, s.contextCursor+1)
+	case msg.String() == "enter":
+		s.picking = false
+		name := s.contexts[s.contextCursor].Name
+		if name == s.dockerContext {
+			return s.resize()
+		}
+		connect := func() tea.Msg {
+			cli, err := clientForContext(name)
+			if err == nil {
+				_, err = cli.Ping(context.Background())
+				if err != nil {
+					cli.Close()
+					err = fmt.Errorf("cannot reach context %s: %v", name, err)
+				}
+			}
+			return contextSwitchedMsg{name: name, cli: cli, err: err}
+		}
+		return tea.Batch(connect, s.resize())
+	}
+	return nil
+}
+
+// switchContext closes every tab, since their containers live on the old
+// daemon, and asks for containers to open on the new one
+func (s *logsSession) switchContext(name string, cli *client.Client) tea.Cmd {
+	for i := range s.tabs {
+		s.tabs[i].cleanup()
+	}
+	s.tabs = nil
+	s.active = 0
+	s.cli.Close()
+	s.cli = cli
+	s.dockerContext = name
+	SetContext(name)
+
+	s.opening = true
+	s.openInput.SetValue("")
+	s.openInput.Focus()
+	return s.resize()
+}
+
+// inspectActive fetches the raw inspect JSON of the active tab's containers
+func (s *logsSession) inspectActive() tea.Cmd {
+	ctx, cli := s.ctx, s.cli
+	var ids []string
+	for _, source := range s.tabs[s.active].sources {
+		ids = append(ids, source.id)
+	}
+
+	return func() tea.Msg {
+		var raw []json.RawMessage
+		for _, id := range ids {
+			_, data, err := cli.ContainerInspectWithRaw(ctx, id, false)
+			if err != nil {
+				return inspectReadyMsg{err: err}
+			}
+			raw = append(raw, data)
+		}
+		data, err := json.MarshalIndent(raw, "", "  ")
+		return inspectReadyMsg{data: data, err: err}
+	}
+}
+
+// closeTab stops the active tab's streams; closing the last tab quits
+func (s *logsSession) closeTab() tea.Cmd {
+	s.tabs[s.active].cleanup()
+	s.tabs = append(s.tabs[:s.active], s.tabs[s.active+1:]...)
+	if len(s.tabs) == 0 {
+		return tea.Quit
+	}
+	s.active = min(s.active, len(s.tabs)-1)
+	return s.resize()
+}
+
+// resize gives every tab the space left after the tab bar and prompt
+func (s *logsSession) resize() tea.Cmd {
+	height := s.height - s.chromeHeight()
+	var cmds []tea.Cmd
+	for i := range s.tabs {
+		cmds = append(cmds, s.updateTab(i, tea.WindowSizeMsg{Width: s.width, Height: height}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// chromeHeight is the number of lines used by the session around the tab
+func (s *logsSession) chromeHeight() int {
+	lines := 0
+	if s.showTabBar() {
+		lines++
+	}
+	if s.opening || s.watching || s.err != nil {
+		lines++
+	}
+	if s.picking {
+		lines += len(s.contexts) + 1
+	}
+	if s.alerting {
+		lines += min(len(s.notifications), notificationRows) + 1
+	}
+	return lines
+}
+
+// showTabBar reports whether the tab bar is up: with several tabs, or to
+// show unread watched lines
+func (s *logsSession) showTabBar() bool {
+	return len(s.tabs) > 1 || s.unread() > 0
+}
+
+func (s *logsSession) View() string {
+	if small := tooSmallView(s.width, s.height, 40, 8); s.width > 0 && small != "" {
+		return small
+	}
+	if s.tracing {
+		return renderAPITrace(s.height)
+	}
+
+	var sb strings.Builder
+	if len(s.tabs) == 0 {
+		sb.WriteString(titleStyle.Render(fmt.Sprintf("%sLOGS [ctx: %s]", glyphs.logs, s.dockerContext)))
+		if s.cli == nil || s.starting > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(helpStyle.Render("Connecting to Docker..."))
+		}
+	} else {
+		if s.showTabBar() {
+			sb.WriteString(s.renderTabBar())
+			sb.WriteString("\n")
+		}
+		sb.WriteString(s.tabs[s.active].View())
+	}
+
+	if s.alerting {
+		sb.WriteString("\n")
+		sb.WriteString(s.renderNotifications())
+		return sb.String()
+	}
+
+	if s.picking {
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Switch context (enter: connect, esc: cancel)"))
+		for i, c := range s.contexts {
+			sb.WriteString("\n")
+			line := fmt.Sprintf("  %-20s %s", c.Name, c.Host)
+			if c.Description != "" {
+				line += "  " + c.Description
+			}
+			if i == s.contextCursor {
+				sb.WriteString(activeTabStyle.Render(glyphs.cursor + line[1:]))
+			} else {
+				sb.WriteString(tabStyle.Render(line))
+			}
+		}
+		return sb.String()
+	}
+
+	switch {
+	case s.opening:
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Open: ") + s.openInput.View())
+	case s.watching:
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Watch for: ") + s.watchInput.View())
+	case s.err != nil:
+		sb.WriteString("\n")
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, s.err)))
+	}
+
+	return sb.String()
+}
+
+func (s *logsSession) renderTabBar() string {
+	var parts []string
+	for i, tab := range s.tabs {
+		var names []string
+		for _, source := range tab.sources {
+			names = append(names, source.name)
+		}
+		label := fmt.Sprintf("%d %s", i+1, strings.Join(names, "+"))
+		if i == s.active {
+			parts = append(parts, activeTabStyle.Render(label))
+		} else {
+			parts = append(parts, tabStyle.Render(label))
+		}
+	}
+	bar := strings.Join(parts, "")
+	if unread := s.unread(); unread > 0 {
+		bar += " " + errorStyle.Render(fmt.Sprintf("%s%d", glyphs.bell, unread))
+	}
+	return bar + helpStyle.Render("  o: open | x: close | tab: next | v/e: pager/editor | i: inspect | c: context | w/W: watch/alerts")
+}
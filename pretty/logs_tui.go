@@ -1,18 +1,20 @@
 package pretty
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/client"
 )
 
@@ -41,50 +43,231 @@ var (
 			Bold(true)
 )
 
+// sourceColors are cycled through to tell containers apart in multiplexed logs
+var sourceColors = []string{"#00d7ff", "#ff87d7", "#87ff5f", "#ffaf00", "#af87ff", "#5fd7af", "#ff5f5f", "#d7d75f"}
+
+// logsKeyMap holds the logs TUI key bindings
+type logsKeyMap struct {
+	Quit      key.Binding
+	Search    key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+	Pause     key.Binding
+	Follow    key.Binding
+	JSON      key.Binding
+	OpenTab   key.Binding
+	CloseTab  key.Binding
+	NextTab   key.Binding
+	PrevTab   key.Binding
+	Cause     key.Binding
+	Pager     key.Binding
+	Editor    key.Binding
+	Inspect   key.Binding
+	Context   key.Binding
+	Trace     key.Binding
+	Numbers   key.Binding
+	CopyRef   key.Binding
+	CopyLine  key.Binding
+	GoTo      key.Binding
+	Events    key.Binding
+	Times     key.Binding
+	Stderr    key.Binding
+	Watch     key.Binding
+	Alerts    key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+	Top       key.Binding
+	Bottom    key.Binding
+}
+
+// defaultLogsKeyMap returns the logs TUI bindings with config overrides applied
+func defaultLogsKeyMap() logsKeyMap {
+	return logsKeyMap{
+		Quit:      keyBinding("quit", "q", "ctrl+c"),
+		Search:    keyBinding("search", "/"),
+		NextMatch: keyBinding("next_match", "n"),
+		PrevMatch: keyBinding("prev_match", "N"),
+		Pause:     keyBinding("pause", " "),
+		Follow:    keyBinding("follow", "f"),
+		JSON:      keyBinding("json", "J"),
+		OpenTab:   keyBinding("open_tab", "o"),
+		CloseTab:  keyBinding("close_tab", "x"),
+		NextTab:   keyBinding("next_tab", "tab"),
+		PrevTab:   keyBinding("prev_tab", "shift+tab"),
+		Cause:     keyBinding("crash_cause", "!"),
+		Pager:     keyBinding("view_pager", "v"),
+		Editor:    keyBinding("view_editor", "e"),
+		Inspect:   keyBinding("view_inspect", "i"),
+		Context:   keyBinding("switch_context", "c"),
+		Trace:     keyBinding("api_trace", "ctrl+t"),
+		Numbers:   keyBinding("line_numbers", "#"),
+		CopyRef:   keyBinding("copy_reference", "r"),
+		CopyLine:  keyBinding("copy_line", "y"),
+		GoTo:      keyBinding("go_to", ":"),
+		Events:    keyBinding("log_events", "E"),
+		Times:     keyBinding("timestamps", "t"),
+		Stderr:    keyBinding("stderr_only", "s"),
+		Watch:     keyBinding("watch_pattern", "w"),
+		Alerts:    keyBinding("notifications", "W"),
+		Up:        keyBinding("up", "up", "k"),
+		Down:      keyBinding("down", "down", "j"),
+		PageUp:    keyBinding("page_up", "pgup"),
+		PageDown:  keyBinding("page_down", "pgdown"),
+		Top:       keyBinding("top", "home", "g"),
+		Bottom:    keyBinding("bottom", "end", "G"),
+	}
+}
+
+// Timestamp modes cycled by the t key
+const (
+	timestampsOff = iota
+	timestampsAbsolute
+	timestampsRelative
+)
+
+// logSource is one container whose logs are shown in the viewer
+type logSource struct {
+	id     string
+	name   string
+	style  lipgloss.Style
+	hidden bool
+	tty    bool // the container's logs are a plain TTY stream, not framed
+	state  *container.State
+}
+
 type logLine struct {
 	raw       string
 	formatted string
-	timestamp time.Time
+	timestamp time.Time // the daemon's, or when the line arrived
+	source    int
+	number    int // position among its container's lines, from 1
+	stderr    bool
+	marker    string // set on a lifecycle event's marker line instead of log text
+	action    events.Action
+	entry     *jsonLog // set when the line is a JSON object
 }
 
 type logsModel struct {
-	containerID   string
-	containerName string
-	lines         []logLine
-	scrollOffset  int
+	tab           int // identifies the tab within a logs session
+	opened        time.Time
+	dockerContext string // Docker context the tab's containers run on
+	sources       []logSource
+	lines         *logBuffer
+	shown         []int // indexes into lines from sources that aren't hidden
+	scrollOffset  int   // position in shown
+	lineCounts    []int // lines received per source, for numbering
+	numbers       bool  // show each line's number
+	timestamps    int   // timestampsOff, timestampsAbsolute, or timestampsRelative
+	clockGen      int   // the running relative timestamps tick
+	marked        int   // index into lines of the line go to landed on, or -1
+	notice        string
+	showEvents    bool // interleave lifecycle events as marker lines
+	eventStream   <-chan events.Message
+	eventErrs     <-chan error
+	eventsGen     int
+	lastEvent     time.Time
 	width         int
 	height        int
 	follow        bool
+	window        string    // the history loaded, from --tail, --since, and --until
+	until         time.Time // end of the logs to show, or zero
+	structured    bool      // render JSON lines as level/msg/fields
+	minLevel      int       // hide JSON lines less severe than this; levelNone shows everything
+	stderrOnly    bool
+	autoScroll    bool
 	paused        bool
+	keys          logsKeyMap
 	searchMode    bool
 	searchInput   textinput.Model
+	searches      []string // earlier searches, oldest first, that up and down recall
+	searchRecall  int      // position in searches; len(searches) is what is being typed
+	gotoMode      bool
+	gotoInput     textinput.Model
+	causes        []crashCause // probable exit causes of exited containers
+	causeIndex    int          // cause the ! key jumps to next
 	searchPattern *regexp.Regexp
 	matchCount    int
 	currentMatch  int
-	reader        io.ReadCloser
+	cli           *client.Client
+	stream        *logStream
+	streamGen     int
+	streamEnded   time.Time
+	refreshErr    errorBanner // repeated failures reconnecting the follow stream
 	ctx           context.Context
 	cancel        context.CancelFunc
 	done          bool
 }
 
+// logStream is a set of running log readers feeding lines over one channel
+type logStream struct {
+	lines  <-chan logLine
+	errs   <-chan error
+	follow bool
+	cancel context.CancelFunc
+}
+
+// logMsg and streamEndMsg carry the tab they belong to, so lines keep
+// flowing into tabs that aren't currently shown
 type logMsg struct {
 	line logLine
+	tab  int
+	gen  int
 }
 
-type errMsg struct {
+type streamEndMsg struct {
+	tab int
+	gen int
 	err error
 }
 
+// logClockMsg redraws relative timestamps as time passes
+type logClockMsg struct {
+	tab int
+	gen int
+}
+
+// followRetryMsg reconnects a follow stream that ended with an error
+type followRetryMsg struct {
+	tab int
+	gen int
+}
+
+// sourceStatesMsg carries freshly inspected container states after a stream ends
+type sourceStatesMsg struct {
+	tab    int
+	states []*container.State
+}
+
 func (m logsModel) Init() tea.Cmd {
 	return tea.Batch(
 		textinput.Blink,
-		m.readLogs(),
+		m.waitForLogLine(),
 	)
 }
 
 func (m logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.notice = ""
+		if m.gotoMode {
+			switch msg.String() {
+			case "enter":
+				m.gotoMode = false
+				if text := m.gotoInput.Value(); text != "" {
+					m.goToRef(text)
+				}
+				return m, nil
+			case "esc":
+				m.gotoMode = false
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.gotoInput, cmd = m.gotoInput.Update(msg)
+				return m, cmd
+			}
+		}
 		if m.searchMode {
 			switch msg.String() {
 			case "enter":
@@ -92,6 +275,7 @@ func (m logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchMode = false
 				pattern := m.searchInput.Value()
 				if pattern != "" {
+					updateState(func(state *tuiState) { addSearch(state, pattern) })
 					compiled, err := regexp.Compile("(?i)" + pattern)
 					if err == nil {
 						m.searchPattern = compiled
@@ -108,6 +292,20 @@ func (m logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchMode = false
 				m.searchInput.SetValue("")
 				return m, nil
+			case "up", "down":
+				// Step through earlier searches, past the newest back to empty
+				if msg.String() == "up" {
+					m.searchRecall = max(m.searchRecall-1, 0)
+				} else {
+					m.searchRecall = min(m.searchRecall+1, len(m.searches))
+				}
+				value := ""
+				if m.searchRecall < len(m.searches) {
+					value = m.searches[m.searchRecall]
+				}
+				m.searchInput.SetValue(value)
+				m.searchInput.CursorEnd()
+				return m, nil
 			default:
 				var cmd tea.Cmd
 				m.searchInput, cmd = m.searchInput.Update(msg)
@@ -115,77 +313,203 @@ func (m logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
-			m.cleanup()
-			return m, tea.Quit
-		case "/":
+		switch {
+		case key.Matches(msg, m.keys.Search):
 			m.searchMode = true
 			m.searchInput.Focus()
+			// Read the history each time, so searches from other tabs and
+			// other runs are there
+			m.searches = loadState().Searches
+			m.searchRecall = len(m.searches)
 			return m, nil
-		case "n":
+		case key.Matches(msg, m.keys.NextMatch):
 			if m.searchPattern != nil {
 				m.jumpToNextMatch()
 			}
 			return m, nil
-		case "N":
+		case key.Matches(msg, m.keys.PrevMatch):
 			if m.searchPattern != nil {
 				m.jumpToPrevMatch()
 			}
 			return m, nil
-		case " ":
+		case key.Matches(msg, m.keys.Pause):
 			m.paused = !m.paused
 			return m, nil
-		case "up", "k":
+		case key.Matches(msg, m.keys.Numbers):
+			m.numbers = !m.numbers
+			return m, nil
+		case key.Matches(msg, m.keys.CopyRef):
+			ref, ok := m.currentRef()
+			if !ok {
+				return m, nil
+			}
+			m.notice = "Copied " + ref.String()
+			return m, copyToClipboard(ref.String())
+		case key.Matches(msg, m.keys.CopyLine):
+			line, ok := m.topLine()
+			if !ok {
+				return m, nil
+			}
+			m.notice = copiedNotice(line.raw)
+			return m, copyToClipboard(line.raw)
+		case key.Matches(msg, m.keys.Times):
+			m.timestamps = (m.timestamps + 1) % 3
+			if m.timestamps == timestampsRelative {
+				m.clockGen++
+				return m, m.clockTick()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Events):
+			return m, m.toggleEvents()
+		case key.Matches(msg, m.keys.Stderr):
+			m.stderrOnly = !m.stderrOnly
+			m.rebuildShown()
+			return m, nil
+		case key.Matches(msg, m.keys.GoTo):
+			m.gotoMode = true
+			m.gotoInput.SetValue("")
+			m.gotoInput.Focus()
+			return m, nil
+		case key.Matches(msg, m.keys.Follow):
+			return m, m.toggleFollow()
+		case key.Matches(msg, m.keys.Cause):
+			m.jumpToCause()
+			return m, nil
+		case key.Matches(msg, m.keys.JSON):
+			m.structured = !m.structured
+			if !m.structured && m.minLevel != levelNone {
+				m.minLevel = levelNone
+				m.rebuildShown()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Up):
 			if m.scrollOffset > 0 {
 				m.scrollOffset--
+				m.autoScroll = false
 			}
 			return m, nil
-		case "down", "j":
-			maxScroll := max(0, len(m.lines)-m.contentHeight())
+		case key.Matches(msg, m.keys.Down):
+			maxScroll := m.maxScroll()
 			if m.scrollOffset < maxScroll {
 				m.scrollOffset++
 			}
+			m.autoScroll = m.scrollOffset >= maxScroll
 			return m, nil
-		case "pgup":
+		case key.Matches(msg, m.keys.PageUp):
 			m.scrollOffset = max(0, m.scrollOffset-m.contentHeight())
+			m.autoScroll = false
 			return m, nil
-		case "pgdown":
-			maxScroll := max(0, len(m.lines)-m.contentHeight())
+		case key.Matches(msg, m.keys.PageDown):
+			maxScroll := m.maxScroll()
 			m.scrollOffset = min(m.scrollOffset+m.contentHeight(), maxScroll)
+			m.autoScroll = m.scrollOffset >= maxScroll
 			return m, nil
-		case "home", "g":
+		case key.Matches(msg, m.keys.Top):
 			m.scrollOffset = 0
+			m.autoScroll = false
+			return m, nil
+		case key.Matches(msg, m.keys.Bottom):
+			m.scrollOffset = m.maxScroll()
+			m.autoScroll = true
 			return m, nil
-		case "end", "G":
-			m.scrollOffset = max(0, len(m.lines)-m.contentHeight())
+		}
+
+		// In JSON mode 1-4 filter by level (error, warn, info, debug); pressing
+		// the active level again clears the filter
+		if m.structured && len(msg.String()) == 1 {
+			if level := int(msg.String()[0]-'1') + levelError; level >= levelError && level <= levelDebug {
+				if m.minLevel == level {
+					m.minLevel = levelNone
+				} else {
+					m.minLevel = level
+				}
+				m.rebuildShown()
+			}
 			return m, nil
 		}
 
+		// Number keys show/hide individual containers
+		if len(m.sources) > 1 && len(msg.String()) == 1 {
+			if n := int(msg.String()[0] - '1'); n >= 0 && n < len(m.sources) && n < 9 {
+				m.sources[n].hidden = !m.sources[n].hidden
+				m.rebuildShown()
+			}
+		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
 
 	case logMsg:
+		// Ignore lines from a stream that has since been replaced
+		if msg.gen != m.streamGen {
+			return m, nil
+		}
 		if !m.paused {
-			m.lines = append(m.lines, msg.line)
-			// Auto-scroll to bottom if we're following and near the end
-			if m.follow {
-				maxScroll := max(0, len(m.lines)-m.contentHeight())
-				if m.scrollOffset >= maxScroll-5 { // Within 5 lines of bottom
-					m.scrollOffset = maxScroll
-				}
+			m.appendLine(msg.line)
+			// Stick to the bottom unless the user scrolled up
+			if m.autoScroll {
+				m.scrollOffset = m.maxScroll()
 			}
-			m.updateMatchCount()
 		}
-		if !m.done {
-			return m, m.readLogs()
+		return m, m.waitForLogLine()
+
+	case streamEndMsg:
+		if msg.gen != m.streamGen {
+			return m, nil
+		}
+		m.streamEnded = time.Now()
+		// Follow was switched on while the initial fetch was still running
+		if m.follow && !m.stream.follow && msg.err == nil {
+			return m, m.openFollowStream()
+		}
+		// The daemon dropped a live stream; keep following once it's back
+		if m.follow && m.stream.follow && msg.err != nil {
+			m.refreshErr.record(msg.err, time.Now())
+			return m, m.retryFollow()
+		}
+		m.done = true
+		m.follow = false
+		return m, m.inspectSources()
+
+	case logClockMsg:
+		if msg.gen != m.clockGen || m.timestamps != timestampsRelative {
+			return m, nil
+		}
+		return m, m.clockTick()
+
+	case logEventMsg:
+		if msg.gen != m.eventsGen {
+			return m, nil
+		}
+		m.insertEvent(msg.event)
+		return m, m.waitForLogEvent()
+
+	case logEventsErrMsg:
+		if msg.gen != m.eventsGen {
+			return m, nil
 		}
+		// Toggling events off and on again reconnects
+		m.eventStream = nil
+		m.showEvents = false
+		m.rebuildShown()
+		m.notice = fmt.Sprintf("events stream ended: %v", msg.err)
 		return m, nil
 
-	case errMsg:
-		m.done = true
+	case followRetryMsg:
+		if msg.gen != m.streamGen || !m.follow {
+			return m, nil
+		}
+		return m, m.openFollowStream()
+
+	case sourceStatesMsg:
+		for i, state := range msg.states {
+			if state != nil {
+				m.sources[i].state = state
+			}
+		}
+		m.analyzeCrashes()
 		return m, nil
 	}
 
@@ -200,7 +524,32 @@ func (m logsModel) View() string {
 	var sb strings.Builder
 
 	// Title
-	title := titleStyle.Render(fmt.Sprintf("📋 LOGS: %s", m.containerName))
+	indicator := ""
+	switch {
+	case m.paused || (m.follow && !m.autoScroll):
+		indicator = " [PAUSED]"
+	case m.follow:
+		indicator = " [FOLLOW]"
+	}
+	if m.window != "" {
+		indicator += " [" + m.window + "]"
+	}
+	if m.stderrOnly {
+		indicator += " [STDERR]"
+	}
+	if m.showEvents {
+		indicator += " [EVENTS]"
+	}
+	if m.structured {
+		indicator += " [JSON]"
+		if m.minLevel != levelNone {
+			indicator += fmt.Sprintf(" [%s+]", levelNames[m.minLevel])
+		}
+	}
+	if m.dockerContext != "" {
+		indicator += fmt.Sprintf(" [ctx: %s]", m.dockerContext)
+	}
+	title := titleStyle.Render(fmt.Sprintf("%sLOGS: %s%s", glyphs.logs, m.title(), indicator))
 	sb.WriteString(title)
 	sb.WriteString("\n")
 
@@ -209,7 +558,7 @@ func (m logsModel) View() string {
 	visibleLines := m.getVisibleLines(contentHeight)
 
 	for _, line := range visibleLines {
-		formatted := m.formatLine(line)
+		formatted := m.numberLine(line, m.stampLine(line, m.formatLine(line)))
 		sb.WriteString(formatted)
 		sb.WriteString("\n")
 	}
@@ -219,6 +568,18 @@ func (m logsModel) View() string {
 		sb.WriteString("\n")
 	}
 
+	// Probable cause of an exited container
+	if len(m.causes) > 0 {
+		sb.WriteString(m.renderCause())
+		sb.WriteString("\n")
+	}
+
+	// Repeated reconnect failures, collapsed into one line
+	if m.refreshErr.active() {
+		sb.WriteString(errorStyle.Render(m.refreshErr.String()))
+		sb.WriteString("\n")
+	}
+
 	// Status bar
 	statusBar := m.renderStatusBar()
 	sb.WriteString(statusBar)
@@ -228,36 +589,131 @@ func (m logsModel) View() string {
 		sb.WriteString("\n")
 		sb.WriteString(searchBarStyle.Render("Search: ") + m.searchInput.View())
 	}
+	if m.gotoMode {
+		sb.WriteString("\n")
+		sb.WriteString(searchBarStyle.Render("Go to: ") + m.gotoInput.View())
+	}
 
 	return sb.String()
 }
 
+// title names the container, or lists every container with its toggle key
+func (m *logsModel) title() string {
+	if len(m.sources) == 1 {
+		return m.sources[0].name
+	}
+
+	var parts []string
+	for i, source := range m.sources {
+		label := source.name
+		if i < 9 {
+			label = fmt.Sprintf("%d:%s", i+1, source.name)
+		}
+		if source.hidden {
+			parts = append(parts, helpStyle.Strikethrough(true).Render(label))
+		} else {
+			parts = append(parts, source.style.Render(label))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func (m *logsModel) contentHeight() int {
-	// Title (2 lines with margin), status bar (1 line), search bar (1 line if active)
+	// Title (2 lines with margin), status bar (1 line), search or go to bar, crash
+	// cause, and error banner (1 line each if shown)
 	reserved := 3
-	if m.searchMode {
+	if m.searchMode || m.gotoMode {
+		reserved++
+	}
+	if len(m.causes) > 0 {
+		reserved++
+	}
+	if m.refreshErr.active() {
 		reserved++
 	}
 	return max(1, m.height-reserved)
 }
 
+func (m *logsModel) maxScroll() int {
+	return max(0, len(m.shown)-m.contentHeight())
+}
+
 func (m *logsModel) getVisibleLines(count int) []logLine {
 	start := m.scrollOffset
-	end := min(start+count, len(m.lines))
+	end := min(start+count, len(m.shown))
 
-	if start >= len(m.lines) {
+	if start >= len(m.shown) {
 		return []logLine{}
 	}
 
-	return m.lines[start:end]
+	visible := make([]logLine, 0, end-start)
+	for _, index := range m.shown[start:end] {
+		visible = append(visible, m.lines.at(index))
+	}
+	return visible
 }
 
-func (m *logsModel) formatLine(line logLine) string {
-	text := line.raw
+// appendLine stores a new line, tracking visibility and search matches
+func (m *logsModel) appendLine(line logLine) {
+	line.entry, _ = parseJSONLog(lineText(line))
+	m.lineCounts[line.source]++
+	line.number = m.lineCounts[line.source]
+	m.lines.append(line)
+	if !m.lineVisible(line) {
+		return
+	}
+	m.shown = append(m.shown, m.lines.len()-1)
+	if m.searchPattern != nil && m.searchPattern.MatchString(lineText(line)) {
+		m.matchCount++
+	}
+}
 
-	// Skip the Docker header bytes if present
-	if len(text) > 8 {
-		text = text[8:]
+// lineVisible reports whether a line passes the container and level filters
+func (m *logsModel) lineVisible(line logLine) bool {
+	if m.sources[line.source].hidden {
+		return false
+	}
+	// Event markers show whatever the level filter
+	if line.marker != "" {
+		return m.showEvents
+	}
+	if m.stderrOnly && !line.stderr {
+		return false
+	}
+	if m.minLevel == levelNone {
+		return true
+	}
+	// Lines without a level only show when nothing is filtered out
+	return line.entry != nil && line.entry.level != levelNone && line.entry.level <= m.minLevel
+}
+
+// rebuildShown recomputes visible lines after a container, level, or filter changes
+func (m *logsModel) rebuildShown() {
+	m.shown = m.shown[:0]
+	for i, line := range m.lines.all() {
+		if m.lineVisible(line) {
+			m.shown = append(m.shown, i)
+		}
+	}
+	m.scrollOffset = min(m.scrollOffset, m.maxScroll())
+	if m.autoScroll {
+		m.scrollOffset = m.maxScroll()
+	}
+	m.updateMatchCount()
+}
+
+// lineText returns the log text, or a marker line's description
+func lineText(line logLine) string {
+	if line.marker != "" {
+		return line.marker
+	}
+	return line.raw
+}
+
+func (m *logsModel) formatLine(line logLine) string {
+	text := lineText(line)
+	if line.marker != "" {
+		text = markerStyle(line.action).Render(text)
 	}
 
 	// Apply search highlighting
@@ -266,13 +722,52 @@ func (m *logsModel) formatLine(line logLine) string {
 			// Don't show non-matching lines when search is active
 			return ""
 		}
-		text = m.highlightMatches(text)
+		// Structured lines and markers carry their own colors, so only raw
+		// text is highlighted
+		if (!m.structured || line.entry == nil) && line.marker == "" {
+			text = m.highlightMatches(text)
+		}
+	}
+
+	if m.structured && line.entry != nil {
+		text = line.entry.render()
+	} else if line.stderr {
+		text = errorStyle.UnsetBold().Render(text)
+	}
+
+	// Prefix with the container name when multiplexing
+	if len(m.sources) > 1 {
+		source := m.sources[line.source]
+		width := 0
+		for _, s := range m.sources {
+			width = max(width, len(s.name))
+		}
+		text = source.style.Render(source.name+strings.Repeat(" ", width-len(source.name))) + helpStyle.Render(" "+glyphs.divider+" ") + text
 	}
 
 	// Return raw text, preserving original terminal colors
 	return text
 }
 
+// stampLine puts the line's time before its text, as a time of day or how
+// long ago it was logged
+func (m *logsModel) stampLine(line logLine, text string) string {
+	if m.timestamps == timestampsOff || (text == "" && m.searchPattern != nil) {
+		return text
+	}
+	stamp := formatStamp(line.timestamp)
+	if m.timestamps == timestampsRelative {
+		stamp = fmt.Sprintf("%10s", formatAgo(time.Since(line.timestamp)))
+	}
+	return helpStyle.Render(stamp) + " " + text
+}
+
+// clockTick redraws once a second while timestamps are relative
+func (m *logsModel) clockTick() tea.Cmd {
+	tab, gen := m.tab, m.clockGen
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return logClockMsg{tab: tab, gen: gen} })
+}
+
 func (m *logsModel) highlightMatches(text string) string {
 	matches := m.searchPattern.FindAllStringIndex(text, -1)
 	if len(matches) == 0 {
@@ -294,30 +789,24 @@ func (m *logsModel) highlightMatches(text string) string {
 }
 
 func (m *logsModel) renderStatusBar() string {
-	pauseIndicator := ""
-	if m.paused {
-		pauseIndicator = " [PAUSED]"
-	}
-
-	followIndicator := ""
-	if m.follow {
-		followIndicator = " [FOLLOW]"
-	}
-
 	searchInfo := ""
 	if m.searchPattern != nil {
 		searchInfo = fmt.Sprintf(" | Matches: %d", m.matchCount)
 	}
 
-	status := fmt.Sprintf("Lines: %d/%d%s%s%s",
+	status := fmt.Sprintf("Lines: %d/%d%s",
 		m.scrollOffset+1,
-		len(m.lines),
-		pauseIndicator,
-		followIndicator,
+		len(m.shown),
 		searchInfo,
 	)
 
-	help := "q: quit | /: search | n/N: next/prev | ↑↓: scroll | space: pause | g/G: top/bottom"
+	help := "q: quit | /: search | n/N: next/prev | " + glyphs.arrows + ": scroll | space: pause | f: follow | J: json | t: time | E: events | #: numbers | r: copy ref | y: copy line | :: go to | g/G: top/bottom"
+	switch {
+	case m.structured:
+		help = "1-4: level | " + help
+	case len(m.sources) > 1:
+		help = "1-9: show/hide | " + help
+	}
 
 	// Calculate available width
 	availWidth := m.width - lipgloss.Width(status) - 4
@@ -326,6 +815,10 @@ func (m *logsModel) renderStatusBar() string {
 		help = "q: quit | /: search | space: pause"
 	}
 
+	if m.notice != "" {
+		status += " | " + m.notice
+	}
+
 	left := statusBarStyle.Render(status)
 	right := statusBarStyle.Render(help)
 
@@ -337,28 +830,133 @@ func (m *logsModel) renderStatusBar() string {
 	return left + strings.Repeat(" ", gap) + right
 }
 
-func (m *logsModel) readLogs() tea.Cmd {
-	return func() tea.Msg {
-		if m.reader == nil {
-			return errMsg{fmt.Errorf("reader is nil")}
-		}
+// startLogStream reads lines from every reader in the background until EOF
+// or ctx is cancelled; readers[i] belongs to source i
+func startLogStream(ctx context.Context, readers []logReader, follow bool) *logStream {
+	ctx, cancel := context.WithCancel(ctx)
+	lines := make(chan logLine)
+	errs := make(chan error, len(readers))
+
+	var wg sync.WaitGroup
+	for source, reader := range readers {
+		wg.Add(1)
+		go func(source int, reader logReader) {
+			defer wg.Done()
+			defer reader.Close()
+
+			err := demuxLogs(reader, reader.tty, func(text string, stderr bool) bool {
+				line := logLine{
+					raw:       text,
+					timestamp: time.Now(),
+					source:    source,
+					stderr:    stderr,
+				}
+				if raw, at, ok := splitLogTimestamp(line.raw); ok {
+					line.raw, line.timestamp = raw, at
+				}
+				select {
+				case lines <- line:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				errs <- err
+			}
+		}(source, reader)
 
-		scanner := bufio.NewScanner(m.reader)
-		if scanner.Scan() {
-			line := logLine{
-				raw:       scanner.Text(),
-				timestamp: time.Now(),
+		// Closing the reader unblocks a read waiting on a follow stream
+		go func(reader io.ReadCloser) {
+			<-ctx.Done()
+			reader.Close()
+		}(reader)
+	}
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	return &logStream{lines: lines, errs: errs, follow: follow, cancel: cancel}
+}
+
+func (m *logsModel) waitForLogLine() tea.Cmd {
+	stream, tab, gen := m.stream, m.tab, m.streamGen
+	return func() tea.Msg {
+		line, ok := <-stream.lines
+		if !ok {
+			var err error
+			select {
+			case err = <-stream.errs:
+			default:
 			}
-			return logMsg{line: line}
+			return streamEndMsg{tab: tab, gen: gen, err: err}
 		}
+		return logMsg{line: line, tab: tab, gen: gen}
+	}
+}
 
-		if err := scanner.Err(); err != nil && err != io.EOF {
-			return errMsg{err}
+// toggleFollow switches between a finished fetch and a live follow stream
+func (m *logsModel) toggleFollow() tea.Cmd {
+	if m.follow {
+		m.follow = false
+		m.refreshErr.clear()
+		if m.stream.follow && !m.done {
+			m.stream.cancel()
+			m.streamGen++
+			m.streamEnded = time.Now()
+			m.done = true
 		}
+		return nil
+	}
 
-		m.done = true
+	m.follow = true
+	m.autoScroll = true
+	m.scrollOffset = m.maxScroll()
+	if !m.done {
+		// The initial fetch is still running; follow starts when it ends
 		return nil
 	}
+	return m.openFollowStream()
+}
+
+// openFollowStream resumes streaming from where the previous stream ended
+func (m *logsModel) openFollowStream() tea.Cmd {
+	var readers []logReader
+	for _, source := range m.sources {
+		reader, err := m.cli.ContainerLogs(m.ctx, source.id, container.LogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Follow:     true,
+			Timestamps: true,
+			Since:      m.streamEnded.UTC().Format(time.RFC3339Nano),
+			Until:      logTimeOption(m.until),
+		})
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			m.refreshErr.record(err, time.Now())
+			return m.retryFollow()
+		}
+		readers = append(readers, logReader{reader, source.tty})
+	}
+
+	m.refreshErr.clear()
+	m.stream = startLogStream(m.ctx, readers, true)
+	m.streamGen++
+	m.done = false
+	return m.waitForLogLine()
+}
+
+// retryFollow schedules another follow attempt, backing off while the
+// same error keeps coming back
+func (m *logsModel) retryFollow() tea.Cmd {
+	tab, gen := m.tab, m.streamGen
+	return tea.Tick(m.refreshErr.retryDelay(), func(time.Time) tea.Msg {
+		return followRetryMsg{tab: tab, gen: gen}
+	})
 }
 
 func (m *logsModel) updateMatchCount() {
@@ -368,41 +966,33 @@ func (m *logsModel) updateMatchCount() {
 	}
 
 	count := 0
-	for _, line := range m.lines {
-		text := line.raw
-		if len(text) > 8 {
-			text = text[8:]
-		}
-		if m.searchPattern.MatchString(text) {
+	for _, index := range m.shown {
+		if m.searchPattern.MatchString(lineText(m.lines.at(index))) {
 			count++
 		}
 	}
 	m.matchCount = count
 }
 
+func (m *logsModel) matchesAt(position int) bool {
+	return m.searchPattern.MatchString(lineText(m.lines.at(m.shown[position])))
+}
+
 func (m *logsModel) jumpToNextMatch() {
 	if m.searchPattern == nil || m.matchCount == 0 {
 		return
 	}
 
-	for i := m.scrollOffset + 1; i < len(m.lines); i++ {
-		text := m.lines[i].raw
-		if len(text) > 8 {
-			text = text[8:]
-		}
-		if m.searchPattern.MatchString(text) {
+	for i := m.scrollOffset + 1; i < len(m.shown); i++ {
+		if m.matchesAt(i) {
 			m.scrollOffset = i
 			return
 		}
 	}
 
 	// Wrap around to beginning
-	for i := 0; i <= m.scrollOffset; i++ {
-		text := m.lines[i].raw
-		if len(text) > 8 {
-			text = text[8:]
-		}
-		if m.searchPattern.MatchString(text) {
+	for i := 0; i <= m.scrollOffset && i < len(m.shown); i++ {
+		if m.matchesAt(i) {
 			m.scrollOffset = i
 			return
 		}
@@ -415,68 +1005,229 @@ func (m *logsModel) jumpToPrevMatch() {
 	}
 
 	for i := m.scrollOffset - 1; i >= 0; i-- {
-		text := m.lines[i].raw
-		if len(text) > 8 {
-			text = text[8:]
-		}
-		if m.searchPattern.MatchString(text) {
+		if m.matchesAt(i) {
 			m.scrollOffset = i
 			return
 		}
 	}
 
 	// Wrap around to end
-	for i := len(m.lines) - 1; i >= m.scrollOffset; i-- {
-		text := m.lines[i].raw
-		if len(text) > 8 {
-			text = text[8:]
-		}
-		if m.searchPattern.MatchString(text) {
+	for i := len(m.shown) - 1; i >= m.scrollOffset; i-- {
+		if m.matchesAt(i) {
 			m.scrollOffset = i
 			return
 		}
 	}
 }
 
+// inspectSources re-reads container states, since a followed container may
+// have exited since the viewer opened
+func (m *logsModel) inspectSources() tea.Cmd {
+	ctx, cli, tab := m.ctx, m.cli, m.tab
+	ids := make([]string, len(m.sources))
+	for i, source := range m.sources {
+		ids[i] = source.id
+	}
+	return func() tea.Msg {
+		states := make([]*container.State, len(ids))
+		for i, id := range ids {
+			if info, err := cli.ContainerInspect(ctx, id); err == nil {
+				states[i] = info.State
+			}
+		}
+		return sourceStatesMsg{tab: tab, states: states}
+	}
+}
+
+// analyzeCrashes finds a probable exit cause for each exited container
+func (m *logsModel) analyzeCrashes() {
+	m.causes = nil
+	for s, source := range m.sources {
+		var texts []string
+		var indexes []int
+		for i, line := range m.lines.all() {
+			if line.source == s && line.marker == "" {
+				texts = append(texts, lineText(line))
+				indexes = append(indexes, i)
+			}
+		}
+
+		summary, line, ok := analyzeCrash(source.state, texts)
+		if !ok {
+			continue
+		}
+		cause := crashCause{source: s, summary: summary, line: -1}
+		if line >= 0 {
+			cause.line = indexes[line]
+		}
+		m.causes = append(m.causes, cause)
+	}
+	m.causeIndex = 0
+	m.scrollOffset = min(m.scrollOffset, m.maxScroll())
+}
+
+func (m *logsModel) renderCause() string {
+	cause := m.causes[m.causeIndex%len(m.causes)]
+	text := "Probable cause"
+	if len(m.sources) > 1 {
+		text += " (" + m.sources[cause.source].name + ")"
+	}
+	text += ": " + cause.summary
+	if cause.line >= 0 {
+		text += fmt.Sprintf(" at line %d (!: jump)", cause.line+1)
+	}
+	return errorStyle.Render(fmt.Sprintf("%s %s", glyphs.failed, text))
+}
+
+// jumpToCause scrolls to the log line behind the current crash cause, then
+// moves on to the next cause
+func (m *logsModel) jumpToCause() {
+	if len(m.causes) == 0 {
+		return
+	}
+	cause := m.causes[m.causeIndex%len(m.causes)]
+	m.causeIndex++
+	if cause.line < 0 {
+		return
+	}
+
+	// Make sure the line isn't hidden by a container or level filter
+	if !m.lineVisible(m.lines.at(cause.line)) {
+		m.sources[cause.source].hidden = false
+		m.minLevel = levelNone
+		m.rebuildShown()
+	}
+	for position, index := range m.shown {
+		if index == cause.line {
+			m.scrollOffset = min(position, m.maxScroll())
+			m.autoScroll = false
+			return
+		}
+	}
+}
+
+// bufferText returns the visible log lines as plain text, prefixed with the
+// container name when several are merged
+func (m *logsModel) bufferText() []byte {
+	var sb strings.Builder
+	for _, index := range m.shown {
+		line := m.lines.at(index)
+		if len(m.sources) > 1 {
+			sb.WriteString(m.sources[line.source].name + " | ")
+		}
+		sb.WriteString(lineText(line))
+		sb.WriteString("\n")
+	}
+	return []byte(sb.String())
+}
+
 func (m *logsModel) cleanup() {
 	if m.cancel != nil {
 		m.cancel()
 	}
-	if m.reader != nil {
-		m.reader.Close()
-	}
+	m.lines.close()
 }
 
-// LaunchLogsTUI starts the TUI for viewing container logs
-func LaunchLogsTUI(containerID string, follow bool) error {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		return fmt.Errorf("error creating Docker client: %v", err)
+// LaunchLogsTUI starts the TUI for viewing container logs. With more than one
+// container the streams are merged, each line prefixed with its container
+// name; with tabs set each container gets its own tab instead.
+func LaunchLogsTUI(containerIDs []string, flags logFlags, tabs bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The client is created once the TUI is up, and the session may
+	// reconnect to another context, so close whichever client it ends up with
+	session := newLogsSession(ctx, nil, flags)
+	defer func() {
+		if session.cli != nil {
+			session.cli.Close()
+		}
+	}()
+
+	session.startup = [][]string{containerIDs}
+	if tabs {
+		session.startup = nil
+		for _, containerID := range containerIDs {
+			session.startup = append(session.startup, []string{containerID})
+		}
 	}
-	defer cli.Close()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Remove the spill files of tabs still open however the TUI ends
+	defer func() {
+		for i := range session.tabs {
+			session.tabs[i].lines.close()
+		}
+	}()
 
-	// Get container info
-	containerInfo, err := cli.ContainerInspect(ctx, containerID)
-	if err != nil {
-		cancel()
-		return fmt.Errorf("error inspecting container: %v", err)
+	p := newProgram(session, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %v", err)
+	}
+	if session.startErr == nil {
+		updateState(rememberView)
 	}
 
-	// Get logs
-	logOptions := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Follow:     follow,
-		Timestamps: false,
-		Tail:       "100", // Start with last 100 lines
+	return session.startErr
+}
+
+// newLogsModel opens log streams for containerIDs, merged into one view.
+// Options not given on the command line come from each container's label
+// rule in the config, then the config defaults; merged containers are all
+// followed if any one of them is.
+func newLogsModel(ctx context.Context, cli *client.Client, containerIDs []string, flags logFlags, tab int) (logsModel, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var sources []logSource
+	var tails []string
+	follow := flags.follow
+	for i, containerID := range containerIDs {
+		// Get container info
+		containerInfo, err := cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			cancel()
+			return logsModel{}, fmt.Errorf("error inspecting container: %v", err)
+		}
+
+		var labels map[string]string
+		if containerInfo.Config != nil {
+			labels = containerInfo.Config.Labels
+		}
+		tail, followDefault := logDefaultsFor(labels)
+		if flags.tail != "" {
+			tail = flags.tail
+		}
+		follow = follow || followDefault
+		tails = append(tails, tail)
+
+		sources = append(sources, logSource{
+			id:    containerInfo.ID,
+			name:  strings.TrimPrefix(containerInfo.Name, "/"),
+			tty:   containerInfo.Config != nil && containerInfo.Config.Tty,
+			state: containerInfo.State,
+			style: lipgloss.NewStyle().Foreground(lipgloss.Color(sourceColors[i%len(sourceColors)])).Bold(true),
+		})
 	}
 
-	reader, err := cli.ContainerLogs(ctx, containerID, logOptions)
-	if err != nil {
-		cancel()
-		return fmt.Errorf("error getting container logs: %v", err)
+	// Get logs
+	var readers []logReader
+	for i, source := range sources {
+		reader, err := cli.ContainerLogs(ctx, source.id, container.LogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Follow:     follow,
+			Timestamps: true, // kept off the line's text, for references
+			Tail:       tails[i],
+			Since:      logTimeOption(flags.since),
+			Until:      logTimeOption(flags.until),
+		})
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			cancel()
+			return logsModel{}, fmt.Errorf("error getting container logs: %v", err)
+		}
+		readers = append(readers, logReader{reader, source.tty})
 	}
 
 	// Initialize search input
@@ -485,25 +1236,31 @@ func LaunchLogsTUI(containerID string, follow bool) error {
 	ti.CharLimit = 100
 	ti.Width = 50
 
-	model := logsModel{
-		containerID:   containerID,
-		containerName: containerInfo.Name[1:], // Remove leading /
-		lines:         []logLine{},
-		follow:        follow,
-		reader:        reader,
-		ctx:           ctx,
-		cancel:        cancel,
-		searchInput:   ti,
-	}
-
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		cancel()
-		reader.Close()
-		return fmt.Errorf("error running TUI: %v", err)
-	}
-
-	return nil
+	gotoInput := textinput.New()
+	gotoInput.Placeholder = "NAME@TIMESTAMP:LINE or a line number"
+	gotoInput.CharLimit = 200
+	gotoInput.Width = 60
+
+	return logsModel{
+		tab:         tab,
+		opened:      time.Now(),
+		sources:     sources,
+		lines:       newLogBuffer(),
+		follow:      follow,
+		autoScroll:  true,
+		keys:        defaultLogsKeyMap(),
+		cli:         cli,
+		stream:      startLogStream(ctx, readers, follow),
+		ctx:         ctx,
+		cancel:      cancel,
+		searchInput: ti,
+		gotoInput:   gotoInput,
+		lineCounts:  make([]int, len(sources)),
+		numbers:     config.Defaults.LogLineNumbers,
+		marked:      -1,
+		window:      describeLogWindow(tails, flags),
+		until:       flags.until,
+	}, nil
 }
 
 func min(a, b int) int {
@@ -0,0 +1,199 @@
+package pretty
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxNotifications is how many watch matches the W list keeps
+const maxNotifications = 100
+
+// notificationRows is how many notifications the W list shows at once
+const notificationRows = 10
+
+// logWatch is a pattern to look out for in a container's logs
+type logWatch struct {
+	container string // name, or a glob
+	pattern   *regexp.Regexp
+}
+
+// logNotification is a watched line that arrived out of view
+type logNotification struct {
+	tab  int // the tab's id, as in logsModel.tab
+	ref  logRef
+	text string
+	read bool
+}
+
+// configWatches compiles the watch rules from the config
+func configWatches() ([]logWatch, error) {
+	var watches []logWatch
+	for _, rule := range config.Watch {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return watches, fmt.Errorf("invalid watch pattern %q: %v", rule.Pattern, err)
+		}
+		watches = append(watches, logWatch{container: rule.Container, pattern: pattern})
+	}
+	return watches, nil
+}
+
+// ringBell rings the terminal bell, which most terminals also flash or badge
+func ringBell() tea.Cmd {
+	return func() tea.Msg {
+		os.Stdout.WriteString("\a")
+		return nil
+	}
+}
+
+// checkWatches looks at the line tab i just received, if it received one,
+// and notifies when it matches a watch while it isn't on screen. Lines
+// logged before the tab opened are history, not news.
+func (s *logsSession) checkWatches(i, before int) tea.Cmd {
+	if i < 0 || s.tabs[i].lines.len() <= before || len(s.watches) == 0 {
+		return nil
+	}
+	tab := &s.tabs[i]
+	line := tab.lines.at(tab.lines.len() - 1)
+	if line.marker != "" || line.timestamp.Before(tab.opened) {
+		return nil
+	}
+	if i == s.active && tab.lineVisible(line) && (tab.searchPattern == nil || tab.searchPattern.MatchString(lineText(line))) {
+		return nil
+	}
+
+	name := tab.sources[line.source].name
+	text := lineText(line)
+	for _, watch := range s.watches {
+		if matched, _ := path.Match(watch.container, name); !matched || !watch.pattern.MatchString(text) {
+			continue
+		}
+		s.notifications = append(s.notifications, logNotification{
+			tab:  tab.tab,
+			ref:  logRef{container: name, at: line.timestamp, line: line.number},
+			text: text,
+		})
+		if len(s.notifications) > maxNotifications {
+			s.notifications = s.notifications[len(s.notifications)-maxNotifications:]
+		}
+		return tea.Batch(ringBell(), s.resize())
+	}
+	return nil
+}
+
+// unread counts the notifications not yet seen in the W list
+func (s *logsSession) unread() int {
+	count := 0
+	for _, n := range s.notifications {
+		if !n.read {
+			count++
+		}
+	}
+	return count
+}
+
+// updateWatchPrompt reads a pattern to watch for in the active tab's
+// containers for the rest of the session
+func (s *logsSession) updateWatchPrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		s.watching = false
+		return s.resize()
+	case "enter":
+		s.watching = false
+		text := s.watchInput.Value()
+		if text == "" {
+			return s.resize()
+		}
+		pattern, err := regexp.Compile(text)
+		if err != nil {
+			s.err = fmt.Errorf("invalid pattern: %v", err)
+			return s.resize()
+		}
+		tab := &s.tabs[s.active]
+		var names []string
+		for _, source := range tab.sources {
+			s.watches = append(s.watches, logWatch{container: source.name, pattern: pattern})
+			names = append(names, source.name)
+		}
+		tab.notice = fmt.Sprintf("watching %s for %s", strings.Join(names, ", "), text)
+		return s.resize()
+	}
+
+	var cmd tea.Cmd
+	s.watchInput, cmd = s.watchInput.Update(msg)
+	return cmd
+}
+
+// openNotifications shows the watched lines, newest first
+func (s *logsSession) openNotifications() tea.Cmd {
+	if len(s.notifications) == 0 {
+		s.tabs[s.active].notice = "no watched lines yet (w: watch for a pattern)"
+		return nil
+	}
+	s.alerting = true
+	s.alertCursor = 0
+	return s.resize()
+}
+
+func (s *logsSession) updateNotifications(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case msg.String() == "esc" || key.Matches(msg, s.keys.Quit) || key.Matches(msg, s.keys.Alerts):
+		s.closeNotifications()
+	case key.Matches(msg, s.keys.Up):
+		s.alertCursor = max(0, s.alertCursor-1)
+	case key.Matches(msg, s.keys.Down):
+		s.alertCursor = min(len(s.notifications)-1, s.alertCursor+1)
+	case msg.String() == "enter":
+		n := s.notifications[len(s.notifications)-1-s.alertCursor]
+		s.closeNotifications()
+		i := s.tabIndex(n.tab)
+		if i < 0 {
+			s.err = fmt.Errorf("the tab with %s has been closed", n.ref.container)
+			return s.resize()
+		}
+		s.active = i
+		s.tabs[i].goToRef(n.ref.String())
+	}
+	return s.resize()
+}
+
+// closeNotifications hides the W list, counting everything as seen
+func (s *logsSession) closeNotifications() {
+	s.alerting = false
+	for i := range s.notifications {
+		s.notifications[i].read = true
+	}
+}
+
+// renderNotifications lists the watched lines around the cursor
+func (s *logsSession) renderNotifications() string {
+	var sb strings.Builder
+	sb.WriteString(searchBarStyle.Render("Watched lines (enter: jump, esc: close)"))
+
+	first := max(0, min(s.alertCursor-notificationRows/2, len(s.notifications)-notificationRows))
+	last := min(len(s.notifications), first+notificationRows)
+	for row := first; row < last; row++ {
+		n := s.notifications[len(s.notifications)-1-row]
+		unread := strings.Repeat(" ", lipgloss.Width(glyphs.bell))
+		if !n.read {
+			unread = glyphs.bell
+		}
+		line := fmt.Sprintf("  %s %-16s %s  %s", unread, n.ref.container, formatStamp(n.ref.at), n.text)
+		line = ellipsize(line, max(20, s.width))
+		sb.WriteString("\n")
+		if row == s.alertCursor {
+			sb.WriteString(activeTabStyle.Render(glyphs.cursor + line[1:]))
+		} else {
+			sb.WriteString(tabStyle.Render(line))
+		}
+	}
+	return sb.String()
+}
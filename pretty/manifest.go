@@ -0,0 +1,214 @@
+package pretty
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// manifestName is the manifest written beside saved and exported tarballs
+const manifestName = "manifest.json"
+
+// artifactManifest lists the tarballs in a directory with their checksums,
+// so they can be checked after being carried to another machine
+type artifactManifest struct {
+	Version   int             `json:"version"`
+	Artifacts []manifestEntry `json:"artifacts"`
+}
+
+type manifestEntry struct {
+	File     string    `json:"file"` // relative to the manifest
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	Kind     string    `json:"kind"`     // "images" or "container export"
+	Contents []string  `json:"contents"` // the images saved, or the container exported
+	Created  time.Time `json:"created"`
+}
+
+// recordArtifact adds a tarball to the manifest in its directory, replacing
+// any earlier entry for the same file, and returns the manifest's path
+func recordArtifact(path string, size int64, digest, kind string, contents []string) (string, error) {
+	manifestPath := filepath.Join(filepath.Dir(path), manifestName)
+	manifest, err := readManifest(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		manifest, err = &artifactManifest{Version: 1}, nil
+	}
+	if err != nil {
+		return manifestPath, err
+	}
+
+	entry := manifestEntry{
+		File:     filepath.Base(path),
+		Size:     size,
+		SHA256:   digest,
+		Kind:     kind,
+		Contents: contents,
+		Created:  time.Now().UTC().Truncate(time.Second),
+	}
+	replaced := false
+	for i := range manifest.Artifacts {
+		if manifest.Artifacts[i].File == entry.File {
+			manifest.Artifacts[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		manifest.Artifacts = append(manifest.Artifacts, entry)
+	}
+	sort.Slice(manifest.Artifacts, func(i, j int) bool { return manifest.Artifacts[i].File < manifest.Artifacts[j].File })
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifestPath, err
+	}
+	// Written beside it and renamed, so an interrupted write keeps the old one
+	tmp := manifestPath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return manifestPath, err
+	}
+	return manifestPath, os.Rename(tmp, manifestPath)
+}
+
+func readManifest(path string) (*artifactManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest artifactManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%s is not a dockit manifest: %v", path, err)
+	}
+	return &manifest, nil
+}
+
+// printRecorded reports the manifest an artifact was added to, or why it
+// wasn't; the tarball itself is fine either way
+func printRecorded(manifestPath string, err error) {
+	if err != nil {
+		yellow.Printf("%s Could not update %s: %v\n", glyphs.warn, manifestPath, err)
+		return
+	}
+	gray.Printf("  %s checksum recorded in %s\n", glyphs.detail, manifestPath)
+}
+
+// VerifyManifest checks every artifact a manifest lists against its size
+// and SHA256, exiting non-zero if any is missing or differs
+func VerifyManifest(args []string) {
+	quiet := false
+	path := manifestName
+	for _, arg := range args {
+		switch {
+		case arg == "-q" || arg == "--quiet":
+			quiet = true
+		case !strings.HasPrefix(arg, "-"):
+			path = arg
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			fmt.Println("Usage: dockit verify [-q] [MANIFEST]")
+			os.Exit(1)
+		}
+	}
+
+	manifest, err := readManifest(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading manifest: %v\n", err)
+		os.Exit(1)
+	}
+	showProgress := !quiet && isTerminal(os.Stdout)
+	if showProgress {
+		preparePalette()
+	}
+
+	if !quiet {
+		fmt.Println()
+		cyan.Printf("VERIFY: %s\n", path)
+		cyan.Println(strings.Repeat(glyphs.rule, 90))
+	}
+	failed := 0
+	for _, entry := range manifest.Artifacts {
+		problem := verifyArtifact(filepath.Join(filepath.Dir(path), entry.File), entry, showProgress)
+		if problem != "" {
+			failed++
+			red.Printf("%s %-40s ", glyphs.failed, ellipsize(entry.File, 40))
+			red.Println(problem)
+			continue
+		}
+		if !quiet {
+			green.Printf("%s %-40s ", glyphs.ok, ellipsize(entry.File, 40))
+			gray.Printf("%s  sha256:%s\n", formatSize(entry.Size), entry.SHA256[:12])
+		}
+	}
+
+	if failed > 0 {
+		if !quiet {
+			fmt.Println()
+			red.Printf("%s %d of %d artifacts failed verification\n", glyphs.failed, failed, len(manifest.Artifacts))
+		}
+		os.Exit(1)
+	}
+	if !quiet {
+		fmt.Println()
+		green.Printf("%s All %d artifacts match\n", glyphs.ok, len(manifest.Artifacts))
+	}
+}
+
+// verifyArtifact returns what is wrong with one artifact, or "" when it
+// matches. The size is checked first so a truncated copy fails without
+// being read.
+func verifyArtifact(path string, entry manifestEntry, showProgress bool) string {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "missing"
+	}
+	if err != nil {
+		return err.Error()
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err != nil {
+		return err.Error()
+	} else if info.Size() != entry.Size {
+		return fmt.Sprintf("size is %d bytes, expected %d", info.Size(), entry.Size)
+	}
+
+	digest, err := hashFile(file, entry.File, entry.Size, showProgress)
+	if err != nil {
+		return err.Error()
+	}
+	if digest != entry.SHA256 {
+		return fmt.Sprintf("checksum mismatch: sha256:%s, expected sha256:%s", digest[:12], entry.SHA256[:min(12, len(entry.SHA256))])
+	}
+	return ""
+}
+
+// hashFile returns the hex SHA256 of a file, drawing a progress line while
+// it reads
+func hashFile(reader io.Reader, label string, total int64, showProgress bool) (string, error) {
+	counter := &countingReader{r: reader}
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		progressPrinter(label, showProgress)(counter, total, stop)
+		close(finished)
+	}()
+
+	hasher := sha256.New()
+	_, err := io.Copy(hasher, counter)
+	close(stop)
+	<-finished
+	if showProgress {
+		fmt.Print("\r\033[K")
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
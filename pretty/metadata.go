@@ -0,0 +1,313 @@
+package pretty
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// metadataFile is the file name of shared metadata in a sync repo
+const metadataFile = "dockit-meta.yaml"
+
+// Metadata is the part of the config a team shares about a Docker host:
+// what must never be pruned, start profiles, and trusted registries
+type Metadata struct {
+	Protect           []string            `yaml:"protect,omitempty"`
+	Profiles          map[string][]string `yaml:"profiles,omitempty"`
+	TrustedRegistries []string            `yaml:"trusted_registries,omitempty"`
+}
+
+// metadataStorePath is where imported and synced metadata is kept, next to
+// the config file so hand-written config stays untouched
+func metadataStorePath() string {
+	path := ConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "metadata.yaml")
+}
+
+// configMetadata returns the shareable parts of the active config
+func configMetadata() Metadata {
+	return Metadata{
+		Protect:           config.Protect,
+		Profiles:          config.Profiles,
+		TrustedRegistries: config.TrustedRegistries,
+	}
+}
+
+// merge adds other's entries to m; m's profiles win when both define one
+func (m Metadata) merge(other Metadata) Metadata {
+	union := func(a, b []string) []string {
+		out := slices.Clone(a)
+		for _, value := range b {
+			if !slices.Contains(out, value) {
+				out = append(out, value)
+			}
+		}
+		return out
+	}
+
+	merged := Metadata{
+		Protect:           union(m.Protect, other.Protect),
+		TrustedRegistries: union(m.TrustedRegistries, other.TrustedRegistries),
+	}
+	if len(m.Profiles)+len(other.Profiles) > 0 {
+		merged.Profiles = map[string][]string{}
+		for name, containers := range other.Profiles {
+			merged.Profiles[name] = containers
+		}
+		for name, containers := range m.Profiles {
+			merged.Profiles[name] = containers
+		}
+	}
+	return merged
+}
+
+func readMetadata(path string) (Metadata, error) {
+	var m Metadata
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return m, nil
+}
+
+func writeMetadata(path string, m Metadata) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadMetadataStore merges the imported and synced metadata into the config;
+// entries written in the config file take precedence
+func loadMetadataStore() error {
+	path := metadataStorePath()
+	if path == "" {
+		return nil
+	}
+	stored, err := readMetadata(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	merged := configMetadata().merge(stored)
+	config.Protect = merged.Protect
+	config.Profiles = merged.Profiles
+	config.TrustedRegistries = merged.TrustedRegistries
+	return nil
+}
+
+// RunMetadata exports, imports, or syncs shared metadata
+func RunMetadata(args []string) {
+	if len(args) == 0 {
+		printMetadata()
+		return
+	}
+
+	switch args[0] {
+	case "export":
+		exportMetadata(args[1:])
+	case "import":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: file required\n")
+			fmt.Println("Usage: dockit meta import FILE")
+			os.Exit(1)
+		}
+		importMetadata(args[1])
+	case "sync":
+		syncMetadata()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown meta command %q\n", args[0])
+		fmt.Println("Usage: dockit meta [export [FILE] | import FILE | sync]")
+		os.Exit(1)
+	}
+}
+
+// printMetadata shows the metadata in effect and where it comes from
+func printMetadata() {
+	m := configMetadata()
+
+	fmt.Println()
+	cyan.Println("SHARED METADATA")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	gray.Printf("  %s Protect: ", glyphs.detail)
+	fmt.Println(strings.Join(m.Protect, ", "))
+	gray.Printf("  %s Trusted registries: ", glyphs.detail)
+	fmt.Println(strings.Join(m.TrustedRegistries, ", "))
+	gray.Printf("  %s Profiles: ", glyphs.detail)
+	names := make([]string, 0, len(m.Profiles))
+	for name := range m.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println(strings.Join(names, ", "))
+
+	fmt.Println()
+	gray.Printf("Store: %s\n", metadataStorePath())
+	if config.MetadataRepo != "" {
+		gray.Printf("Sync repo: %s\n", config.MetadataRepo)
+	}
+}
+
+// exportMetadata writes the metadata in effect to FILE, or stdout
+func exportMetadata(args []string) {
+	if len(args) == 0 || args[0] == "-" {
+		data, err := yaml.Marshal(configMetadata())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(data)
+		return
+	}
+
+	if err := writeMetadata(args[0], configMetadata()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Exported metadata to %s\n", args[0])
+}
+
+// importMetadata merges FILE into the local store
+func importMetadata(path string) {
+	incoming, err := readMetadata(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	store := metadataStorePath()
+	if store == "" {
+		fmt.Fprintf(os.Stderr, "Error: could not determine config location\n")
+		os.Exit(1)
+	}
+	existing, err := readMetadata(store)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeMetadata(store, existing.merge(incoming)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", store, err)
+		os.Exit(1)
+	}
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Imported %s ", path)
+	gray.Printf("(%d protect patterns, %d profiles, %d trusted registries)\n",
+		len(incoming.Protect), len(incoming.Profiles), len(incoming.TrustedRegistries))
+}
+
+// syncMetadata pulls the team's metadata from the configured git repo,
+// merges it with the local metadata, and pushes the result back
+func syncMetadata() {
+	repo := config.MetadataRepo
+	if repo == "" {
+		fmt.Fprintf(os.Stderr, "Error: no metadata_repo set in %s\n", ConfigPath())
+		os.Exit(1)
+	}
+	if strings.HasPrefix(repo, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			repo = filepath.Join(home, repo[2:])
+		}
+	}
+
+	remotes, err := git(repo, "remote")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a git repository: %v\n", repo, err)
+		os.Exit(1)
+	}
+	hasRemote := strings.TrimSpace(remotes) != ""
+
+	if hasRemote {
+		gray.Println("Pulling...")
+		if _, err := git(repo, "pull", "--ff-only"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error pulling %s: %v\n", repo, err)
+			os.Exit(1)
+		}
+	}
+
+	shared := filepath.Join(repo, metadataFile)
+	remote, err := readMetadata(shared)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Local entries win for profiles defined in both places
+	merged := configMetadata().merge(remote)
+	if err := writeMetadata(metadataStorePath(), merged); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", metadataStorePath(), err)
+		os.Exit(1)
+	}
+	if err := writeMetadata(shared, merged); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", shared, err)
+		os.Exit(1)
+	}
+
+	status, err := git(repo, "status", "--porcelain", "--", metadataFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(status) == "" {
+		green.Print(glyphs.ok + " ")
+		fmt.Println("Metadata is up to date")
+		return
+	}
+
+	host, _ := os.Hostname()
+	_, err = git(repo, "add", "--", metadataFile)
+	if err == nil {
+		_, err = git(repo, "commit", "-m", "Update dockit metadata from "+host, "--", metadataFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error committing %s: %v\n", shared, err)
+		os.Exit(1)
+	}
+	if hasRemote {
+		gray.Println("Pushing...")
+		if _, err := git(repo, "push"); err != nil {
+			fmt.Fprintf(os.Stderr, "Error pushing %s: %v\n", repo, err)
+			os.Exit(1)
+		}
+	}
+
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Synced metadata with %s\n", repo)
+}
+
+// git runs a git command in repo, returning its output or its stderr as the error
+func git(repo string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,488 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+)
+
+// networkDrivers are the drivers the wizard offers
+var networkDrivers = []string{"bridge", "overlay", "macvlan"}
+
+// networkCreateOptions holds the docker network create flags
+type networkCreateOptions struct {
+	name       string
+	driver     string
+	opts       map[string]string
+	labels     map[string]string
+	subnets    []string
+	gateways   []string
+	ipRanges   []string
+	auxAddress map[string]string
+	ipamDriver string
+	ipamOpts   map[string]string
+	scope      string
+	configFrom string
+	attachable bool
+	internal   bool
+	ipv6       bool
+	ingress    bool
+	configOnly bool
+}
+
+// CreateNetwork creates a network like docker network create, validating
+// driver-specific options and prompting for anything commonly missed; run
+// without arguments in a terminal it asks for every setting
+func CreateNetwork(args []string) {
+	options, err := parseNetworkCreateArgs(args)
+	if err == nil && options.name == "" && (len(args) > 0 || !isTerminal(os.Stdin)) {
+		err = fmt.Errorf("network name required")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Println("Usage: dockit network create [OPTIONS] NETWORK")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	if len(args) == 0 {
+		err = createNetworkInteractive(ctx, cli, options)
+	} else {
+		err = guideNetworkOptions(bufio.NewReader(os.Stdin), &options, isTerminal(os.Stdin))
+		if err == nil {
+			err = createNetwork(ctx, cli, options)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newNetworkCreateOptions() networkCreateOptions {
+	return networkCreateOptions{
+		driver:     "bridge",
+		opts:       map[string]string{},
+		labels:     map[string]string{},
+		auxAddress: map[string]string{},
+		ipamOpts:   map[string]string{},
+	}
+}
+
+func parseNetworkCreateArgs(args []string) (networkCreateOptions, error) {
+	options := newNetworkCreateOptions()
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// Support both "--flag value" and "--flag=value"
+		flag, value, hasValue := strings.Cut(arg, "=")
+		if !strings.HasPrefix(arg, "-") {
+			options.name = arg
+			continue
+		}
+		nextValue := func() string {
+			if hasValue {
+				return value
+			}
+			if i+1 < len(args) {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+
+		switch flag {
+		case "-d", "--driver":
+			options.driver = nextValue()
+		case "-o", "--opt":
+			k, v, _ := strings.Cut(nextValue(), "=")
+			options.opts[k] = v
+		case "--label":
+			k, v, _ := strings.Cut(nextValue(), "=")
+			options.labels[k] = v
+		case "--subnet":
+			options.subnets = append(options.subnets, nextValue())
+		case "--gateway":
+			options.gateways = append(options.gateways, nextValue())
+		case "--ip-range":
+			options.ipRanges = append(options.ipRanges, nextValue())
+		case "--aux-address":
+			k, v, _ := strings.Cut(nextValue(), "=")
+			options.auxAddress[k] = v
+		case "--ipam-driver":
+			options.ipamDriver = nextValue()
+		case "--ipam-opt":
+			k, v, _ := strings.Cut(nextValue(), "=")
+			options.ipamOpts[k] = v
+		case "--scope":
+			options.scope = nextValue()
+		case "--config-from":
+			options.configFrom = nextValue()
+		case "--attachable":
+			options.attachable = true
+		case "--internal":
+			options.internal = true
+		case "--ipv6":
+			options.ipv6 = true
+		case "--ingress":
+			options.ingress = true
+		case "--config-only":
+			options.configOnly = true
+		default:
+			return options, fmt.Errorf("unknown option %q", arg)
+		}
+	}
+
+	return options, nil
+}
+
+// guideNetworkOptions checks driver-specific options, asking for what is
+// missing when interactive, and validates the IPAM settings
+func guideNetworkOptions(reader *bufio.Reader, options *networkCreateOptions, interactive bool) error {
+	switch options.driver {
+	case "macvlan", "ipvlan":
+		if options.opts["parent"] == "" {
+			if !interactive {
+				return fmt.Errorf("%s networks require a parent interface (-o parent=eth0)", options.driver)
+			}
+			parent, err := promptParentInterface(reader)
+			if err != nil {
+				return err
+			}
+			options.opts["parent"] = parent
+		}
+		if !interfaceExists(options.opts["parent"]) {
+			yellow.Printf("%s Parent interface %q was not found on this host\n", glyphs.warn, options.opts["parent"])
+		}
+		if len(options.subnets) == 0 {
+			yellow.Println(glyphs.warn + " No --subnet given: Docker will pick one that likely doesn't match your physical network")
+		}
+	case "overlay":
+		if _, ok := options.opts["encrypted"]; !ok && interactive {
+			if promptYesNo(reader, "Encrypt overlay traffic between nodes (IPsec)?") {
+				options.opts["encrypted"] = ""
+			}
+		}
+		if !options.attachable {
+			gray.Println("Hint: add --attachable so standalone containers can join this overlay network")
+		}
+	}
+
+	return validateSubnets(options.subnets, options.gateways)
+}
+
+// createNetworkInteractive asks for the network's settings, then creates it
+func createNetworkInteractive(ctx context.Context, cli *client.Client, options networkCreateOptions) error {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println()
+	cyan.Println("NEW NETWORK")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	for {
+		name, err := promptLine(reader, "Name: ")
+		if err != nil {
+			return err
+		}
+		options.name = name
+		if err := validateNetworkName(ctx, cli, options.name); err != nil {
+			red.Printf("%s %v\n", glyphs.failed, err)
+			continue
+		}
+		break
+	}
+
+	for {
+		answer := prompt(reader, fmt.Sprintf("Driver (%s) [%s]: ", strings.Join(networkDrivers, "/"), options.driver))
+		if answer == "" {
+			break
+		}
+		if slices.Contains(networkDrivers, answer) {
+			options.driver = answer
+			break
+		}
+		red.Printf("%s Choose one of %s\n", glyphs.failed, strings.Join(networkDrivers, ", "))
+	}
+	if options.driver == "macvlan" {
+		parent, err := promptParentInterface(reader)
+		if err != nil {
+			return err
+		}
+		options.opts["parent"] = parent
+	}
+
+	// Subnet and gateway are optional; Docker picks a free range otherwise
+	for {
+		options.subnets, options.gateways = nil, nil
+		if subnet := prompt(reader, "Subnet, e.g. 172.30.0.0/16 (empty for automatic): "); subnet != "" {
+			options.subnets = []string{subnet}
+			if gateway := prompt(reader, "Gateway (empty for the first address): "); gateway != "" {
+				options.gateways = []string{gateway}
+			}
+		}
+		if err := validateSubnets(options.subnets, options.gateways); err != nil {
+			red.Printf("%s %v\n", glyphs.failed, err)
+			continue
+		}
+		break
+	}
+
+	options.internal = promptYesNo(reader, "Internal (no access to outside networks)?")
+	if options.driver == "overlay" {
+		options.attachable = promptYesNo(reader, "Attachable by standalone containers?")
+	}
+
+	if err := guideNetworkOptions(reader, &options, true); err != nil {
+		return err
+	}
+	fmt.Println()
+	return createNetwork(ctx, cli, options)
+}
+
+// validateNetworkName checks that a name is given and not already taken
+func validateNetworkName(ctx context.Context, cli *client.Client, name string) error {
+	if name == "" {
+		return fmt.Errorf("a name is required")
+	}
+	if _, err := cli.NetworkInspect(ctx, name, network.InspectOptions{}); err == nil {
+		return fmt.Errorf("network %s already exists", name)
+	} else if !client.IsErrNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// createNetwork creates the network and shows its details as the daemon
+// reports them
+func createNetwork(ctx context.Context, cli *client.Client, options networkCreateOptions) error {
+	create := network.CreateOptions{
+		Driver:     options.driver,
+		Scope:      options.scope,
+		Internal:   options.internal,
+		Attachable: options.attachable,
+		Ingress:    options.ingress,
+		ConfigOnly: options.configOnly,
+		Options:    options.opts,
+		Labels:     options.labels,
+	}
+	if options.ipv6 {
+		create.EnableIPv6 = &options.ipv6
+	}
+	if options.configFrom != "" {
+		create.ConfigFrom = &network.ConfigReference{Network: options.configFrom}
+	}
+	if len(options.subnets) > 0 || options.ipamDriver != "" || len(options.ipamOpts) > 0 {
+		create.IPAM = &network.IPAM{Driver: options.ipamDriver, Options: options.ipamOpts}
+		ipamConfig, err := ipamConfigs(options)
+		if err != nil {
+			return err
+		}
+		create.IPAM.Config = ipamConfig
+	}
+
+	created, err := cli.NetworkCreate(ctx, options.name, create)
+	if err != nil {
+		return fmt.Errorf("creating network: %v", err)
+	}
+	if created.Warning != "" {
+		yellow.Printf("%s %s\n", glyphs.warn, created.Warning)
+	}
+
+	// Scripts only need the ID, like docker network create prints
+	if !isTerminal(os.Stdout) {
+		fmt.Println(created.ID)
+		return nil
+	}
+
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Created network %s\n", options.name)
+
+	info, err := cli.NetworkInspect(ctx, created.ID, network.InspectOptions{})
+	if err != nil {
+		return nil
+	}
+	printNetworkDetails(info)
+	return nil
+}
+
+// ipamConfigs pairs each subnet with the gateway, IP range, and auxiliary
+// addresses that fall inside it, like the docker CLI does
+func ipamConfigs(options networkCreateOptions) ([]network.IPAMConfig, error) {
+	var configs []network.IPAMConfig
+	for _, subnet := range options.subnets {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q", subnet)
+		}
+		ipam := network.IPAMConfig{Subnet: subnet, AuxAddress: map[string]string{}}
+		for _, gateway := range options.gateways {
+			if ipNet.Contains(net.ParseIP(gateway)) {
+				ipam.Gateway = gateway
+			}
+		}
+		for _, ipRange := range options.ipRanges {
+			if ip, _, err := net.ParseCIDR(ipRange); err == nil && ipNet.Contains(ip) {
+				ipam.IPRange = ipRange
+			}
+		}
+		for host, address := range options.auxAddress {
+			if ipNet.Contains(net.ParseIP(address)) {
+				ipam.AuxAddress[host] = address
+			}
+		}
+		configs = append(configs, ipam)
+	}
+	if len(options.ipRanges) > 0 && len(configs) == 0 {
+		return nil, fmt.Errorf("--ip-range needs a --subnet")
+	}
+	return configs, nil
+}
+
+// printNetworkDetails shows a network's settings
+func printNetworkDetails(n network.Inspect) {
+	fmt.Println()
+	cyan.Printf("NETWORK: %s\n", n.Name)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	printDetail("ID", formatID(n.ID, config.Defaults.FullIDs))
+	printDetail("Driver", n.Driver)
+	printDetail("Scope", n.Scope)
+	for _, cfg := range n.IPAM.Config {
+		if cfg.Subnet != "" {
+			printDetail("Subnet", cfg.Subnet)
+		}
+		if cfg.Gateway != "" {
+			printDetail("Gateway", cfg.Gateway)
+		}
+		if cfg.IPRange != "" {
+			printDetail("IP range", cfg.IPRange)
+		}
+	}
+	printDetail("Internal", fmt.Sprint(n.Internal))
+	printDetail("Attachable", fmt.Sprint(n.Attachable))
+	if n.EnableIPv6 {
+		printDetail("IPv6", "enabled")
+	}
+	if len(n.Options) > 0 {
+		printDetail("Options", formatLabels(n.Options))
+	}
+	if len(n.Labels) > 0 {
+		printDetail("Labels", formatLabels(n.Labels))
+	}
+	fmt.Println()
+}
+
+// validateSubnets checks CIDR syntax and that each gateway falls inside a subnet
+func validateSubnets(subnets, gateways []string) error {
+	var networks []*net.IPNet
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return fmt.Errorf("invalid subnet %q (expected CIDR like 192.168.1.0/24)", subnet)
+		}
+		networks = append(networks, ipNet)
+	}
+
+	for _, gateway := range gateways {
+		ip := net.ParseIP(gateway)
+		if ip == nil {
+			return fmt.Errorf("invalid gateway %q", gateway)
+		}
+		inside := false
+		for _, ipNet := range networks {
+			if ipNet.Contains(ip) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return fmt.Errorf("gateway %s is not inside any given subnet", gateway)
+		}
+	}
+
+	return nil
+}
+
+func promptParentInterface(reader *bufio.Reader) (string, error) {
+	cyan.Println("Select the parent (host) interface for this network:")
+	var names []string
+	interfaces, _ := net.Interfaces()
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		names = append(names, iface.Name)
+		fmt.Printf("  %d) %s\n", len(names), iface.Name)
+	}
+
+	for {
+		answer, err := promptLine(reader, "Interface (number or name): ")
+		if err != nil {
+			return "", err
+		}
+		var index int
+		if _, err := fmt.Sscanf(answer, "%d", &index); err == nil && index >= 1 && index <= len(names) {
+			return names[index-1], nil
+		}
+		if answer != "" {
+			return answer, nil
+		}
+	}
+}
+
+func interfaceExists(name string) bool {
+	_, err := net.InterfaceByName(name)
+	return err == nil
+}
+
+func prompt(reader *bufio.Reader, label string) string {
+	answer, _ := promptLine(reader, label)
+	return answer
+}
+
+// promptLine is prompt for questions asked again until they're answered: it
+// reports stdin closing (ctrl+D) so the asking can stop
+func promptLine(reader *bufio.Reader, label string) (string, error) {
+	fmt.Print(label)
+	answer, err := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if err != nil && answer == "" {
+		fmt.Println()
+		if err == io.EOF {
+			return "", errors.New("cancelled")
+		}
+		return "", err
+	}
+	return answer, nil
+}
+
+func promptYesNo(reader *bufio.Reader, question string) bool {
+	answer := strings.ToLower(prompt(reader, question+" [y/N]: "))
+	return answer == "y" || answer == "yes"
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
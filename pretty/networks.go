@@ -0,0 +1,195 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+)
+
+// PrintNetworks displays networks in a pretty format
+func PrintNetworks(args []string) {
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	format, args, err := parseOutputFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Parse flags; filters are handed to the Docker API as-is, like docker network ls
+	fullIDs := config.Defaults.FullIDs
+	filterArgs := filters.NewArgs()
+	interactive := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var filter string
+		switch {
+		case arg == "--no-trunc":
+			fullIDs = true
+			continue
+		case arg == "-i" || arg == "--interactive":
+			interactive = true
+			continue
+		case (arg == "-f" || arg == "--filter") && i+1 < len(args):
+			i++
+			filter = args[i]
+		case strings.HasPrefix(arg, "--filter="):
+			filter = strings.TrimPrefix(arg, "--filter=")
+		default:
+			continue
+		}
+		if err := addFilter(filterArgs, filter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	networks, err := cli.NetworkList(ctx, network.ListOptions{Filters: filterArgs})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing networks: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+	used := networksInUse(containers)
+
+	if interactive {
+		reload := func() ([]bulkItem, error) {
+			networks, err := cli.NetworkList(ctx, network.ListOptions{Filters: filterArgs})
+			if err != nil {
+				return nil, err
+			}
+			sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+			containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+			if err != nil {
+				return nil, err
+			}
+			return networkBulkItems(networks, networksInUse(containers)), nil
+		}
+		runBulk(ctx, cli, "networks", networkBulkItems(networks, used), networkActions, reload)
+		return
+	}
+
+	if format.enabled() {
+		records := []networkRecord{}
+		for _, n := range networks {
+			records = append(records, newNetworkRecord(n, used))
+		}
+		printRecords(format, records)
+		return
+	}
+
+	if len(networks) == 0 {
+		gray.Println("No networks found")
+		gray.Println("(create one with 'dockit network create NAME')")
+		return
+	}
+
+	renderNetworks(networks, used, fullIDs)
+}
+
+// renderNetworks prints the pretty network list
+func renderNetworks(networks []network.Summary, used map[string]bool, fullIDs bool) {
+	// Print header
+	fmt.Println()
+	cyan.Println("NETWORKS")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	inUse := 0
+	for _, n := range networks {
+		// In-use indicator
+		statusColor, indicator := gray, glyphs.stopped
+		if used[n.Name] {
+			statusColor, indicator = green, glyphs.running
+			inUse++
+		}
+
+		// Network ID (short unless --no-trunc)
+		networkID := formatID(n.ID, fullIDs)
+		idPadded := networkID + strings.Repeat(" ", max(0, idWidth(fullIDs)-len(networkID)))
+
+		// Network name
+		name := n.Name
+		nameWidth := 30
+		if len(name) > nameWidth {
+			name = name[:nameWidth-3] + "..."
+		}
+		namePadded := name + strings.Repeat(" ", nameWidth-len(name))
+
+		// Driver and scope
+		driverWidth := 10
+		driverPadded := n.Driver + strings.Repeat(" ", max(0, driverWidth-len(n.Driver)))
+		scopeWidth := 6
+		scopePadded := n.Scope + strings.Repeat(" ", max(0, scopeWidth-len(n.Scope)))
+
+		// Print main line
+		statusColor.Print(indicator)
+		fmt.Print(" ")
+		gray.Print(idPadded)
+		gray.Print(" " + glyphs.divider + " ")
+		blue.Print(namePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		fmt.Print(driverPadded)
+		gray.Print(" " + glyphs.divider + " ")
+		fmt.Print(scopePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		gray.Println(formatTime(n.Created))
+
+		// Subnets and gateways
+		for _, cfg := range n.IPAM.Config {
+			if cfg.Subnet == "" {
+				continue
+			}
+			subnet := cfg.Subnet
+			if cfg.Gateway != "" {
+				subnet += " (gateway " + cfg.Gateway + ")"
+			}
+			gray.Printf("  %s Subnet: %s\n", glyphs.detail, subnet)
+		}
+
+		// Flags worth knowing about
+		var flags []string
+		if n.Internal {
+			flags = append(flags, "internal")
+		}
+		if n.Attachable {
+			flags = append(flags, "attachable")
+		}
+		if n.EnableIPv6 {
+			flags = append(flags, "ipv6")
+		}
+		if isProtected(n.Name, n.Labels) {
+			flags = append(flags, "protected")
+		}
+		if len(flags) > 0 {
+			gray.Printf("  %s %s\n", glyphs.detail, strings.Join(flags, ", "))
+		}
+
+		fmt.Println()
+	}
+
+	// Summary
+	fmt.Printf("Total: %d networks", len(networks))
+	if inUse > 0 {
+		green.Printf(" (%d in use)", inUse)
+	}
+	fmt.Println()
+}
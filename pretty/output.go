@@ -0,0 +1,110 @@
+package pretty
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// outputFormat selects machine-readable output for list commands in place
+// of the pretty rendering
+type outputFormat struct {
+	json     bool
+	template *template.Template
+}
+
+// templateFuncs are available in --format templates, matching docker's
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"truncate": func(s string, n int) string {
+		if len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+}
+
+// parseOutputFlags removes --json and --format from args, returning the
+// requested format and the remaining arguments
+func parseOutputFlags(args []string) (outputFormat, []string, error) {
+	var format outputFormat
+	var rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var value string
+		switch {
+		case arg == "--json":
+			format.json = true
+			continue
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return format, nil, fmt.Errorf("--format requires a template")
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			value = strings.TrimPrefix(arg, "--format=")
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+
+		if value == "json" {
+			format.json = true
+			continue
+		}
+		tmpl, err := template.New("format").Funcs(templateFuncs).Parse(value)
+		if err != nil {
+			return format, nil, fmt.Errorf("invalid --format template: %v", err)
+		}
+		format.template = tmpl
+	}
+
+	return format, rest, nil
+}
+
+// enabled reports whether machine-readable output was requested
+func (f outputFormat) enabled() bool {
+	return f.json || f.template != nil
+}
+
+// write renders records, a slice, as one JSON array or one template
+// execution per element
+func (f outputFormat) write(w io.Writer, records any) error {
+	if f.json {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	list := reflect.ValueOf(records)
+	for i := 0; i < list.Len(); i++ {
+		if err := f.template.Execute(w, list.Index(i).Interface()); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// printRecords writes records in the requested format, exiting on error
+func printRecords(format outputFormat, records any) {
+	if err := format.write(os.Stdout, records); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,184 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+	"gopkg.in/yaml.v3"
+)
+
+// imagePin is the digest a container's image reference resolves to
+type imagePin struct {
+	container string
+	ref       string
+	digest    string // empty when the image has no registry digest
+	pinned    bool   // the container was created from a digest reference
+}
+
+// PrintPins shows the exact digest behind each container's image tag and can
+// write them to a pin file
+func PrintPins(args []string) {
+	showAll := false
+	var output string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-a" || arg == "--all":
+			showAll = true
+		case (arg == "-w" || arg == "--write") && i+1 < len(args):
+			i++
+			output = args[i]
+		case strings.HasPrefix(arg, "--write="):
+			output = strings.TrimPrefix(arg, "--write=")
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: showAll})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+	if len(containers) == 0 {
+		gray.Println("No containers found")
+		return
+	}
+
+	var pins []imagePin
+	for _, c := range containers {
+		info, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil || info.Config == nil {
+			continue
+		}
+		pin := imagePin{container: strings.TrimPrefix(info.Name, "/"), ref: info.Config.Image}
+
+		if strings.Contains(pin.ref, "@") {
+			pin.pinned = true
+			pin.digest = pin.ref[strings.Index(pin.ref, "@")+1:]
+		} else if img, err := cli.ImageInspect(ctx, info.Image); err == nil {
+			pin.digest = repoDigest(pin.ref, img.RepoDigests)
+		}
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].container < pins[j].container })
+
+	renderPins(pins)
+
+	if output != "" {
+		written, err := writePinFile(output, pins)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", output, err)
+			os.Exit(1)
+		}
+		fmt.Println()
+		green.Printf("Wrote %d pins to %s\n", written, output)
+	}
+}
+
+// repoDigest finds the digest recorded for ref's repository
+func repoDigest(ref string, repoDigests []string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+	for _, rd := range repoDigests {
+		candidate, err := reference.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		if canonical, ok := candidate.(reference.Canonical); ok && candidate.Name() == named.Name() {
+			return canonical.Digest().String()
+		}
+	}
+	return ""
+}
+
+// renderPins prints each container's image reference and the digest behind it
+func renderPins(pins []imagePin) {
+	// Print header
+	fmt.Println()
+	cyan.Println("IMAGE PINS")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	mutable := 0
+	for _, pin := range pins {
+		statusColor, indicator := yellow, glyphs.warn
+		switch {
+		case pin.pinned:
+			statusColor, indicator = green, glyphs.ok
+		case pin.digest == "":
+			statusColor, indicator = gray, glyphs.stopped
+		default:
+			mutable++
+		}
+
+		name := ellipsize(pin.container, 25)
+		namePadded := name + strings.Repeat(" ", 25-len(name))
+		ref := ellipsize(pin.ref, 35)
+		refPadded := ref + strings.Repeat(" ", 35-len(ref))
+
+		digest := "no registry digest"
+		if pin.digest != "" {
+			digest = ellipsize(pin.digest, 19)
+		}
+
+		// Print main line
+		statusColor.Print(indicator)
+		fmt.Print(" ")
+		blue.Print(namePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		fmt.Print(refPadded)
+		gray.Print(" " + glyphs.divider + " ")
+		gray.Println(digest)
+
+		switch {
+		case pin.pinned:
+			gray.Printf("  %s Pinned by digest\n", glyphs.detail)
+		case pin.digest == "":
+			gray.Printf("  %s Built locally or never pushed, so there is nothing to pin\n", glyphs.detail)
+		default:
+			gray.Printf("  %s Pin: %s@%s\n", glyphs.detail, pin.ref, pin.digest)
+		}
+
+		fmt.Println()
+	}
+
+	// Summary
+	fmt.Printf("Total: %d containers", len(pins))
+	if mutable > 0 {
+		yellow.Printf(" (%d on mutable tags)", mutable)
+	}
+	fmt.Println()
+}
+
+// writePinFile writes a YAML tag -> pinned reference mapping, returning the
+// number of pins written
+func writePinFile(path string, pins []imagePin) (int, error) {
+	mapping := map[string]string{}
+	for _, pin := range pins {
+		if pin.digest == "" || pin.pinned {
+			continue
+		}
+		mapping[pin.ref] = pin.ref + "@" + pin.digest
+	}
+
+	data, err := yaml.Marshal(mapping)
+	if err != nil {
+		return 0, err
+	}
+	header := "# Image pins generated by 'dockit pins': tag -> the digest it resolved to\n"
+	return len(mapping), os.WriteFile(path, append([]byte(header), data...), 0644)
+}
@@ -0,0 +1,155 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// publishedPort is one host port a container publishes, once per protocol
+// even when Docker binds it on several addresses
+type publishedPort struct {
+	hostPort      uint16
+	proto         string
+	addresses     []string
+	containerPort uint16
+	container     string
+	service       string
+}
+
+// PrintPorts shows every published host port across the running containers,
+// sorted, with the free ranges between them
+func PrintPorts(args []string) {
+	showGaps := true
+	for _, arg := range args {
+		switch arg {
+		case "--no-gaps":
+			showGaps = false
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			fmt.Println("Usage: dockit ports [--no-gaps]")
+			os.Exit(1)
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(context.Background(), container.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+
+	ports := collectPublishedPorts(containers)
+
+	fmt.Println()
+	cyan.Printf("%-11s %-6s %-18s %-10s %-24s %s\n", "HOST PORT", "PROTO", "ADDRESS", "TO", "CONTAINER", "SERVICE")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	if len(ports) == 0 {
+		gray.Println("No published ports")
+		return
+	}
+
+	owners := map[string]bool{}
+	for i, port := range ports {
+		if showGaps && i > 0 {
+			printPortGap(ports[i-1].hostPort, port.hostPort)
+		}
+		owners[port.container] = true
+
+		fmt.Printf("%-11d ", port.hostPort)
+		gray.Printf("%-6s ", port.proto)
+		gray.Printf("%-18s ", ellipsize(strings.Join(port.addresses, ","), 18))
+		fmt.Printf("%-10d ", port.containerPort)
+		green.Printf("%-24s ", ellipsize(port.container, 24))
+		gray.Println(orDash(port.service))
+	}
+
+	fmt.Println()
+	gray.Printf("%d published port(s) across %d container(s)\n", len(ports), len(owners))
+}
+
+// collectPublishedPorts flattens the containers' published ports, merging
+// the IPv4 and IPv6 bindings of the same port, sorted by port then protocol
+func collectPublishedPorts(containers []container.Summary) []publishedPort {
+	type key struct {
+		hostPort uint16
+		proto    string
+		id       string
+	}
+	byKey := map[key]*publishedPort{}
+	var ports []*publishedPort
+	for _, c := range containers {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		for _, p := range c.Ports {
+			if p.PublicPort == 0 {
+				continue
+			}
+			k := key{p.PublicPort, p.Type, c.ID}
+			port, ok := byKey[k]
+			if !ok {
+				port = &publishedPort{
+					hostPort:      p.PublicPort,
+					proto:         p.Type,
+					containerPort: p.PrivatePort,
+					container:     name,
+					service:       c.Labels[composeServiceLabel],
+				}
+				byKey[k] = port
+				ports = append(ports, port)
+			}
+			address := p.IP
+			if address == "" {
+				address = "*"
+			}
+			port.addresses = append(port.addresses, address)
+		}
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].hostPort != ports[j].hostPort {
+			return ports[i].hostPort < ports[j].hostPort
+		}
+		if ports[i].proto != ports[j].proto {
+			return ports[i].proto < ports[j].proto
+		}
+		return ports[i].container < ports[j].container
+	})
+
+	out := make([]publishedPort, len(ports))
+	for i, port := range ports {
+		sort.Strings(port.addresses)
+		out[i] = *port
+	}
+	return out
+}
+
+// printPortGap shows the free ports between two published ones, drawn as a
+// bar that grows with the size of the gap
+func printPortGap(prev, next uint16) {
+	if next <= prev+1 {
+		return
+	}
+	free := int(next) - int(prev) - 1
+	span := fmt.Sprint(prev + 1)
+	if free > 1 {
+		span = fmt.Sprintf("%d-%d", prev+1, next-1)
+	}
+
+	// Two cells per digit of the gap size keeps big gaps readable
+	width := len(fmt.Sprint(free)) * 2
+	gray.Printf("%-11s %s %d free\n", "", strings.Repeat(glyphs.barEmpty, width)+" "+span, free)
+}
@@ -0,0 +1,212 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// RunProfile lists, starts, or stops the start profiles defined in the config
+func RunProfile(args []string) {
+	timeout := 60 * time.Second
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-t" || arg == "--timeout") && i+1 < len(args):
+			i++
+			d, err := time.ParseDuration(args[i])
+			if err != nil || d <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: invalid timeout %q\n", args[i])
+				os.Exit(1)
+			}
+			timeout = d
+		case !strings.HasPrefix(arg, "-"):
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) == 0 || positional[0] == "ls" || positional[0] == "list" {
+		printProfiles()
+		return
+	}
+
+	action := positional[0]
+	if (action != "start" && action != "stop") || len(positional) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: profile name required\n")
+		fmt.Println("Usage: dockit profile [list | start [-t TIMEOUT] NAME | stop NAME]")
+		os.Exit(1)
+	}
+
+	name := positional[1]
+	containers, ok := config.Profiles[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: no profile named %q in %s\n", name, ConfigPath())
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	if action == "start" {
+		startProfile(cli, name, containers, timeout)
+	} else {
+		stopProfile(cli, name, containers)
+	}
+}
+
+// printProfiles shows each profile's containers in start order with their state
+func printProfiles() {
+	if len(config.Profiles) == 0 {
+		gray.Println("No profiles defined")
+		gray.Printf("(add them under 'profiles:' in %s)\n", ConfigPath())
+		return
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Print header
+	fmt.Println()
+	cyan.Println("PROFILES")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	for _, name := range names {
+		blue.Println(name)
+		fmt.Print("  ")
+		for i, c := range config.Profiles[name] {
+			if i > 0 {
+				gray.Print(" " + glyphs.next + " ")
+			}
+			statusColor, indicator := gray, glyphs.stopped
+			if info, err := cli.ContainerInspect(ctx, c); err != nil {
+				statusColor, indicator = red, glyphs.failed
+			} else if info.State != nil && info.State.Running {
+				statusColor, indicator = green, glyphs.running
+				if info.State.Health != nil {
+					statusColor = healthColor(info.State.Health.Status)
+				}
+			}
+			statusColor.Print(indicator + " ")
+			fmt.Print(c)
+		}
+		fmt.Println()
+		fmt.Println()
+	}
+
+	fmt.Printf("Total: %d profiles\n", len(names))
+}
+
+// startProfile starts containers in order, waiting for each to be ready
+// before starting the next
+func startProfile(cli *client.Client, name string, containers []string, timeout time.Duration) {
+	ctx := context.Background()
+
+	fmt.Println()
+	cyan.Printf("STARTING PROFILE: %s\n", name)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	for i, c := range containers {
+		gray.Printf("[%d/%d] ", i+1, len(containers))
+		fmt.Printf("%s ", c)
+
+		start := time.Now()
+		status, err := startAndWait(ctx, cli, c, timeout)
+		if err != nil {
+			red.Print(glyphs.failed + " ")
+			red.Println(err)
+			if i+1 < len(containers) {
+				gray.Printf("Not started: %s\n", strings.Join(containers[i+1:], ", "))
+			}
+			os.Exit(1)
+		}
+		green.Print(glyphs.ok + " ")
+		gray.Printf("%s (%s)\n", status, time.Since(start).Round(100*time.Millisecond))
+	}
+
+	fmt.Println()
+	green.Printf("Profile %s is up (%d containers)\n", name, len(containers))
+}
+
+// startAndWait starts a container and waits until it is healthy, or just
+// running when it has no healthcheck
+func startAndWait(ctx context.Context, cli *client.Client, name string, timeout time.Duration) (string, error) {
+	info, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	alreadyRunning := info.State != nil && info.State.Running
+
+	if !alreadyRunning {
+		if err := cli.ContainerStart(ctx, info.ID, container.StartOptions{}); err != nil {
+			return "", err
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		info, err := cli.ContainerInspect(ctx, info.ID)
+		if err != nil {
+			return "", err
+		}
+		state := info.State
+
+		switch {
+		case state == nil:
+		case !state.Running && !state.Restarting:
+			return "", fmt.Errorf("exited with code %d", state.ExitCode)
+		case state.Health == nil && state.Running:
+			if alreadyRunning {
+				return "already running", nil
+			}
+			return "running", nil
+		case state.Health != nil && state.Health.Status == "healthy":
+			if alreadyRunning {
+				return "already running, healthy", nil
+			}
+			return "healthy", nil
+		case state.Health != nil && state.Health.Status == "unhealthy":
+			return "", fmt.Errorf("unhealthy (see 'dockit health %s')", name)
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("not ready after %s", timeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// stopProfile stops containers in reverse start order, one at a time
+func stopProfile(cli *client.Client, name string, containers []string) {
+	reversed := slices.Clone(containers)
+	slices.Reverse(reversed)
+
+	fmt.Println()
+	targets := stopContainers(context.Background(), cli, "STOPPING PROFILE: "+name, reversed, 1, nil)
+	if printStopSummary(targets) {
+		os.Exit(1)
+	}
+}
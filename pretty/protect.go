@@ -0,0 +1,26 @@
+package pretty
+
+import (
+	"path"
+	"strings"
+)
+
+// protectLabel marks a container, volume, network, or image that cleanup
+// flows must never remove, e.g. --label dockit.keep=true
+const protectLabel = "dockit.keep"
+
+// isProtected reports whether a resource carries the protection label or
+// its name matches one of the configured protect patterns
+func isProtected(name string, labels map[string]string) bool {
+	if value, ok := labels[protectLabel]; ok && !strings.EqualFold(value, "false") {
+		return true
+	}
+
+	name = strings.TrimPrefix(name, "/")
+	for _, pattern := range config.Protect {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// pruneCategory is one kind of resource dockit prune can clean up
+type pruneCategory struct {
+	name        string
+	count       int
+	reclaimable int64
+	hasSize     bool
+	selected    bool
+	note        string
+	protected   int // resources skipped because of the protection label or a protect pattern
+	prune       func(context.Context, *client.Client) (removed int, reclaimed int64, err error)
+}
+
+// builtinNetworks can never be removed
+var builtinNetworks = map[string]bool{"bridge": true, "host": true, "none": true}
+
+// PrintPrune shows reclaimable space per category, lets the user pick
+// categories, then prunes them and reports the space reclaimed
+func PrintPrune(args []string) {
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	categories, err := collectPruneCategories(ctx, cli)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing disk usage: %v\n", err)
+		os.Exit(1)
+	}
+
+	// -f/--force skips the interactive picker and prunes the default selection
+	force := false
+	for _, arg := range args {
+		if arg == "-f" || arg == "--force" {
+			force = true
+		}
+	}
+
+	if !force {
+		selected, ok, err := LaunchPruneTUI(categories)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			gray.Println("Prune cancelled")
+			return
+		}
+		categories = selected
+	}
+
+	// Print header
+	fmt.Println()
+	cyan.Println("PRUNE")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	var totalReclaimed int64
+	for _, category := range categories {
+		if !category.selected {
+			continue
+		}
+
+		removed, reclaimed, err := category.prune(ctx, cli)
+		if err != nil {
+			red.Print(glyphs.failed + " ")
+			fmt.Printf("%-20s ", category.name)
+			red.Println(err)
+			continue
+		}
+
+		totalReclaimed += reclaimed
+		green.Print(glyphs.ok + " ")
+		fmt.Printf("%-20s ", category.name)
+		gray.Printf("removed %d", removed)
+		if category.hasSize {
+			gray.Printf(", reclaimed %s", formatSize(reclaimed))
+		}
+		if category.protected > 0 {
+			yellow.Printf(" (%d protected)", category.protected)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Print("Total reclaimed: ")
+	green.Println(formatSize(totalReclaimed))
+}
+
+// collectPruneCategories uses the system/df API to compute what each prune would remove
+func collectPruneCategories(ctx context.Context, cli *client.Client) ([]pruneCategory, error) {
+	usage, err := systemDiskUsage(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+
+	// Containers, volumes, and networks are removed one by one rather than
+	// with the prune APIs, so protected ones can be skipped by name
+	var stoppedContainers []*container.Summary
+	usedNetworks := map[string]bool{}
+	for _, c := range usage.Containers {
+		if c.NetworkSettings != nil {
+			for name := range c.NetworkSettings.Networks {
+				usedNetworks[name] = true
+			}
+		}
+		if !containerStateIsActive(c) {
+			stoppedContainers = append(stoppedContainers, c)
+		}
+	}
+
+	containers := pruneCategory{
+		name:     "Stopped containers",
+		hasSize:  true,
+		selected: true,
+	}
+	var removableContainers []*container.Summary
+	for _, c := range stoppedContainers {
+		if isProtected(c.Names[0], c.Labels) {
+			containers.protected++
+			continue
+		}
+		removableContainers = append(removableContainers, c)
+		containers.count++
+		containers.reclaimable += c.SizeRw
+	}
+	containers.prune = func(ctx context.Context, cli *client.Client) (int, int64, error) {
+		removed, reclaimed := 0, int64(0)
+		for _, c := range removableContainers {
+			if err := cli.ContainerRemove(ctx, c.ID, container.RemoveOptions{}); err != nil {
+				return removed, reclaimed, err
+			}
+			removed++
+			reclaimed += c.SizeRw
+		}
+		return removed, reclaimed, nil
+	}
+
+	images := pruneCategory{
+		name:     "Dangling images",
+		hasSize:  true,
+		selected: true,
+		prune: func(ctx context.Context, cli *client.Client) (int, int64, error) {
+			// Dangling images have no name, so only the label protects them
+			report, err := cli.ImagesPrune(ctx, filters.NewArgs(
+				filters.Arg("dangling", "true"),
+				filters.Arg("label!", protectLabel),
+			))
+			return len(report.ImagesDeleted), int64(report.SpaceReclaimed), err
+		},
+	}
+	for _, img := range usage.Images {
+		dangling := len(img.RepoTags) == 0 || (len(img.RepoTags) == 1 && img.RepoTags[0] == "<none>:<none>")
+		if dangling && img.Containers == 0 {
+			if _, ok := img.Labels[protectLabel]; ok {
+				images.protected++
+				continue
+			}
+			images.count++
+			images.reclaimable += img.Size - img.SharedSize
+		}
+	}
+
+	volumes := pruneCategory{
+		name:    "Unused volumes",
+		hasSize: true,
+		note:    "includes named volumes",
+	}
+	var removableVolumes []*volume.Volume
+	for _, v := range usage.Volumes {
+		if v.UsageData != nil && v.UsageData.RefCount == 0 {
+			if isProtected(v.Name, v.Labels) {
+				volumes.protected++
+				continue
+			}
+			removableVolumes = append(removableVolumes, v)
+			volumes.count++
+			if v.UsageData.Size > 0 {
+				volumes.reclaimable += v.UsageData.Size
+			}
+		}
+	}
+	volumes.prune = func(ctx context.Context, cli *client.Client) (int, int64, error) {
+		removed, reclaimed := 0, int64(0)
+		for _, v := range removableVolumes {
+			if err := cli.VolumeRemove(ctx, v.Name, false); err != nil {
+				return removed, reclaimed, err
+			}
+			removed++
+			if v.UsageData.Size > 0 {
+				reclaimed += v.UsageData.Size
+			}
+		}
+		return removed, reclaimed, nil
+	}
+
+	networks := pruneCategory{
+		name:     "Unused networks",
+		selected: true,
+	}
+	networkList, err := cli.NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var removableNetworks []string
+	for _, n := range networkList {
+		if !builtinNetworks[n.Name] && n.Scope == "local" && !usedNetworks[n.Name] {
+			if isProtected(n.Name, n.Labels) {
+				networks.protected++
+				continue
+			}
+			removableNetworks = append(removableNetworks, n.ID)
+			networks.count++
+		}
+	}
+	networks.prune = func(ctx context.Context, cli *client.Client) (int, int64, error) {
+		removed := 0
+		for _, id := range removableNetworks {
+			if err := cli.NetworkRemove(ctx, id); err != nil {
+				return removed, 0, err
+			}
+			removed++
+		}
+		return removed, 0, nil
+	}
+
+	buildCache := pruneCategory{
+		name:     "Build cache",
+		hasSize:  true,
+		selected: true,
+		prune: func(ctx context.Context, cli *client.Client) (int, int64, error) {
+			report, err := cli.BuildCachePrune(ctx, build.CachePruneOptions{})
+			if err != nil {
+				return 0, 0, err
+			}
+			return len(report.CachesDeleted), int64(report.SpaceReclaimed), nil
+		},
+	}
+	for _, record := range usage.BuildCache {
+		if !record.InUse && !record.Shared {
+			buildCache.count++
+			buildCache.reclaimable += record.Size
+		}
+	}
+
+	return []pruneCategory{containers, images, volumes, networks, buildCache}, nil
+}
+
+// containerStateIsActive reports whether a container state should be kept by prune
+func containerStateIsActive(c *container.Summary) bool {
+	return c.State == "running" || c.State == "paused" || c.State == "restarting"
+}
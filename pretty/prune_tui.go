@@ -0,0 +1,119 @@
+package pretty
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	cursorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#00d7ff")).
+			Bold(true)
+
+	selectedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#00ff87"))
+)
+
+type pruneModel struct {
+	categories []pruneCategory
+	cursor     int
+	confirmed  bool
+}
+
+func (m pruneModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pruneModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.categories)-1 {
+				m.cursor++
+			}
+		case " ", "x":
+			m.categories[m.cursor].selected = !m.categories[m.cursor].selected
+		case "a":
+			// Select all, or clear all if everything is already selected
+			all := true
+			for _, c := range m.categories {
+				all = all && c.selected
+			}
+			for i := range m.categories {
+				m.categories[i].selected = !all
+			}
+		case "enter":
+			m.confirmed = true
+			return m, tea.Quit
+		}
+	}
+
+	return m, nil
+}
+
+func (m pruneModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render(glyphs.prune + "PRUNE"))
+	sb.WriteString("\n")
+
+	var total int64
+	for i, category := range m.categories {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+
+		checkbox := "[ ]"
+		if category.selected {
+			checkbox = selectedStyle.Render("[" + glyphs.ok + "]")
+			total += category.reclaimable
+		}
+
+		size := "-"
+		if category.hasSize {
+			size = formatSize(category.reclaimable)
+		}
+
+		line := fmt.Sprintf("%s %-20s %4d items  %10s", checkbox, category.name, category.count, size)
+		if category.note != "" {
+			line += helpStyle.Render("  (" + category.note + ")")
+		}
+		if category.protected > 0 {
+			line += helpStyle.Render(fmt.Sprintf("  %d protected", category.protected))
+		}
+		sb.WriteString(cursor + line + "\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("Reclaimable: %s\n", selectedStyle.Render(formatSize(total))))
+	sb.WriteString(helpStyle.Render("space: toggle | a: all/none | enter: prune | q: cancel"))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// LaunchPruneTUI lets the user choose which categories to prune. The returned
+// bool is false if the user cancelled.
+func LaunchPruneTUI(categories []pruneCategory) ([]pruneCategory, bool, error) {
+	model := pruneModel{categories: categories}
+
+	p := newProgram(model)
+	final, err := p.Run()
+	if err != nil {
+		return nil, false, fmt.Errorf("error running TUI: %v", err)
+	}
+
+	result := final.(pruneModel)
+	return result.categories, result.confirmed, nil
+}
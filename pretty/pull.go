@@ -0,0 +1,416 @@
+package pretty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	progressFillStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#00d7ff"))
+
+	progressDoneStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#00ff87"))
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#ff5f5f")).
+			Bold(true)
+)
+
+type layerProgress struct {
+	id      string
+	status  string
+	current int64
+	total   int64
+}
+
+type pullModel struct {
+	ref      string
+	layers   map[string]*layerProgress
+	order    []string
+	messages []string
+	updates  <-chan jsonmessage.JSONMessage
+	cancel   context.CancelFunc
+	err      error
+	done     bool
+}
+
+type pullProgressMsg struct {
+	msg jsonmessage.JSONMessage
+}
+
+type pullDoneMsg struct{}
+
+// PullImage pulls an image, showing per-layer download and extract progress
+func PullImage(args []string) {
+	var ref, expected string
+	options := image.PullOptions{}
+	quiet, verify := false, false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-a" || arg == "--all-tags":
+			options.All = true
+		case arg == "-q" || arg == "--quiet":
+			quiet = true
+		case arg == "--platform" && i+1 < len(args):
+			options.Platform = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--platform="):
+			options.Platform = strings.TrimPrefix(arg, "--platform=")
+		case arg == "--verify-digest":
+			verify = true
+		case strings.HasPrefix(arg, "--verify-digest="):
+			verify = true
+			expected = strings.TrimPrefix(arg, "--verify-digest=")
+		case !strings.HasPrefix(arg, "-"):
+			ref = arg
+		}
+	}
+
+	if ref == "" {
+		fmt.Fprintf(os.Stderr, "Error: image reference required\n")
+		fmt.Println("Usage: dockit pull [OPTIONS] NAME[:TAG|@DIGEST]")
+		os.Exit(1)
+	}
+
+	if verify {
+		var err error
+		if expected, err = requestedDigest(ref, expected); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if options.All {
+			fmt.Fprintf(os.Stderr, "Error: --verify-digest can't be combined with --all-tags\n")
+			os.Exit(1)
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	options.RegistryAuth = registryAuth(ref)
+	reader, err := cli.ImagePull(ctx, ref, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error pulling image: %v\n", err)
+		printLoginHint(ref, err)
+		os.Exit(1)
+	}
+	defer reader.Close()
+
+	updates := make(chan jsonmessage.JSONMessage)
+	errs := make(chan error, 1)
+	go decodePullStream(reader, updates, errs)
+
+	// Without a terminal there's nothing to redraw, so just report the result
+	if quiet || !isTerminal(os.Stdout) {
+		for range updates {
+		}
+		if err := <-errs; err != nil {
+			fmt.Fprintf(os.Stderr, "Error pulling image: %v\n", err)
+			printLoginHint(ref, err)
+			os.Exit(1)
+		}
+		if verify {
+			if err := verifyPulledDigest(ctx, cli, ref, expected); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Println(ref)
+		return
+	}
+
+	model := pullModel{
+		ref:     ref,
+		layers:  map[string]*layerProgress{},
+		updates: updates,
+		cancel:  cancel,
+	}
+
+	p := newProgram(model)
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Errors reported in the stream (or a cancelled pull) were already rendered
+	if err := final.(pullModel).err; err != nil {
+		printLoginHint(ref, err)
+		os.Exit(1)
+	}
+	if err := <-errs; err != nil {
+		fmt.Fprintf(os.Stderr, "Error pulling image: %v\n", err)
+		printLoginHint(ref, err)
+		os.Exit(1)
+	}
+
+	if verify {
+		if err := verifyPulledDigest(ctx, cli, ref, expected); err != nil {
+			red.Printf("%s ", glyphs.failed)
+			red.Println(err)
+			os.Exit(1)
+		}
+		green.Printf("%s ", glyphs.ok)
+		fmt.Printf("Verified %s\n", expected)
+	}
+}
+
+// printLoginHint suggests logging in when a pull failed for lack of credentials
+func printLoginHint(ref string, err error) {
+	if isAuthError(err) {
+		gray.Printf("  %s The registry wants credentials; run %s\n", glyphs.detail, loginHint(ref))
+	}
+}
+
+// pullImageTag pulls the first tag of an image, with saved credentials, so
+// the images picker can refresh it from its registry
+func pullImageTag(ctx context.Context, cli *client.Client, id string) error {
+	img, err := cli.ImageInspect(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(img.RepoTags) == 0 {
+		return fmt.Errorf("untagged, nothing to pull")
+	}
+	ref := img.RepoTags[0]
+
+	reader, err := cli.ImagePull(ctx, ref, image.PullOptions{RegistryAuth: registryAuth(ref)})
+	if err == nil {
+		err = jsonmessage.DisplayJSONMessagesStream(reader, io.Discard, 0, false, nil)
+		reader.Close()
+	}
+	if isAuthError(err) {
+		return fmt.Errorf("%s login required (run %s)", glyphs.warn, loginHint(ref))
+	}
+	return err
+}
+
+// requestedDigest returns the digest --verify-digest checks against: the one
+// in a NAME@DIGEST reference, or the flag's value, which must agree with it
+func requestedDigest(ref, flagDigest string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid reference %q: %v", ref, err)
+	}
+
+	var fromRef string
+	if digested, ok := named.(reference.Digested); ok {
+		fromRef = digested.Digest().String()
+	}
+
+	switch {
+	case flagDigest == "" && fromRef == "":
+		return "", fmt.Errorf("--verify-digest needs a digest: pull NAME@sha256:... or pass --verify-digest=sha256:...")
+	case flagDigest == "":
+		return fromRef, nil
+	}
+
+	if _, err := digest.Parse(flagDigest); err != nil {
+		return "", fmt.Errorf("invalid digest %q: %v", flagDigest, err)
+	}
+	if fromRef != "" && fromRef != flagDigest {
+		return "", fmt.Errorf("--verify-digest=%s doesn't match the reference's %s", flagDigest, fromRef)
+	}
+	return flagDigest, nil
+}
+
+// verifyPulledDigest checks that the local image for ref was pulled as the
+// expected content digest, as recorded in its repo digests (or its ID, with
+// the containerd image store)
+func verifyPulledDigest(ctx context.Context, cli *client.Client, ref, expected string) error {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return err
+	}
+
+	img, err := cli.ImageInspect(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("inspecting pulled image: %v", err)
+	}
+	if img.ID == expected {
+		return nil
+	}
+
+	var got []string
+	for _, rd := range img.RepoDigests {
+		candidate, err := reference.ParseNormalizedNamed(rd)
+		if err != nil || candidate.Name() != named.Name() {
+			continue
+		}
+		if canonical, ok := candidate.(reference.Canonical); ok {
+			if canonical.Digest().String() == expected {
+				return nil
+			}
+			got = append(got, canonical.Digest().String())
+		}
+	}
+
+	if len(got) == 0 {
+		return fmt.Errorf("digest mismatch: requested %s, but %s has no registry digest", expected, ref)
+	}
+	return fmt.Errorf("digest mismatch: requested %s, pulled %s", expected, strings.Join(got, ", "))
+}
+
+// decodePullStream parses the JSON progress stream, forwarding each message
+func decodePullStream(reader io.Reader, updates chan<- jsonmessage.JSONMessage, errs chan<- error) {
+	defer close(updates)
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				errs <- nil
+			} else {
+				errs <- err
+			}
+			return
+		}
+		if msg.Error != nil {
+			updates <- msg
+			errs <- msg.Error
+			return
+		}
+		updates <- msg
+	}
+}
+
+func waitForPullUpdate(updates <-chan jsonmessage.JSONMessage) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return pullDoneMsg{}
+		}
+		return pullProgressMsg{msg: msg}
+	}
+}
+
+func (m pullModel) Init() tea.Cmd {
+	return waitForPullUpdate(m.updates)
+}
+
+func (m pullModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			m.cancel()
+			m.err = fmt.Errorf("pull cancelled")
+			return m, tea.Quit
+		}
+
+	case pullProgressMsg:
+		m.applyProgress(msg.msg)
+		return m, waitForPullUpdate(m.updates)
+
+	case pullDoneMsg:
+		m.done = true
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m *pullModel) applyProgress(msg jsonmessage.JSONMessage) {
+	if msg.Error != nil {
+		m.err = msg.Error
+		return
+	}
+
+	// Messages without an ID describe the pull as a whole
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+		m.messages = append(m.messages, strings.TrimSpace(msg.Status+" "+msg.ID))
+		return
+	}
+
+	layer, ok := m.layers[msg.ID]
+	if !ok {
+		layer = &layerProgress{id: msg.ID}
+		m.layers[msg.ID] = layer
+		m.order = append(m.order, msg.ID)
+	}
+
+	layer.status = msg.Status
+	if msg.Progress != nil && msg.Progress.Total > 0 {
+		layer.current = msg.Progress.Current
+		layer.total = msg.Progress.Total
+	} else {
+		layer.current, layer.total = 0, 0
+	}
+}
+
+func (m pullModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("%sPULLING %s", glyphs.pull, m.ref)))
+	sb.WriteString("\n")
+
+	for _, id := range m.order {
+		layer := m.layers[id]
+		sb.WriteString(fmt.Sprintf("%-12s  %-18s ", layer.id, layer.status))
+
+		switch {
+		case layer.total > 0:
+			percent := float64(layer.current) / float64(layer.total)
+			sb.WriteString(renderProgressBar(percent, 30, false))
+			sb.WriteString(fmt.Sprintf(" %3.0f%%  %s/%s", percent*100, formatSize(layer.current), formatSize(layer.total)))
+		case layer.status == "Pull complete" || layer.status == "Already exists":
+			sb.WriteString(renderProgressBar(1, 30, true))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, message := range m.messages {
+		sb.WriteString(helpStyle.Render(message))
+		sb.WriteString("\n")
+	}
+
+	switch {
+	case m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+		sb.WriteString("\n")
+	case m.done:
+		sb.WriteString(progressDoneStyle.Render(fmt.Sprintf("%s Pulled %s", glyphs.ok, m.ref)))
+		sb.WriteString("\n")
+	default:
+		sb.WriteString(helpStyle.Render("q: cancel"))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// renderProgressBar draws a fixed-width bar for a 0-1 completion ratio
+func renderProgressBar(percent float64, width int, complete bool) string {
+	filled := int(percent * float64(width))
+	filled = max(0, min(filled, width))
+
+	style := progressFillStyle
+	if complete {
+		style = progressDoneStyle
+	}
+
+	return style.Render(strings.Repeat(glyphs.barFull, filled)) + helpStyle.Render(strings.Repeat(glyphs.barEmpty, width-filled))
+}
@@ -0,0 +1,419 @@
+package pretty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RunQuery evaluates a jq-like filter over dockit's resource records
+func RunQuery(args []string) {
+	var positional []string
+	raw := false
+	for _, arg := range args {
+		switch arg {
+		case "-r", "--raw-output":
+			raw = true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: resource required\n")
+		printQueryUsage()
+		os.Exit(1)
+	}
+
+	filter := "."
+	if len(positional) > 1 {
+		filter = positional[1]
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	records, err := collectRecords(context.Background(), cli, positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Round-trip through JSON so filters see plain maps, slices, and numbers
+	var input any
+	data, _ := json.Marshal(records)
+	if err := json.Unmarshal(data, &input); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	results, err := evalQuery(filter, input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, result := range results {
+		if s, ok := result.(string); ok && raw {
+			fmt.Println(s)
+			continue
+		}
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(out))
+	}
+}
+
+func printQueryUsage() {
+	fmt.Println("Usage: dockit query RESOURCE [FILTER] [-r]")
+	fmt.Println()
+	fmt.Println("Resources: containers, images, volumes, networks")
+	fmt.Println()
+	fmt.Println("Filters (a jq subset):")
+	fmt.Println("  .                     The whole list")
+	fmt.Println("  .[] / .[0]            Each element / one element")
+	fmt.Println("  .Field.Sub            Field access")
+	fmt.Println("  select(COND)          Keep values where COND is true (==, !=, <, <=, >, >=, and, or)")
+	fmt.Println("  {Key: .Field, ...}    Build an object")
+	fmt.Println("  length, keys          Size of a list/object, sorted object keys")
+	fmt.Println("  A | B                 Pipe results of A into B")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  dockit query containers '.[] | select(.State == \"running\") | .Name' -r")
+	fmt.Println("  dockit query containers '.[] | select(.Health == \"unhealthy\") | {Name, Image}'")
+	fmt.Println("  dockit query images '.[] | select(.Dangling) | .ID' -r")
+}
+
+// evalQuery runs a pipeline of filters, feeding every output of one stage into the next
+func evalQuery(filter string, input any) ([]any, error) {
+	values := []any{input}
+	for _, stage := range splitTopLevel(filter, "|") {
+		var next []any
+		for _, v := range values {
+			out, err := evalStage(strings.TrimSpace(stage), v)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func evalStage(stage string, v any) ([]any, error) {
+	switch {
+	case stage == "" || stage == ".":
+		return []any{v}, nil
+	case stage == "length":
+		switch t := v.(type) {
+		case []any:
+			return []any{float64(len(t))}, nil
+		case map[string]any:
+			return []any{float64(len(t))}, nil
+		case string:
+			return []any{float64(len(t))}, nil
+		case nil:
+			return []any{float64(0)}, nil
+		}
+		return nil, fmt.Errorf("length: unsupported value %v", v)
+	case stage == "keys":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("keys: not an object")
+		}
+		var keys []any
+		names := make([]string, 0, len(m))
+		for k := range m {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			keys = append(keys, k)
+		}
+		return []any{keys}, nil
+	case strings.HasPrefix(stage, "select(") && strings.HasSuffix(stage, ")"):
+		ok, err := evalCondition(stage[len("select("):len(stage)-1], v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return []any{v}, nil
+		}
+		return nil, nil
+	case strings.HasPrefix(stage, "{") && strings.HasSuffix(stage, "}"):
+		return evalObject(stage[1:len(stage)-1], v)
+	case strings.HasPrefix(stage, "."):
+		return evalPath(stage, v)
+	}
+
+	// Literals are allowed as stages too
+	if literal, err := parseLiteral(stage); err == nil {
+		return []any{literal}, nil
+	}
+	return nil, fmt.Errorf("unsupported filter %q", stage)
+}
+
+// evalPath walks a path like .Labels["com.docker.compose.project"] or .[].Names[0]
+func evalPath(path string, v any) ([]any, error) {
+	values := []any{v}
+	rest := strings.TrimPrefix(path, ".")
+
+	for rest != "" {
+		var next []any
+		switch {
+		case strings.HasPrefix(rest, "[]"):
+			rest = rest[2:]
+			for _, value := range values {
+				switch t := value.(type) {
+				case []any:
+					next = append(next, t...)
+				case map[string]any:
+					keys := make([]string, 0, len(t))
+					for k := range t {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, t[k])
+					}
+				case nil:
+				default:
+					return nil, fmt.Errorf("cannot iterate over %v", value)
+				}
+			}
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in %q", path)
+			}
+			index := rest[1:end]
+			rest = rest[end+1:]
+			for _, value := range values {
+				if key, err := strconv.Unquote(index); err == nil {
+					next = append(next, field(value, key))
+					continue
+				}
+				i, err := strconv.Atoi(index)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q", index)
+				}
+				list, _ := value.([]any)
+				if i < 0 {
+					i += len(list)
+				}
+				if i >= 0 && i < len(list) {
+					next = append(next, list[i])
+				} else {
+					next = append(next, nil)
+				}
+			}
+		default:
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			for _, value := range values {
+				next = append(next, field(value, name))
+			}
+		}
+		values = next
+	}
+
+	return values, nil
+}
+
+func field(v any, name string) any {
+	if m, ok := v.(map[string]any); ok {
+		return m[name]
+	}
+	return nil
+}
+
+// evalObject builds an object from "Key: .path, Other" pairs
+func evalObject(body string, v any) ([]any, error) {
+	result := map[string]any{}
+	for _, entry := range splitTopLevel(body, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, expr, found := strings.Cut(entry, ":")
+		key = strings.TrimSpace(key)
+		if !found {
+			expr = "." + key
+		}
+		values, err := evalQuery(strings.TrimSpace(expr), v)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 1 {
+			result[key] = values[0]
+		} else {
+			result[key] = values
+		}
+	}
+	return []any{result}, nil
+}
+
+// evalCondition evaluates comparisons joined by "and"/"or"
+func evalCondition(cond string, v any) (bool, error) {
+	for _, alternative := range splitTopLevel(cond, " or ") {
+		all := true
+		for _, term := range splitTopLevel(alternative, " and ") {
+			ok, err := evalComparison(strings.TrimSpace(term), v)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func evalComparison(term string, v any) (bool, error) {
+	for _, op := range []string{"==", "!=", ">=", "<=", ">", "<"} {
+		parts := splitTopLevel(term, op)
+		if len(parts) != 2 {
+			continue
+		}
+		left, err := evalOperand(strings.TrimSpace(parts[0]), v)
+		if err != nil {
+			return false, err
+		}
+		right, err := evalOperand(strings.TrimSpace(parts[1]), v)
+		if err != nil {
+			return false, err
+		}
+		return compare(left, op, right), nil
+	}
+
+	// No operator: test truthiness
+	value, err := evalOperand(term, v)
+	if err != nil {
+		return false, err
+	}
+	return value != nil && value != false, nil
+}
+
+func evalOperand(expr string, v any) (any, error) {
+	if strings.HasPrefix(expr, ".") {
+		values, err := evalPath(expr, v)
+		if err != nil || len(values) == 0 {
+			return nil, err
+		}
+		return values[0], nil
+	}
+	return parseLiteral(expr)
+}
+
+func parseLiteral(expr string) (any, error) {
+	var value any
+	if err := json.Unmarshal([]byte(expr), &value); err != nil {
+		return nil, fmt.Errorf("invalid value %q", expr)
+	}
+	return value, nil
+}
+
+func compare(left any, op string, right any) bool {
+	ln, lok := left.(float64)
+	rn, rok := right.(float64)
+	if lok && rok {
+		switch op {
+		case "==":
+			return ln == rn
+		case "!=":
+			return ln != rn
+		case ">":
+			return ln > rn
+		case "<":
+			return ln < rn
+		case ">=":
+			return ln >= rn
+		case "<=":
+			return ln <= rn
+		}
+	}
+
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	if lok && rok {
+		switch op {
+		case ">":
+			return ls > rs
+		case "<":
+			return ls < rs
+		case ">=":
+			return ls >= rs
+		case "<=":
+			return ls <= rs
+		}
+	}
+
+	switch op {
+	case "==":
+		return reflect.DeepEqual(left, right)
+	case "!=":
+		return !reflect.DeepEqual(left, right)
+	}
+	return false
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside quotes or brackets
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth := 0
+	inString := false
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		case c == '"':
+			inString = true
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			continue
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			continue
+		}
+
+		if depth == 0 && strings.HasPrefix(s[i:], sep) {
+			// Don't split ">=" on ">" or "==" on "="
+			if (sep == ">" || sep == "<") && i+1 < len(s) && s[i+1] == '=' {
+				continue
+			}
+			parts = append(parts, s[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
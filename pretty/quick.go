@@ -0,0 +1,28 @@
+package pretty
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PrintQuick opens the single-container view: live stats, the log tail, and
+// restart/stop/exec keys on one screen
+func PrintQuick(args []string) {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit quick CONTAINER")
+		os.Exit(1)
+	}
+
+	if err := LaunchQuickTUI(positional[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,137 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/muesli/cancelreader"
+)
+
+// attachDetachKeys leave an attached container running, like docker attach
+const attachDetachKeys = "ctrl-p,ctrl-q"
+
+// attachCommand attaches the terminal to a container's main process. It is
+// run through tea.Exec, which suspends the TUI and hands over the terminal.
+type attachCommand struct {
+	cli    *client.Client
+	id     string
+	name   string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *attachCommand) SetStdin(r io.Reader)  { c.stdin = r }
+func (c *attachCommand) SetStdout(w io.Writer) { c.stdout = w }
+func (c *attachCommand) SetStderr(w io.Writer) { c.stderr = w }
+
+// attachContainer suspends the TUI and attaches to the container until it
+// exits or the detach keys are pressed
+func attachContainer(cli *client.Client, id, name string) tea.Cmd {
+	return tea.Exec(&attachCommand{cli: cli, id: id, name: name}, func(err error) tea.Msg {
+		return externalDoneMsg{err: err}
+	})
+}
+
+func (c *attachCommand) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	info, err := c.cli.ContainerInspect(ctx, c.id)
+	if err != nil {
+		return err
+	}
+	if info.State == nil || !info.State.Running {
+		return fmt.Errorf("%s is not running", c.name)
+	}
+	tty, openStdin := info.Config.Tty, info.Config.OpenStdin
+
+	resp, err := c.cli.ContainerAttach(ctx, c.id, container.AttachOptions{
+		Stream:     true,
+		Stdin:      openStdin,
+		Stdout:     true,
+		Stderr:     true,
+		DetachKeys: attachDetachKeys,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	detach := "ctrl-p ctrl-q to detach"
+	if !openStdin {
+		detach = "no stdin, ctrl-c to detach"
+	}
+	fmt.Fprintf(c.stdout, "Attached to %s (%s)\r\n", c.name, detach)
+
+	// Raw mode passes keys through as typed, so the daemon sees the detach
+	// keys and ctrl-c reaches the container instead of this process
+	if file, ok := c.stdin.(*os.File); ok && term.IsTerminal(file.Fd()) {
+		state, err := term.MakeRaw(file.Fd())
+		if err != nil {
+			return err
+		}
+		defer term.Restore(file.Fd(), state)
+
+		if tty {
+			if width, height, err := term.GetSize(file.Fd()); err == nil {
+				c.cli.ContainerResize(ctx, c.id, container.ResizeOptions{Width: uint(width), Height: uint(height)})
+			}
+		}
+	}
+
+	output := make(chan error, 1)
+	go func() {
+		var err error
+		if tty {
+			_, err = io.Copy(c.stdout, resp.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(c.stdout, c.stderr, resp.Reader)
+		}
+		output <- err
+	}()
+
+	// The TUI reads stdin again once this returns, so the copy must stop
+	// then rather than keep a read pending
+	reader, err := cancelreader.NewReader(c.stdin)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	defer reader.Cancel()
+	if openStdin {
+		go func() {
+			if _, err := io.Copy(resp.Conn, reader); err == nil {
+				resp.CloseWrite()
+			}
+		}()
+	} else {
+		// Without stdin there is nothing for the daemon to watch for the
+		// detach keys, so ctrl-c detaches here instead
+		go func() {
+			buf := make([]byte, 1)
+			for {
+				if _, err := reader.Read(buf); err != nil || buf[0] == 0x03 {
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	select {
+	case err := <-output:
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+	case <-ctx.Done():
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// changeRow is one line of the changes tree: a changed path, or a directory
+// above changed paths that is only there to group them
+type changeRow struct {
+	name     string // the last path element
+	path     string
+	kind     container.ChangeType
+	depth    int
+	grouping bool // not changed itself
+	parent   bool // has rows below it
+}
+
+// changesView replaces the quick view's log tail with the paths the
+// container added, changed, or deleted since it was created, like docker
+// diff, grouped into a tree
+type changesView struct {
+	changes     []container.FilesystemChange
+	rows        []changeRow
+	cursor      int
+	filtering   bool
+	filterInput textinput.Model
+	err         error
+	loading     bool
+}
+
+type quickChangesMsg struct {
+	gen     int
+	changes []container.FilesystemChange
+	err     error
+}
+
+func newChangesView() *changesView {
+	ti := textinput.New()
+	ti.Placeholder = "path"
+	ti.CharLimit = 200
+	ti.Width = 40
+	return &changesView{filterInput: ti, loading: true}
+}
+
+// loadChanges lists the container's filesystem changes in the background
+func loadChanges(ctx context.Context, cli *client.Client, id string, gen int) tea.Cmd {
+	return func() tea.Msg {
+		changes, err := cli.ContainerDiff(ctx, id)
+		return quickChangesMsg{gen: gen, changes: changes, err: err}
+	}
+}
+
+// setChanges replaces the changes, keeping the cursor on the same path
+func (v *changesView) setChanges(changes []container.FilesystemChange) {
+	v.changes = changes
+	v.applyFilter()
+}
+
+// applyFilter rebuilds the tree from the changes whose path contains the
+// filter, keeping the cursor on the same path when it is still there
+func (v *changesView) applyFilter() {
+	selected := ""
+	if v.cursor < len(v.rows) {
+		selected = v.rows[v.cursor].path
+	}
+
+	needle := strings.ToLower(v.filterInput.Value())
+	var matches []container.FilesystemChange
+	for _, change := range v.changes {
+		if needle == "" || strings.Contains(strings.ToLower(change.Path), needle) {
+			matches = append(matches, change)
+		}
+	}
+	v.rows = changeTree(matches)
+
+	v.cursor = min(v.cursor, max(len(v.rows)-1, 0))
+	for i, row := range v.rows {
+		if row.path == selected {
+			v.cursor = i
+		}
+	}
+}
+
+// changeTree orders changes so each directory comes right before what is
+// in it, adding rows for directories that didn't change themselves
+func changeTree(changes []container.FilesystemChange) []changeRow {
+	// Comparing with the separators lowest keeps /etc/nginx next to /etc,
+	// ahead of /etc-backup
+	key := func(p string) string { return strings.ReplaceAll(p, "/", "\x00") }
+	sorted := append([]container.FilesystemChange(nil), changes...)
+	sort.Slice(sorted, func(i, j int) bool { return key(sorted[i].Path) < key(sorted[j].Path) })
+
+	var rows []changeRow
+	shown := map[string]bool{}
+	for _, change := range sorted {
+		parts := strings.Split(strings.Trim(change.Path, "/"), "/")
+		for depth := range len(parts) - 1 {
+			dir := "/" + strings.Join(parts[:depth+1], "/")
+			if !shown[dir] {
+				shown[dir] = true
+				rows = append(rows, changeRow{name: parts[depth], path: dir, depth: depth, grouping: true})
+			}
+		}
+		shown[change.Path] = true
+		rows = append(rows, changeRow{name: parts[len(parts)-1], path: change.Path, kind: change.Kind, depth: len(parts) - 1})
+	}
+	for i := range rows {
+		rows[i].parent = i+1 < len(rows) && rows[i+1].depth > rows[i].depth
+	}
+	return rows
+}
+
+// update handles a key; it returns true when the view should close, and
+// true for reload when the changes should be listed again
+func (v *changesView) update(msg tea.KeyMsg) (done, reload bool, cmd tea.Cmd) {
+	if v.filtering {
+		switch msg.String() {
+		case "enter":
+			v.filtering = false
+			v.filterInput.Blur()
+			return false, false, nil
+		case "esc":
+			v.filtering = false
+			v.filterInput.Blur()
+			v.filterInput.SetValue("")
+			v.applyFilter()
+			return false, false, nil
+		}
+		v.filterInput, cmd = v.filterInput.Update(msg)
+		v.applyFilter()
+		return false, false, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "c":
+		return true, false, nil
+	case "/":
+		v.filtering = true
+		v.filterInput.Focus()
+		return false, false, textinput.Blink
+	case "R":
+		v.loading = true
+		return false, true, nil
+	case "up", "k":
+		v.cursor = max(0, v.cursor-1)
+	case "down", "j":
+		v.cursor = min(max(len(v.rows)-1, 0), v.cursor+1)
+	case "pgup":
+		v.cursor = max(0, v.cursor-10)
+	case "pgdown":
+		v.cursor = min(max(len(v.rows)-1, 0), v.cursor+10)
+	case "g", "home":
+		v.cursor = 0
+	case "G", "end":
+		v.cursor = max(len(v.rows)-1, 0)
+	}
+	return false, false, nil
+}
+
+// view renders height lines of the changes tree
+func (v *changesView) view(width, height int) string {
+	var lines []string
+
+	added, changed, deleted := 0, 0, 0
+	for _, change := range v.changes {
+		switch change.Kind {
+		case container.ChangeAdd:
+			added++
+		case container.ChangeModify:
+			changed++
+		case container.ChangeDelete:
+			deleted++
+		}
+	}
+	summary := fmt.Sprintf("  %d added, %d changed, %d deleted since the container was created", added, changed, deleted)
+	lines = append(lines, helpStyle.Render(ellipsize(summary, width)))
+
+	switch {
+	case v.filtering:
+		lines = append(lines, searchBarStyle.Render("Filter: ")+v.filterInput.View())
+	case v.filterInput.Value() != "":
+		matched := 0
+		for _, row := range v.rows {
+			if !row.grouping {
+				matched++
+			}
+		}
+		lines = append(lines, helpStyle.Render(ellipsize(fmt.Sprintf("  Filter: %q (%d of %d match)", v.filterInput.Value(), matched, len(v.changes)), width)))
+	}
+
+	switch {
+	case v.err != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, v.err)))
+	case len(v.changes) == 0 && v.loading:
+		lines = append(lines, helpStyle.Render("  Loading changes..."))
+	case len(v.changes) == 0:
+		lines = append(lines, helpStyle.Render("  No changes to the image's filesystem"))
+	case len(v.rows) == 0:
+		lines = append(lines, helpStyle.Render("  No changed paths match the filter"))
+	}
+
+	rows := max(height-len(lines), 1)
+	first := max(0, min(v.cursor-rows/2, len(v.rows)-rows))
+	for i := first; i < min(len(v.rows), first+rows); i++ {
+		lines = append(lines, v.renderRow(v.rows[i], i == v.cursor, width))
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+func (v *changesView) renderRow(row changeRow, selected bool, width int) string {
+	name := row.name
+	if row.parent {
+		name += "/"
+	}
+	indent := strings.Repeat("  ", row.depth)
+	name = ellipsize(name, max(width-6-len(indent), 1))
+
+	prefix := "  "
+	if selected {
+		prefix = cursorStyle.Render(glyphs.cursor) + " "
+	}
+	if row.grouping {
+		return prefix + "   " + indent + helpStyle.Render(name)
+	}
+
+	kindStyle := detachedStyle
+	switch row.kind {
+	case container.ChangeAdd:
+		kindStyle = selectedStyle
+	case container.ChangeDelete:
+		kindStyle = errorStyle
+	}
+	if row.parent {
+		name = dirStyle.Render(name)
+	} else if selected {
+		name = cursorStyle.Render(name)
+	}
+	return prefix + kindStyle.Render(row.kind.String()) + "  " + indent + name
+}
@@ -0,0 +1,119 @@
+package pretty
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// envRow is one environment variable in the quick view
+type envRow struct {
+	name   string
+	value  string
+	secret bool
+}
+
+// envView replaces the quick view's log tail with the container's
+// environment; values that look like secrets stay masked unless revealed
+type envView struct {
+	rows     []envRow
+	cursor   int
+	revealed bool // the selected row's secret is shown until the cursor moves
+}
+
+func newEnvView(env []string) *envView {
+	values := envMap(env)
+	rows := make([]envRow, 0, len(values))
+	for name, value := range values {
+		rows = append(rows, envRow{name: name, value: value, secret: value != "" && isSecretEnv(name)})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].name < rows[j].name })
+	return &envView{rows: rows}
+}
+
+// update handles a key; it returns true when the view should close
+func (v *envView) update(msg tea.KeyMsg) bool {
+	cursor := v.cursor
+	switch msg.String() {
+	case "esc", "E":
+		return true
+	case " ":
+		if v.cursor < len(v.rows) && v.rows[v.cursor].secret {
+			v.revealed = !v.revealed
+		}
+	case "up", "k":
+		v.cursor = max(0, v.cursor-1)
+	case "down", "j":
+		v.cursor = min(max(len(v.rows)-1, 0), v.cursor+1)
+	case "g", "home":
+		v.cursor = 0
+	case "G", "end":
+		v.cursor = max(len(v.rows)-1, 0)
+	}
+	// A revealed secret is hidden again as soon as the cursor leaves it
+	if v.cursor != cursor {
+		v.revealed = false
+	}
+	return false
+}
+
+// view renders height lines of the environment
+func (v *envView) view(width, height int) string {
+	var lines []string
+	masked, nameWidth := 0, 0
+	for _, row := range v.rows {
+		if row.secret {
+			masked++
+		}
+		nameWidth = max(nameWidth, len(row.name))
+	}
+	nameWidth = min(nameWidth, 30)
+
+	summary := fmt.Sprintf("  %d variables", len(v.rows))
+	if masked > 0 {
+		summary += fmt.Sprintf(", %d masked", masked)
+	}
+	lines = append(lines, helpStyle.Render(summary))
+	if len(v.rows) == 0 {
+		lines = append(lines, helpStyle.Render("  No environment variables"))
+	}
+
+	rows := max(height-len(lines), 1)
+	first := max(0, min(v.cursor-rows/2, len(v.rows)-rows))
+	for i := first; i < min(len(v.rows), first+rows); i++ {
+		row := v.rows[i]
+		value := row.value
+		if row.secret && !(i == v.cursor && v.revealed) {
+			value = secretMask
+		}
+		line := fmt.Sprintf("  %-*s  %s", nameWidth, ellipsize(row.name, 30), value)
+		line = ellipsize(line, width)
+		switch {
+		case i == v.cursor:
+			line = cursorStyle.Render(glyphs.cursor + line[1:])
+		case row.secret:
+			line = helpStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+// selected returns the variable under the cursor
+func (v *envView) selected() (envRow, bool) {
+	if v.cursor >= len(v.rows) {
+		return envRow{}, false
+	}
+	return v.rows[v.cursor], true
+}
+
+// selectedSecret reports whether the selected row is a masked value, for
+// the help line
+func (v *envView) selectedSecret() bool {
+	return v.cursor < len(v.rows) && v.rows[v.cursor].secret
+}
@@ -0,0 +1,260 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+)
+
+// minMemory is the smallest memory limit Docker accepts
+const minMemory = 6 * 1024 * 1024
+
+// resourceLimits are the limits the quick view can change on a running
+// container; zero means unlimited (or the default shares)
+type resourceLimits struct {
+	memory      int64
+	reservation int64
+	shares      int64
+	nanoCPUs    int64
+}
+
+func limitsFromHostConfig(hc *container.HostConfig) resourceLimits {
+	if hc == nil {
+		return resourceLimits{}
+	}
+	return resourceLimits{
+		memory:      hc.Memory,
+		reservation: hc.MemoryReservation,
+		shares:      hc.CPUShares,
+		nanoCPUs:    hc.NanoCPUs,
+	}
+}
+
+// Field order in the limits form
+const (
+	limitMemory = iota
+	limitReservation
+	limitShares
+	limitCPUs
+)
+
+// limitsForm edits a container's limits; empty fields keep the current value,
+// since ContainerUpdate treats zero as "unchanged"
+type limitsForm struct {
+	fields  []wizardField
+	focus   int
+	current resourceLimits
+	err     error
+}
+
+func newLimitsForm(current resourceLimits) *limitsForm {
+	text := func(label, placeholder, hint string) wizardField {
+		ti := textinput.New()
+		ti.Placeholder = placeholder
+		ti.CharLimit = 20
+		ti.Width = 12
+		return wizardField{label: label, hint: hint, input: ti}
+	}
+
+	form := &limitsForm{
+		current: current,
+		fields: []wizardField{
+			text("Memory", formatMemoryFlag(current.memory), "hard limit, like 512m or 2g"),
+			text("Reserve", formatMemoryFlag(current.reservation), "soft limit under memory pressure, like 256m"),
+			text("CPU shares", formatSharesFlag(current.shares), "relative weight against other containers, default 1024"),
+			text("CPUs", formatCPUs(current.nanoCPUs), "CPU quota as a number of CPUs, like 1.5"),
+		},
+	}
+	form.fields[0].input.Focus()
+	return form
+}
+
+// update handles a key; it returns true when the form is done, with apply
+// set when the values should be applied
+func (f *limitsForm) update(msg tea.KeyMsg) (done, apply bool, cmd tea.Cmd) {
+	f.err = nil
+	switch msg.String() {
+	case "esc":
+		return true, false, nil
+	case "tab", "down":
+		return false, false, f.moveFocus(1)
+	case "shift+tab", "up":
+		return false, false, f.moveFocus(-1)
+	case "enter", "ctrl+s":
+		if msg.String() == "enter" && f.focus < len(f.fields)-1 {
+			return false, false, f.moveFocus(1)
+		}
+		if _, err := f.limits(); err != nil {
+			f.err = err
+			return false, false, nil
+		}
+		return true, true, nil
+	}
+
+	field := &f.fields[f.focus]
+	field.input, cmd = field.input.Update(msg)
+	return false, false, cmd
+}
+
+func (f *limitsForm) moveFocus(step int) tea.Cmd {
+	f.fields[f.focus].input.Blur()
+	f.focus = (f.focus + step + len(f.fields)) % len(f.fields)
+	return f.fields[f.focus].input.Focus()
+}
+
+// limits validates the form and returns the requested limits, with the
+// current value for any field left empty
+func (f *limitsForm) limits() (resourceLimits, error) {
+	limits := f.current
+
+	if value := f.fields[limitMemory].value(); value != "" {
+		memory, err := units.RAMInBytes(value)
+		if err != nil {
+			return limits, fmt.Errorf("memory: %v", err)
+		}
+		if memory < minMemory {
+			return limits, fmt.Errorf("memory: at least 6m")
+		}
+		limits.memory = memory
+	}
+	if value := f.fields[limitReservation].value(); value != "" {
+		reservation, err := units.RAMInBytes(value)
+		if err != nil {
+			return limits, fmt.Errorf("reserve: %v", err)
+		}
+		if reservation <= 0 {
+			return limits, fmt.Errorf("reserve: must be positive")
+		}
+		limits.reservation = reservation
+	}
+	if limits.memory > 0 && limits.reservation > limits.memory {
+		return limits, fmt.Errorf("reserve: must be less than the memory limit (%s)", formatMemoryFlag(limits.memory))
+	}
+	if value := f.fields[limitShares].value(); value != "" {
+		shares, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || shares < 2 {
+			return limits, fmt.Errorf("CPU shares: a whole number of at least 2")
+		}
+		limits.shares = shares
+	}
+	if value := f.fields[limitCPUs].value(); value != "" {
+		cpus, err := strconv.ParseFloat(value, 64)
+		if err != nil || cpus < 0.01 {
+			return limits, fmt.Errorf("CPUs: a number of at least 0.01, like 1.5")
+		}
+		limits.nanoCPUs = int64(cpus * 1e9)
+	}
+	return limits, nil
+}
+
+func (f *limitsForm) view() string {
+	var sb strings.Builder
+	sb.WriteString(searchBarStyle.Render("Resource limits"))
+	sb.WriteString(helpStyle.Render("  (empty keeps the current value, shown as the placeholder)"))
+	sb.WriteString("\n")
+
+	for i, field := range f.fields {
+		cursor := "  "
+		label := fmt.Sprintf("%-11s", field.label)
+		if i == f.focus {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+			label = cursorStyle.Render(label)
+		}
+		sb.WriteString(cursor + label + " " + field.input.View())
+		if i == f.focus {
+			sb.WriteString(helpStyle.Render("  " + field.hint))
+		}
+		sb.WriteString("\n")
+	}
+	if f.err != nil {
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, f.err)))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// quickLimitsMsg carries the current limits for the form, or the before and
+// after values once an update was applied
+type quickLimitsMsg struct {
+	before  resourceLimits
+	after   resourceLimits
+	applied bool
+	err     error
+}
+
+func loadLimits(ctx context.Context, cli *client.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return quickLimitsMsg{err: err}
+		}
+		return quickLimitsMsg{before: limitsFromHostConfig(info.HostConfig)}
+	}
+}
+
+// applyLimits updates the container and reads back what Docker applied
+func applyLimits(ctx context.Context, cli *client.Client, id string, before, limits resourceLimits) tea.Cmd {
+	return func() tea.Msg {
+		_, err := cli.ContainerUpdate(ctx, id, container.UpdateConfig{Resources: container.Resources{
+			Memory:            limits.memory,
+			MemoryReservation: limits.reservation,
+			CPUShares:         limits.shares,
+			NanoCPUs:          limits.nanoCPUs,
+		}})
+		if err != nil {
+			return quickLimitsMsg{applied: true, err: err}
+		}
+		info, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return quickLimitsMsg{applied: true, err: err}
+		}
+		return quickLimitsMsg{before: before, after: limitsFromHostConfig(info.HostConfig), applied: true}
+	}
+}
+
+// describeLimitChanges lists what changed, like "memory 512m → 1g"
+func describeLimitChanges(before, after resourceLimits) string {
+	var changes []string
+	add := func(name string, from, to int64, format func(int64) string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s %s %s %s", name, format(from), glyphs.next, format(to)))
+		}
+	}
+	add("memory", before.memory, after.memory, formatMemoryFlag)
+	add("reserve", before.reservation, after.reservation, formatMemoryFlag)
+	add("CPU shares", before.shares, after.shares, formatSharesFlag)
+	add("CPUs", before.nanoCPUs, after.nanoCPUs, formatCPUs)
+	if len(changes) == 0 {
+		return "Limits unchanged"
+	}
+	return "Updated " + strings.Join(changes, ", ")
+}
+
+// formatMemoryFlag shows bytes the way docker run's flags take them
+func formatMemoryFlag(bytes int64) string {
+	switch {
+	case bytes <= 0:
+		return "unlimited"
+	case bytes%(1<<30) == 0:
+		return fmt.Sprintf("%dg", bytes>>30)
+	case bytes%(1<<20) == 0:
+		return fmt.Sprintf("%dm", bytes>>20)
+	case bytes%(1<<10) == 0:
+		return fmt.Sprintf("%dk", bytes>>10)
+	}
+	return fmt.Sprint(bytes)
+}
+
+func formatSharesFlag(shares int64) string {
+	if shares == 0 {
+		return "1024"
+	}
+	return fmt.Sprint(shares)
+}
@@ -0,0 +1,291 @@
+package pretty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// processRefresh is how often the processes list is reloaded
+const processRefresh = 2 * time.Second
+
+// listContainerPIDs prints each process inside the container as its PID
+// and command line, which maps docker top's host PIDs to the ones kill
+// takes inside the container
+const listContainerPIDs = `for p in /proc/[0-9]*; do echo "${p#/proc/} $(tr '\0' ' ' < $p/cmdline 2>/dev/null)"; done`
+
+// processRow is one process from docker top
+type processRow struct {
+	pid     string // on the host
+	user    string
+	time    string // CPU time used
+	command string
+}
+
+// processesView replaces the quick view's log tail with the container's
+// processes while it is open
+type processesView struct {
+	rows    []processRow
+	cursor  int
+	signal  string // "TERM" or "KILL" waiting for y to confirm
+	err     error
+	loading bool
+}
+
+type quickProcessesMsg struct {
+	gen  int
+	rows []processRow
+	err  error
+}
+
+type quickProcessesTickMsg struct {
+	gen int
+}
+
+type quickSignalMsg struct {
+	status string
+	err    error
+}
+
+// loadProcesses runs docker top in the background
+func loadProcesses(ctx context.Context, cli *client.Client, id string, gen int) tea.Cmd {
+	return func() tea.Msg {
+		top, err := cli.ContainerTop(ctx, id, nil)
+		if err != nil {
+			return quickProcessesMsg{gen: gen, err: err}
+		}
+		return quickProcessesMsg{gen: gen, rows: parseTop(top)}
+	}
+}
+
+// parseTop picks the columns the view shows out of whatever ps printed;
+// Windows containers and custom ps arguments name them differently
+func parseTop(top container.TopResponse) []processRow {
+	column := func(names ...string) int {
+		for i, title := range top.Titles {
+			for _, name := range names {
+				if strings.EqualFold(title, name) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	pid, user := column("PID"), column("UID", "USER")
+	cpu, command := column("TIME", "CPU"), column("CMD", "COMMAND", "Name")
+
+	value := func(process []string, i int) string {
+		if i < 0 || i >= len(process) {
+			return ""
+		}
+		return process[i]
+	}
+	var rows []processRow
+	for _, process := range top.Processes {
+		rows = append(rows, processRow{
+			pid:     value(process, pid),
+			user:    value(process, user),
+			time:    value(process, cpu),
+			command: value(process, command),
+		})
+	}
+	return rows
+}
+
+// scheduleProcesses reloads the list after the refresh interval
+func scheduleProcesses(gen int) tea.Cmd {
+	return tea.Tick(processRefresh, func(time.Time) tea.Msg {
+		return quickProcessesTickMsg{gen: gen}
+	})
+}
+
+// signalProcess sends signal to a process by running kill inside the
+// container. docker top shows host PIDs, so the process is found again by
+// its command line, taking the same position among processes with that
+// command; both are numbered in start order.
+func signalProcess(ctx context.Context, cli *client.Client, id string, rows []processRow, target processRow, signal string) tea.Cmd {
+	return func() tea.Msg {
+		output, err := execOutput(ctx, cli, id, []string{"sh", "-c", listContainerPIDs})
+		if err != nil {
+			return quickSignalMsg{err: fmt.Errorf("listing processes in the container: %v", err)}
+		}
+
+		// Position of the target among the processes running the same command
+		rank := 0
+		for _, row := range rows {
+			if row.command == target.command && hostPIDLess(row.pid, target.pid) {
+				rank++
+			}
+		}
+
+		var pids []int
+		for _, line := range strings.Split(output, "\n") {
+			pid, command, _ := strings.Cut(line, " ")
+			n, err := strconv.Atoi(pid)
+			if err == nil && strings.TrimSpace(command) == target.command {
+				pids = append(pids, n)
+			}
+		}
+		sort.Ints(pids)
+		if rank >= len(pids) {
+			return quickSignalMsg{err: fmt.Errorf("process %s is gone", target.pid)}
+		}
+
+		pid := strconv.Itoa(pids[rank])
+		if _, err := execOutput(ctx, cli, id, []string{"kill", "-s", signal, pid}); err != nil {
+			return quickSignalMsg{err: fmt.Errorf("kill: %v", err)}
+		}
+		return quickSignalMsg{status: fmt.Sprintf("Sent SIG%s to %s (PID %s in the container)", signal, ellipsize(target.command, 40), pid)}
+	}
+}
+
+func hostPIDLess(a, b string) bool {
+	x, _ := strconv.Atoi(a)
+	y, _ := strconv.Atoi(b)
+	return x < y
+}
+
+// execOutput runs cmd in the container and returns its stdout, failing with
+// its stderr when it exits non-zero
+func execOutput(ctx context.Context, cli *client.Client, id string, cmd []string) (string, error) {
+	capture, err := captureExec(ctx, cli, id, cmd)
+	if err != nil {
+		return "", err
+	}
+	if capture.exitCode != 0 {
+		message := strings.TrimSpace(string(capture.stderr))
+		if message == "" {
+			message = fmt.Sprintf("exit code %d", capture.exitCode)
+		}
+		return "", fmt.Errorf("%s", message)
+	}
+	return string(capture.stdout), nil
+}
+
+// captureExec runs cmd in the container and collects its output and exit
+// code, whatever the code is
+func captureExec(ctx context.Context, cli *client.Client, id string, cmd []string) (execCapture, error) {
+	capture := execCapture{container: id, command: cmd, started: time.Now()}
+	exec, err := cli.ContainerExecCreate(ctx, id, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return capture, err
+	}
+
+	resp, err := cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return capture, err
+	}
+	defer resp.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader); err != nil {
+		return capture, err
+	}
+	capture.duration = time.Since(capture.started)
+	capture.stdout, capture.stderr = stdout.Bytes(), stderr.Bytes()
+
+	inspect, err := cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return capture, err
+	}
+	capture.exitCode = inspect.ExitCode
+	return capture, nil
+}
+
+// update handles a key; it returns true when the view should close, and a
+// signal to send to the selected process once confirmed
+func (v *processesView) update(msg tea.KeyMsg) (done bool, signal string) {
+	if v.signal != "" {
+		signal, v.signal = v.signal, ""
+		if msg.String() == "y" {
+			return false, signal
+		}
+		return false, ""
+	}
+
+	switch msg.String() {
+	case "esc", "t":
+		return true, ""
+	case "up", "k":
+		v.cursor = max(0, v.cursor-1)
+	case "down", "j":
+		v.cursor = min(len(v.rows)-1, v.cursor+1)
+	case "x":
+		if len(v.rows) > 0 {
+			v.signal = "TERM"
+		}
+	case "X":
+		if len(v.rows) > 0 {
+			v.signal = "KILL"
+		}
+	}
+	return false, ""
+}
+
+// setRows replaces the list, keeping the cursor on the same process
+func (v *processesView) setRows(rows []processRow) {
+	selected := ""
+	if v.cursor < len(v.rows) {
+		selected = v.rows[v.cursor].pid
+	}
+	v.rows = rows
+	v.cursor = min(v.cursor, max(len(rows)-1, 0))
+	for i, row := range rows {
+		if row.pid == selected {
+			v.cursor = i
+		}
+	}
+}
+
+// selected returns the process under the cursor
+func (v *processesView) selected() (processRow, bool) {
+	if v.cursor >= len(v.rows) {
+		return processRow{}, false
+	}
+	return v.rows[v.cursor], true
+}
+
+// view renders height lines of the process table
+func (v *processesView) view(width, height int) string {
+	var lines []string
+	header := fmt.Sprintf("  %-8s %-10s %-10s %s", "PID", "USER", "TIME", "COMMAND")
+	lines = append(lines, helpStyle.Render(ellipsize(header, width)))
+
+	switch {
+	case v.err != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, v.err)))
+	case len(v.rows) == 0 && v.loading:
+		lines = append(lines, helpStyle.Render("  Loading processes..."))
+	case len(v.rows) == 0:
+		lines = append(lines, helpStyle.Render("  No processes (the container isn't running)"))
+	}
+
+	rows := max(height-len(lines), 1)
+	first := max(0, min(v.cursor-rows/2, len(v.rows)-rows))
+	for i := first; i < min(len(v.rows), first+rows); i++ {
+		row := v.rows[i]
+		line := fmt.Sprintf("  %-8s %-10s %-10s %s", row.pid, ellipsize(row.user, 10), row.time, row.command)
+		line = ellipsize(line, width)
+		if i == v.cursor {
+			line = cursorStyle.Render(glyphs.cursor + line[1:])
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:height], "\n")
+}
@@ -0,0 +1,179 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/client"
+)
+
+// runView replaces the quick view's log tail with the output of a one-shot
+// command run in the container, which w saves with its metadata header like
+// dockit exec --output, to attach to a bug report
+type runView struct {
+	prompt  *textinput.Model // asks for the command, or the file when saving
+	saving  bool
+	command string // as typed; it runs through sh -c
+	running bool
+	ran     bool // capture holds a finished run
+	capture execCapture
+	err     error
+	scroll  int // output lines scrolled back from the end
+}
+
+type quickRunMsg struct {
+	capture execCapture
+	err     error
+}
+
+func newRunView() *runView {
+	v := &runView{}
+	v.ask("Run: ", "")
+	return v
+}
+
+// ask opens the prompt, starting from value
+func (v *runView) ask(prompt, value string) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.CharLimit = 500
+	input.Width = 60
+	if prompt == "Run: " {
+		input.Placeholder = "ps aux, env, cat /etc/hosts..."
+	}
+	input.SetValue(value)
+	input.Focus()
+	v.prompt = &input
+	return textinput.Blink
+}
+
+// runInContainer runs command through the container's shell in the
+// background
+func runInContainer(ctx context.Context, cli *client.Client, id, name, command string) tea.Cmd {
+	return func() tea.Msg {
+		capture, err := captureExec(ctx, cli, id, []string{"sh", "-c", command})
+		capture.container = name
+		return quickRunMsg{capture: capture, err: err}
+	}
+}
+
+// updateRun handles a key while the run panel is open: the prompt's, or
+// scrolling, running another command, saving, and closing
+func (m *quickModel) updateRun(msg tea.KeyMsg) tea.Cmd {
+	v := m.run
+	if v.prompt != nil {
+		switch msg.String() {
+		case "esc":
+			v.prompt, v.saving = nil, false
+			if !v.ran && !v.running && v.err == nil {
+				m.run = nil
+			}
+			return nil
+		case "enter":
+			value := strings.TrimSpace(v.prompt.Value())
+			if value == "" {
+				return nil
+			}
+			v.prompt = nil
+			if v.saving {
+				v.saving = false
+				if err := os.WriteFile(value, v.capture.report(), 0644); err != nil {
+					m.err = err
+					return nil
+				}
+				m.err = nil
+				m.status = "Saved the output to " + value
+				return nil
+			}
+			v.command = value
+			v.running, v.ran, v.err, v.scroll = true, false, nil, 0
+			m.err = nil
+			m.status = ""
+			return runInContainer(m.ctx, m.cli, m.id, m.name, value)
+		}
+		var cmd tea.Cmd
+		*v.prompt, cmd = v.prompt.Update(msg)
+		return cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.run = nil
+	case "up", "k":
+		v.scroll = min(v.scroll+1, max(len(v.outputLines())-1, 0))
+	case "down", "j":
+		v.scroll = max(v.scroll-1, 0)
+	case "x":
+		if !v.running {
+			return v.ask("Run: ", "")
+		}
+	case "w":
+		if v.ran {
+			v.saving = true
+			path := fmt.Sprintf("%s-exec-%s.txt", m.name, v.capture.started.Format("20060102-150405"))
+			return v.ask("Save to: ", path)
+		}
+	}
+	return nil
+}
+
+// outputLines is the capture's stdout then its stderr, line by line
+func (v *runView) outputLines() []quickLogLine {
+	var lines []quickLogLine
+	add := func(output []byte, level int) {
+		text := strings.TrimRight(string(output), "\n")
+		if text == "" {
+			return
+		}
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, quickLogLine{text: strings.ReplaceAll(line, "\t", "    "), level: level})
+		}
+	}
+	add(v.capture.stdout, levelNone)
+	add(v.capture.stderr, levelError)
+	return lines
+}
+
+// view renders height lines of the command's output, scrolled back from the
+// end
+func (v *runView) view(width, height int) string {
+	var lines []string
+	switch {
+	case v.running:
+		lines = append(lines, helpStyle.Render("  Running..."))
+	case v.err != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  %s %v", glyphs.failed, v.err)))
+	case !v.ran:
+		lines = append(lines, helpStyle.Render("  Type a command to run in the container"))
+	default:
+		header := fmt.Sprintf("  $ %s  (exit code %d, %s)", v.command, v.capture.exitCode, v.capture.duration.Round(time.Millisecond))
+		style := helpStyle
+		if v.capture.exitCode != 0 {
+			style = errorStyle
+		}
+		lines = append(lines, style.Render(ellipsize(header, width)))
+
+		output := v.outputLines()
+		if len(output) == 0 {
+			lines = append(lines, helpStyle.Render("  No output"))
+		}
+		rows := max(height-len(lines), 1)
+		end := len(output) - v.scroll
+		for _, line := range output[max(end-rows, 0):end] {
+			text := ellipsize(line.text, width)
+			if style, ok := lineLevelStyles[line.level]; ok {
+				text = style.Render(text)
+			}
+			lines = append(lines, text)
+		}
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:height], "\n")
+}
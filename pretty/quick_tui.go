@@ -0,0 +1,872 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+const (
+	quickHistory  = 120  // CPU and memory samples the sparklines keep, about two minutes
+	quickMaxLines = 1000 // log lines kept for the tail
+)
+
+// quickModel is a one-screen view of a single container: stats on top, the
+// log tail below, and keys for the common actions. dockit watch opens it
+// read-only with the health and restarts on top instead.
+type quickModel struct {
+	cli         *client.Client
+	ctx         context.Context
+	id          string
+	name        string
+	image       string
+	env         []string
+	state       string
+	cpu         []float64
+	memUsed     uint64
+	memLimit    uint64
+	mem         []float64 // memory in percent of the limit, or bytes without one
+	lines       []quickLogLine
+	minLevel    int // hide log lines less severe than this; levelNone shows everything
+	stats       *statsStream
+	logs        *logStream
+	gen         int // bumped whenever the streams are reopened
+	cancel      context.CancelFunc
+	streamEnded time.Time // when the log stream last ended; reopening resumes here
+	streamErr   errorBanner
+	limits      *limitsForm      // open while editing resource limits
+	procs       *processesView   // open while showing processes instead of logs
+	procsGen    int              // bumped when the processes view opens or closes
+	changes     *changesView     // open while showing filesystem changes instead of logs
+	changesGen  int              // bumped when the changes view opens or closes
+	envPanel    *envView         // open while showing the environment instead of logs
+	run         *runView         // open while showing a command's output instead of logs
+	stopTimeout *int             // from the config, unless the container sets its own
+	stopPrompt  *textinput.Model // open while asking S for a stop timeout
+	watch       bool             // opened by dockit watch: no actions, and the status on top
+	watched     *watchStatus     // the last inspect while watching
+	busy        bool
+	status      string
+	err         error
+	width       int
+	height      int
+}
+
+// quickLogLine is a line of the log tail with its detected level
+type quickLogLine struct {
+	text  string
+	level int
+}
+
+// quickOpenedMsg carries the streams opened for generation gen
+type quickOpenedMsg struct {
+	gen   int
+	state string
+	stats *statsStream
+	logs  *logStream
+	err   error
+}
+
+type quickStatsMsg struct {
+	gen    int
+	sample container.StatsResponse
+}
+
+type quickLogMsg struct {
+	gen  int
+	line string
+}
+
+// quickStreamEndMsg reports the log stream ending, because the container
+// stopped or the connection failed
+type quickStreamEndMsg struct {
+	gen int
+	err error
+}
+
+type quickRetryMsg struct {
+	gen int
+}
+
+// quickStateMsg carries the container's state after an inspect
+type quickStateMsg struct {
+	state string
+	err   error
+}
+
+type quickActionMsg struct {
+	verb string
+	err  error
+}
+
+func (m quickModel) Init() tea.Cmd {
+	// Streams open from Update so the model keeps their generation
+	gen := m.gen
+	open := func() tea.Msg { return quickRetryMsg{gen: gen} }
+	if m.watch {
+		return tea.Batch(open, inspectWatched(m.ctx, m.cli, m.id))
+	}
+	return open
+}
+
+// openStreams inspects the container and opens its log and stats streams;
+// the first open shows recent lines, later ones resume where the last ended
+func (m *quickModel) openStreams() tea.Cmd {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.gen++
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancel = cancel
+
+	cli, id, gen, since := m.cli, m.id, m.gen, m.streamEnded
+	return func() tea.Msg {
+		info, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return quickOpenedMsg{gen: gen, err: err}
+		}
+
+		options := container.LogsOptions{ShowStdout: true, ShowStderr: true, Follow: true}
+		if since.IsZero() {
+			options.Tail = "200"
+			if rule, ok := logRuleFor(info.Config.Labels); ok && rule.Tail != "" {
+				options.Tail = rule.Tail
+			}
+		} else {
+			options.Since = since.Format(time.RFC3339Nano)
+		}
+		reader, err := cli.ContainerLogs(ctx, id, options)
+		if err != nil {
+			return quickOpenedMsg{gen: gen, err: err}
+		}
+		msg := quickOpenedMsg{gen: gen, state: info.State.Status, logs: startLogStream(ctx, []logReader{{reader, info.Config.Tty}}, true)}
+
+		// Stopped containers have no stats to stream
+		if info.State.Running {
+			msg.stats, msg.err = startStatsStream(ctx, cli, id)
+		}
+		return msg
+	}
+}
+
+func waitForQuickStats(stream *statsStream, gen int) tea.Cmd {
+	return func() tea.Msg {
+		sample, ok := <-stream.samples
+		if !ok {
+			// The log stream reports why things ended
+			return nil
+		}
+		return quickStatsMsg{gen: gen, sample: sample}
+	}
+}
+
+func waitForQuickLog(stream *logStream, gen int) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-stream.lines
+		if !ok {
+			var err error
+			select {
+			case err = <-stream.errs:
+			default:
+			}
+			return quickStreamEndMsg{gen: gen, err: err}
+		}
+		return quickLogMsg{gen: gen, line: lineText(line)}
+	}
+}
+
+func (m quickModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case quickOpenedMsg:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.streamErr.record(msg.err, time.Now())
+			return m, m.retry()
+		}
+		m.streamErr.clear()
+		m.state = msg.state
+		m.logs = msg.logs
+		cmds := []tea.Cmd{waitForQuickLog(m.logs, m.gen)}
+		if msg.stats != nil {
+			m.stats = msg.stats
+			cmds = append(cmds, waitForQuickStats(m.stats, m.gen))
+		} else {
+			m.stats = nil
+			m.cpu, m.mem = nil, nil
+			m.memUsed, m.memLimit = 0, 0
+		}
+		return m, tea.Batch(cmds...)
+
+	case quickStatsMsg:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+		m.cpu = append(m.cpu, cpuPercent(msg.sample))
+		if len(m.cpu) > quickHistory {
+			m.cpu = m.cpu[len(m.cpu)-quickHistory:]
+		}
+		m.memUsed, m.memLimit = memoryUsage(msg.sample)
+		mem := float64(m.memUsed)
+		if m.memLimit > 0 {
+			mem = mem / float64(m.memLimit) * 100
+		}
+		m.mem = append(m.mem, mem)
+		if len(m.mem) > quickHistory {
+			m.mem = m.mem[len(m.mem)-quickHistory:]
+		}
+		return m, waitForQuickStats(m.stats, m.gen)
+
+	case quickLogMsg:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+		m.lines = append(m.lines, quickLogLine{text: msg.line, level: detectLevel(msg.line)})
+		if len(m.lines) > quickMaxLines {
+			m.lines = m.lines[len(m.lines)-quickMaxLines:]
+		}
+		return m, waitForQuickLog(m.logs, m.gen)
+
+	case quickStreamEndMsg:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+		m.streamEnded = time.Now()
+		if msg.err != nil {
+			m.streamErr.record(msg.err, time.Now())
+			return m, m.retry()
+		}
+		// A clean end means the container stopped; pick up its new state
+		m.stats = nil
+		return m, m.refreshState()
+
+	case quickRetryMsg:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+		return m, m.openStreams()
+
+	case quickActionMsg:
+		m.busy = false
+		if msg.err != nil {
+			m.err = msg.err
+			m.status = ""
+			return m, m.refreshState()
+		}
+		m.status = msg.verb
+		m.streamEnded = time.Now()
+		return m, m.openStreams()
+
+	case quickStateMsg:
+		if msg.err == nil {
+			m.state = msg.state
+		}
+		return m, nil
+
+	case watchTickMsg:
+		return m, inspectWatched(m.ctx, m.cli, m.id)
+
+	case watchInspectedMsg:
+		m.err = msg.err
+		if msg.err != nil {
+			return m, scheduleWatchInspect()
+		}
+		m.watched = &msg.status
+		m.state = msg.status.state
+		// The streams ended when the container stopped; nothing else
+		// restarts them, so pick them up again once it is back
+		if m.state == "running" && m.stats == nil && !m.streamEnded.IsZero() && !m.streamErr.active() {
+			return m, tea.Batch(m.openStreams(), scheduleWatchInspect())
+		}
+		return m, scheduleWatchInspect()
+
+	case quickLimitsMsg:
+		m.busy = false
+		m.status = ""
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if !msg.applied {
+			m.limits = newLimitsForm(msg.before)
+			return m, textinput.Blink
+		}
+		m.status = describeLimitChanges(msg.before, msg.after)
+		return m, nil
+
+	case quickProcessesMsg:
+		if m.procs == nil || msg.gen != m.procsGen {
+			return m, nil
+		}
+		m.procs.loading = false
+		m.procs.err = msg.err
+		if msg.err == nil {
+			m.procs.setRows(msg.rows)
+		}
+		return m, scheduleProcesses(msg.gen)
+
+	case quickProcessesTickMsg:
+		if m.procs == nil || msg.gen != m.procsGen {
+			return m, nil
+		}
+		return m, loadProcesses(m.ctx, m.cli, m.id, m.procsGen)
+
+	case quickChangesMsg:
+		if m.changes == nil || msg.gen != m.changesGen {
+			return m, nil
+		}
+		m.changes.loading = false
+		m.changes.err = msg.err
+		if msg.err == nil {
+			m.changes.setChanges(msg.changes)
+		}
+		return m, nil
+
+	case quickSignalMsg:
+		m.busy = false
+		m.status = msg.status
+		m.err = msg.err
+		return m, nil
+
+	case quickRunMsg:
+		if m.run == nil {
+			return m, nil
+		}
+		m.run.running = false
+		m.run.capture, m.run.err = msg.capture, msg.err
+		m.run.ran = msg.err == nil
+		return m, nil
+
+	case externalDoneMsg:
+		m.err = msg.err
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.limits != nil && msg.String() != "ctrl+c" {
+			done, apply, cmd := m.limits.update(msg)
+			if !done {
+				return m, cmd
+			}
+			form := m.limits
+			m.limits = nil
+			if !apply {
+				return m, nil
+			}
+			limits, _ := form.limits()
+			m.busy = true
+			m.status = "Updating limits..."
+			return m, applyLimits(m.ctx, m.cli, m.id, form.current, limits)
+		}
+
+		if m.stopPrompt != nil && msg.String() != "ctrl+c" {
+			return m, m.updateStopPrompt(msg)
+		}
+
+		if m.procs != nil && msg.String() != "q" && msg.String() != "ctrl+c" {
+			done, signal := m.procs.update(msg)
+			if done {
+				m.procs = nil
+				m.procsGen++
+				return m, nil
+			}
+			row, ok := m.procs.selected()
+			if signal == "" || !ok || m.busy {
+				return m, nil
+			}
+			m.busy = true
+			m.err = nil
+			m.status = fmt.Sprintf("Sending SIG%s to %s...", signal, row.pid)
+			return m, signalProcess(m.ctx, m.cli, m.id, m.procs.rows, row, signal)
+		}
+
+		if m.changes != nil && !m.changes.filtering && msg.String() == "y" {
+			if m.changes.cursor < len(m.changes.rows) {
+				path := m.changes.rows[m.changes.cursor].path
+				m.status = copiedNotice(path)
+				return m, copyToClipboard(path)
+			}
+			return m, nil
+		}
+		if m.changes != nil && msg.String() != "ctrl+c" && (m.changes.filtering || msg.String() != "q") {
+			done, reload, cmd := m.changes.update(msg)
+			if done {
+				m.changes = nil
+				m.changesGen++
+				return m, nil
+			}
+			if reload {
+				m.changesGen++
+				return m, loadChanges(m.ctx, m.cli, m.id, m.changesGen)
+			}
+			return m, cmd
+		}
+
+		if m.run != nil && msg.String() != "ctrl+c" && (m.run.prompt != nil || msg.String() != "q") {
+			return m, m.updateRun(msg)
+		}
+
+		if m.envPanel != nil && msg.String() == "y" {
+			row, ok := m.envPanel.selected()
+			if !ok {
+				return m, nil
+			}
+			// The status line never shows a masked value
+			shown := row.value
+			if row.secret {
+				shown = secretMask
+			}
+			m.status = copiedNotice(row.name + "=" + shown)
+			return m, copyToClipboard(row.name + "=" + row.value)
+		}
+		if m.envPanel != nil && msg.String() != "q" && msg.String() != "ctrl+c" {
+			if m.envPanel.update(msg) {
+				m.envPanel = nil
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, tea.Quit
+		}
+		if m.busy {
+			return m, nil
+		}
+		if m.watch && msg.String() != "L" && msg.String() != "y" {
+			return m, nil
+		}
+
+		m.err = nil
+		switch msg.String() {
+		case "r":
+			m.busy = true
+			m.status = "Restarting..."
+			return m, m.action("Restarted", func(ctx context.Context) error {
+				return m.cli.ContainerRestart(ctx, m.id, container.StopOptions{Timeout: m.stopTimeout})
+			})
+		case "s":
+			m.busy = true
+			// Docker stops paused containers too
+			if m.state == "running" || m.state == "paused" {
+				return m, m.stop(m.stopTimeout)
+			}
+			m.status = "Starting..."
+			return m, m.action("Started", func(ctx context.Context) error {
+				return m.cli.ContainerStart(ctx, m.id, container.StartOptions{})
+			})
+		case "S":
+			if m.state != "running" && m.state != "paused" {
+				m.err = fmt.Errorf("%s is not running", m.name)
+				return m, nil
+			}
+			input := textinput.New()
+			input.Prompt = "Stop timeout: "
+			input.Placeholder = "30s, 2m, or seconds"
+			input.CharLimit = 16
+			input.Width = 20
+			if m.stopTimeout != nil {
+				input.SetValue(fmt.Sprintf("%ds", *m.stopTimeout))
+			}
+			input.Focus()
+			m.stopPrompt = &input
+			return m, textinput.Blink
+		case "p":
+			if m.state != "running" && m.state != "paused" {
+				m.err = fmt.Errorf("%s is not running", m.name)
+				return m, nil
+			}
+			m.busy = true
+			if m.state == "paused" {
+				m.status = "Unpausing..."
+				return m, m.action("Unpaused", func(ctx context.Context) error {
+					return m.cli.ContainerUnpause(ctx, m.id)
+				})
+			}
+			m.status = "Pausing..."
+			return m, m.action("Paused", func(ctx context.Context) error {
+				return m.cli.ContainerPause(ctx, m.id)
+			})
+		case "t":
+			if m.state != "running" {
+				m.err = fmt.Errorf("%s is not running", m.name)
+				return m, nil
+			}
+			m.procs = &processesView{loading: true}
+			m.procsGen++
+			return m, loadProcesses(m.ctx, m.cli, m.id, m.procsGen)
+		case "L":
+			m.minLevel = nextLevelFilter(m.minLevel)
+			m.status = "Showing all log lines"
+			if m.minLevel != levelNone {
+				m.status = fmt.Sprintf("Showing %s+ log lines", levelNames[m.minLevel])
+			}
+			return m, nil
+		case "y":
+			m.status = copiedNotice(m.id)
+			return m, copyToClipboard(m.id)
+		case "E":
+			m.envPanel = newEnvView(m.env)
+			return m, nil
+		case "c":
+			m.changes = newChangesView()
+			m.changesGen++
+			return m, loadChanges(m.ctx, m.cli, m.id, m.changesGen)
+		case "l":
+			m.busy = true
+			m.status = "Loading limits..."
+			return m, loadLimits(m.ctx, m.cli, m.id)
+		case "e":
+			if m.state != "running" {
+				m.err = fmt.Errorf("%s is not running", m.name)
+				return m, nil
+			}
+			return m, tea.ExecProcess(DockerCommand("exec", "-it", m.id, "sh"), func(err error) tea.Msg {
+				return externalDoneMsg{err: err}
+			})
+		case "A":
+			if m.state != "running" {
+				m.err = fmt.Errorf("%s is not running", m.name)
+				return m, nil
+			}
+			return m, attachContainer(m.cli, m.id, m.name)
+		case "x":
+			if m.state != "running" {
+				m.err = fmt.Errorf("%s is not running", m.name)
+				return m, nil
+			}
+			m.run = newRunView()
+			return m, textinput.Blink
+		}
+	}
+
+	return m, nil
+}
+
+// stop stops the container, waiting timeout seconds before it is killed, or
+// the container's own stop timeout when nil
+func (m *quickModel) stop(timeout *int) tea.Cmd {
+	m.busy = true
+	m.status = "Stopping..."
+	if timeout != nil {
+		m.status = fmt.Sprintf("Stopping, killing after %ds...", *timeout)
+	}
+	return m.action("Stopped", func(ctx context.Context) error {
+		return m.cli.ContainerStop(ctx, m.id, container.StopOptions{Timeout: timeout})
+	})
+}
+
+// updateStopPrompt reads the timeout for S; enter stops with it
+func (m *quickModel) updateStopPrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.stopPrompt = nil
+		return nil
+	case "enter":
+		seconds, err := parseStopTimeout(m.stopPrompt.Value())
+		if err != nil {
+			m.err = err
+			return nil
+		}
+		m.stopPrompt = nil
+		m.err = nil
+		return m.stop(&seconds)
+	}
+	var cmd tea.Cmd
+	*m.stopPrompt, cmd = m.stopPrompt.Update(msg)
+	return cmd
+}
+
+// retry reopens the streams after a failure, backing off while it repeats
+func (m *quickModel) retry() tea.Cmd {
+	gen := m.gen
+	return tea.Tick(m.streamErr.retryDelay(), func(time.Time) tea.Msg {
+		return quickRetryMsg{gen: gen}
+	})
+}
+
+func (m *quickModel) refreshState() tea.Cmd {
+	cli, ctx, id := m.cli, m.ctx, m.id
+	return func() tea.Msg {
+		info, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return quickStateMsg{err: err}
+		}
+		return quickStateMsg{state: info.State.Status}
+	}
+}
+
+// action runs a lifecycle call in the background and reports it with verb
+func (m *quickModel) action(verb string, run func(context.Context) error) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		return quickActionMsg{verb: verb, err: run(ctx)}
+	}
+}
+
+func (m quickModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if small := tooSmallView(m.width, m.height, 40, 10); small != "" {
+		return small
+	}
+
+	var sb strings.Builder
+
+	state := m.state
+	switch state {
+	case "":
+		state = "connecting"
+	case "paused":
+		state = glyphs.paused + " paused"
+	}
+	title := "QUICK"
+	if m.watch {
+		title = "WATCH"
+	}
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("%s: %s (%s) [%s]", title, m.name, m.image, state)))
+	sb.WriteString("\n")
+	if m.watch {
+		sb.WriteString(renderWatchStatus(m.watched, m.width))
+		sb.WriteString("\n")
+	}
+
+	// Stats: a CPU sparkline and a memory bar, each with its figure
+	width := max(m.width-24, 10)
+	if m.stats == nil {
+		sb.WriteString(helpStyle.Render("  No stats while the container isn't running"))
+		sb.WriteString("\n\n")
+	} else {
+		current := 0.0
+		if len(m.cpu) > 0 {
+			current = m.cpu[len(m.cpu)-1]
+		}
+		sb.WriteString("  CPU " + progressFillStyle.Render(renderSparkline(m.cpu, width, 100)))
+		sb.WriteString(fmt.Sprintf(" %6.1f%%\n", current))
+
+		if m.watch {
+			// Watching is about trends, so memory gets a sparkline too
+			ceiling, figure := 100.0, formatSize(int64(m.memUsed))
+			if m.memLimit > 0 {
+				figure += " / " + formatSize(int64(m.memLimit))
+			} else {
+				ceiling = 0 // scale to the highest sample
+			}
+			sb.WriteString("  MEM " + progressFillStyle.Render(renderSparkline(m.mem, width, ceiling)))
+			sb.WriteString(" " + figure + "\n")
+		} else if m.memLimit > 0 {
+			fraction := float64(m.memUsed) / float64(m.memLimit)
+			sb.WriteString("  MEM " + renderProgressBar(fraction, width, false))
+			sb.WriteString(fmt.Sprintf(" %s / %s\n", formatSize(int64(m.memUsed)), formatSize(int64(m.memLimit))))
+		} else {
+			// Without a limit there is nothing to fill the bar against
+			sb.WriteString("  MEM " + helpStyle.Render(fmt.Sprintf("%-*s", width, "no limit")))
+			sb.WriteString(fmt.Sprintf(" %s\n", formatSize(int64(m.memUsed))))
+		}
+	}
+	sb.WriteString(helpStyle.Render(strings.Repeat(glyphs.rule, m.width)))
+	sb.WriteString("\n")
+
+	// Logs, the limits form, the processes, the changes, the environment, or
+	// a command's output fill what's left above the status and help lines
+	logHeight := m.height - 7
+	if m.watch {
+		logHeight--
+	}
+	logHeight = max(logHeight, 1)
+	if m.run != nil {
+		sb.WriteString(m.run.view(m.width, logHeight))
+		sb.WriteString("\n")
+	} else if m.envPanel != nil {
+		sb.WriteString(m.envPanel.view(m.width, logHeight))
+		sb.WriteString("\n")
+	} else if m.changes != nil {
+		sb.WriteString(m.changes.view(m.width, logHeight))
+		sb.WriteString("\n")
+	} else if m.procs != nil {
+		sb.WriteString(m.procs.view(m.width, logHeight))
+		sb.WriteString("\n")
+	} else if m.limits != nil {
+		form := strings.TrimSuffix(m.limits.view(), "\n")
+		sb.WriteString(form)
+		sb.WriteString("\n")
+		for i := lipgloss.Height(form); i < logHeight; i++ {
+			sb.WriteString("\n")
+		}
+	} else {
+		lines := m.shownLines()
+		start := max(len(lines)-logHeight, 0)
+		for _, line := range lines[start:] {
+			text := ellipsize(line.text, m.width)
+			if style, ok := lineLevelStyles[line.level]; ok {
+				text = style.Render(text)
+			}
+			sb.WriteString(text)
+			sb.WriteString("\n")
+		}
+		for i := len(lines) - start; i < logHeight; i++ {
+			sb.WriteString("\n")
+		}
+	}
+
+	switch {
+	case m.stopPrompt != nil:
+		sb.WriteString(m.stopPrompt.View())
+		if m.err != nil {
+			sb.WriteString(errorStyle.Render(fmt.Sprintf("  %s %v", glyphs.failed, m.err)))
+		}
+	case m.run != nil && m.run.prompt != nil:
+		sb.WriteString(m.run.prompt.View())
+		if m.err != nil {
+			sb.WriteString(errorStyle.Render(fmt.Sprintf("  %s %v", glyphs.failed, m.err)))
+		}
+	case m.procs != nil && m.procs.signal != "":
+		row, _ := m.procs.selected()
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("Send SIG%s to PID %s (%s)? y to confirm", m.procs.signal, row.pid, ellipsize(row.command, 40))))
+	case m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+	case m.streamErr.active():
+		sb.WriteString(errorStyle.Render(m.streamErr.String()))
+	case m.status != "":
+		sb.WriteString(statusBarStyle.Render(m.status))
+	}
+	sb.WriteString("\n")
+
+	if m.limits != nil {
+		sb.WriteString(helpStyle.Render("tab: next field | enter: next/apply | ctrl+s: apply | esc: cancel"))
+		return sb.String()
+	}
+	if m.stopPrompt != nil {
+		sb.WriteString(helpStyle.Render("enter: stop | esc: cancel"))
+		return sb.String()
+	}
+	if m.run != nil {
+		switch {
+		case m.run.prompt != nil && m.run.saving:
+			sb.WriteString(helpStyle.Render("enter: save | esc: cancel"))
+		case m.run.prompt != nil:
+			sb.WriteString(helpStyle.Render("enter: run | esc: cancel"))
+		case m.run.ran:
+			sb.WriteString(helpStyle.Render(glyphs.arrows + ": scroll | x: run another | w: save to file | esc: back to logs | q: quit"))
+		default:
+			sb.WriteString(helpStyle.Render("x: run another | esc: back to logs | q: quit"))
+		}
+		return sb.String()
+	}
+	if m.envPanel != nil {
+		reveal := ""
+		if m.envPanel.selectedSecret() {
+			reveal = "space: show value | "
+			if m.envPanel.revealed {
+				reveal = "space: hide value | "
+			}
+		}
+		sb.WriteString(helpStyle.Render(glyphs.arrows + ": select | " + reveal + "y: copy | E/esc: back to logs | q: quit"))
+		return sb.String()
+	}
+	if m.changes != nil {
+		if m.changes.filtering {
+			sb.WriteString(helpStyle.Render("enter: keep filter | esc: clear filter"))
+		} else {
+			sb.WriteString(helpStyle.Render(glyphs.arrows + ": scroll | /: filter | y: copy path | R: reload | c/esc: back to logs | q: quit"))
+		}
+		return sb.String()
+	}
+	if m.procs != nil {
+		sb.WriteString(helpStyle.Render(glyphs.arrows + ": select | x: SIGTERM | X: SIGKILL | t/esc: back to logs | q: quit"))
+		return sb.String()
+	}
+
+	level := "L: level"
+	if m.minLevel != levelNone {
+		level += " (" + levelNames[m.minLevel] + "+)"
+	}
+	if m.watch {
+		sb.WriteString(helpStyle.Render(level + " | y: copy ID | q: quit"))
+		return sb.String()
+	}
+
+	startStop, pause := "s: stop | S: stop with timeout", "p: pause"
+	switch m.state {
+	case "running":
+	case "paused":
+		pause = "p: unpause"
+	default:
+		startStop = "s: start"
+	}
+	sb.WriteString(helpStyle.Render("r: restart | " + startStop + " | " + pause + " | l: limits | " + level + " | t: processes | c: changes | E: env | y: copy ID | x: run | e: exec sh | A: attach | q: quit"))
+
+	return sb.String()
+}
+
+// shownLines returns the log lines the level filter lets through; lines
+// with no level are hidden while it is on
+func (m quickModel) shownLines() []quickLogLine {
+	if m.minLevel == levelNone {
+		return m.lines
+	}
+	var shown []quickLogLine
+	for _, line := range m.lines {
+		if line.level != levelNone && line.level <= m.minLevel {
+			shown = append(shown, line)
+		}
+	}
+	return shown
+}
+
+// LaunchQuickTUI opens the single-container view
+func LaunchQuickTUI(containerID string) error {
+	return launchQuick(containerID, false)
+}
+
+// launchQuick opens the single-container view, read-only for dockit watch
+func launchQuick(containerID string, watch bool) error {
+	cli, err := newClient()
+	if err != nil {
+		return fmt.Errorf("error creating Docker client: %v", err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("error inspecting container: %v", err)
+	}
+
+	model := quickModel{
+		cli:   cli,
+		ctx:   ctx,
+		id:    info.ID,
+		name:  strings.TrimPrefix(info.Name, "/"),
+		image: info.Config.Image,
+		env:   info.Config.Env,
+
+		stopTimeout: stopTimeoutFor(info.Config),
+		watch:       watch,
+	}
+
+	p := newProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	updateState(rememberView)
+	return nil
+}
@@ -0,0 +1,301 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// Compose labels used to group containers by project and service
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// containerRecord is dockit's enriched view of a container, used for
+// machine-readable output and queries
+type containerRecord struct {
+	ID             string            `json:"ID"`
+	Name           string            `json:"Name"`
+	Names          []string          `json:"Names"`
+	Image          string            `json:"Image"`
+	ImageID        string            `json:"ImageID"`
+	Command        string            `json:"Command"`
+	Created        int64             `json:"Created"`
+	State          string            `json:"State"`
+	Status         string            `json:"Status"`
+	StartedAt      string            `json:"StartedAt"`  // RFC 3339, empty if it never started
+	FinishedAt     string            `json:"FinishedAt"` // RFC 3339, empty if it never stopped
+	ExitCode       int               `json:"ExitCode"`
+	RestartCount   int               `json:"RestartCount"`
+	Health         string            `json:"Health"`
+	Ports          []string          `json:"Ports"`
+	Networks       []string          `json:"Networks"`
+	Mounts         []string          `json:"Mounts"`
+	Labels         map[string]string `json:"Labels"`
+	ComposeProject string            `json:"ComposeProject"`
+	ComposeService string            `json:"ComposeService"`
+	Trusted        bool              `json:"Trusted"`
+}
+
+// imageRecord is dockit's view of an image
+type imageRecord struct {
+	ID          string            `json:"ID"`
+	RepoTags    []string          `json:"RepoTags"`
+	RepoDigests []string          `json:"RepoDigests"`
+	Size        int64             `json:"Size"`
+	Created     int64             `json:"Created"`
+	Containers  int64             `json:"Containers"`
+	Dangling    bool              `json:"Dangling"`
+	Labels      map[string]string `json:"Labels"`
+}
+
+// volumeRecord is dockit's view of a volume
+type volumeRecord struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	Scope      string            `json:"Scope"`
+	Mountpoint string            `json:"Mountpoint"`
+	CreatedAt  string            `json:"CreatedAt"`
+	Labels     map[string]string `json:"Labels"`
+	InUse      bool              `json:"InUse"`
+}
+
+// networkRecord is dockit's view of a network
+type networkRecord struct {
+	ID         string            `json:"ID"`
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	Scope      string            `json:"Scope"`
+	Internal   bool              `json:"Internal"`
+	Attachable bool              `json:"Attachable"`
+	Subnets    []string          `json:"Subnets"`
+	Created    int64             `json:"Created"`
+	Labels     map[string]string `json:"Labels"`
+	InUse      bool              `json:"InUse"`
+}
+
+func newContainerRecord(ctx context.Context, c container.Summary, allowlist []string, times *containerTimesCache) containerRecord {
+	record := containerRecord{
+		ID:             c.ID,
+		Image:          c.Image,
+		ImageID:        c.ImageID,
+		Command:        c.Command,
+		Created:        c.Created,
+		State:          c.State,
+		Status:         c.Status,
+		Health:         healthFromStatus(c.Status),
+		Labels:         c.Labels,
+		ComposeProject: c.Labels[composeProjectLabel],
+		ComposeService: c.Labels[composeServiceLabel],
+		Trusted:        isTrustedImage(c.Image, allowlist),
+		Ports:          []string{},
+		Networks:       []string{},
+		Mounts:         []string{},
+	}
+
+	for _, name := range c.Names {
+		record.Names = append(record.Names, strings.TrimPrefix(name, "/"))
+	}
+	if len(record.Names) > 0 {
+		record.Name = record.Names[0]
+	}
+
+	for _, port := range c.Ports {
+		if port.PublicPort > 0 {
+			record.Ports = append(record.Ports, fmt.Sprintf("%d:%d/%s", port.PublicPort, port.PrivatePort, port.Type))
+		} else {
+			record.Ports = append(record.Ports, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
+		}
+	}
+
+	if c.NetworkSettings != nil {
+		for name := range c.NetworkSettings.Networks {
+			record.Networks = append(record.Networks, name)
+		}
+	}
+
+	for _, m := range c.Mounts {
+		source := m.Name
+		if source == "" {
+			source = m.Source
+		}
+		record.Mounts = append(record.Mounts, source+":"+m.Destination)
+	}
+
+	if t, ok := times.get(ctx, c.ID); ok {
+		if !t.started.IsZero() {
+			record.StartedAt = t.started.Format(time.RFC3339)
+		}
+		if !t.finished.IsZero() {
+			record.FinishedAt = t.finished.Format(time.RFC3339)
+		}
+		record.ExitCode = t.exitCode
+		record.RestartCount = t.restarts
+	}
+
+	return record
+}
+
+func newImageRecord(img image.Summary) imageRecord {
+	return imageRecord{
+		ID:          img.ID,
+		RepoTags:    img.RepoTags,
+		RepoDigests: img.RepoDigests,
+		Size:        img.Size,
+		Created:     img.Created,
+		Containers:  img.Containers,
+		Dangling:    len(img.RepoTags) == 0 || (len(img.RepoTags) == 1 && img.RepoTags[0] == "<none>:<none>"),
+		Labels:      img.Labels,
+	}
+}
+
+func newVolumeRecord(v *volume.Volume, used map[string]bool) volumeRecord {
+	return volumeRecord{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Scope:      v.Scope,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  v.CreatedAt,
+		Labels:     v.Labels,
+		InUse:      used[v.Name],
+	}
+}
+
+func newNetworkRecord(n network.Summary, used map[string]bool) networkRecord {
+	record := networkRecord{
+		ID:         n.ID,
+		Name:       n.Name,
+		Driver:     n.Driver,
+		Scope:      n.Scope,
+		Internal:   n.Internal,
+		Attachable: n.Attachable,
+		Created:    n.Created.Unix(),
+		Labels:     n.Labels,
+		InUse:      used[n.Name],
+		Subnets:    []string{},
+	}
+	for _, cfg := range n.IPAM.Config {
+		if cfg.Subnet != "" {
+			record.Subnets = append(record.Subnets, cfg.Subnet)
+		}
+	}
+	return record
+}
+
+// collectRecords fetches all resources of the given kind as enriched records
+func collectRecords(ctx context.Context, cli *client.Client, kind string) (any, error) {
+	switch kind {
+	case "containers":
+		containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+		if err != nil {
+			return nil, err
+		}
+		allowlist := trustedRegistries()
+		times := newContainerTimesCache(cli)
+		records := []containerRecord{}
+		for _, c := range containers {
+			records = append(records, newContainerRecord(ctx, c, allowlist, times))
+		}
+		return records, nil
+	case "images":
+		images, err := cli.ImageList(ctx, image.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		records := []imageRecord{}
+		for _, img := range images {
+			records = append(records, newImageRecord(img))
+		}
+		return records, nil
+	case "volumes":
+		response, err := cli.VolumeList(ctx, volume.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+		if err != nil {
+			return nil, err
+		}
+		used := volumesInUse(containers)
+		records := []volumeRecord{}
+		for _, v := range response.Volumes {
+			records = append(records, newVolumeRecord(v, used))
+		}
+		return records, nil
+	case "networks":
+		networks, err := cli.NetworkList(ctx, network.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+		if err != nil {
+			return nil, err
+		}
+		used := networksInUse(containers)
+		records := []networkRecord{}
+		for _, n := range networks {
+			records = append(records, newNetworkRecord(n, used))
+		}
+		return records, nil
+	}
+	return nil, fmt.Errorf("unknown resource %q (expected containers, images, volumes, or networks)", kind)
+}
+
+// volumesInUse returns the names of volumes mounted by any of containers
+func volumesInUse(containers []container.Summary) map[string]bool {
+	used := map[string]bool{}
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Name != "" {
+				used[m.Name] = true
+			}
+		}
+	}
+	return used
+}
+
+// imagesInUse returns the IDs of images any of containers was created from,
+// running or not
+func imagesInUse(containers []container.Summary) map[string]bool {
+	used := map[string]bool{}
+	for _, c := range containers {
+		used[c.ImageID] = true
+	}
+	return used
+}
+
+// networksInUse returns the names of networks any of containers is attached to
+func networksInUse(containers []container.Summary) map[string]bool {
+	used := map[string]bool{}
+	for _, c := range containers {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		for name := range c.NetworkSettings.Networks {
+			used[name] = true
+		}
+	}
+	return used
+}
+
+// healthFromStatus extracts the healthcheck state from a container status
+// string such as "Up 5 minutes (healthy)"
+func healthFromStatus(status string) string {
+	switch {
+	case strings.Contains(status, "(healthy)"):
+		return "healthy"
+	case strings.Contains(status, "(unhealthy)"):
+		return "unhealthy"
+	case strings.Contains(status, "(health: starting)"):
+		return "starting"
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+)
+
+// RecreateContainer replaces a container with a new one built from the same
+// configuration on the image its tag currently points to
+func RecreateContainer(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit recreate CONTAINER [CONTAINER...]")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	failed := false
+	for _, name := range args {
+		if err := recreateContainer(ctx, cli, name); err != nil {
+			red.Print(glyphs.failed + " ")
+			fmt.Printf("%s: ", name)
+			red.Println(err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func recreateContainer(ctx context.Context, cli *client.Client, name string) error {
+	info, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return err
+	}
+	name = strings.TrimPrefix(info.Name, "/")
+	ref := info.Config.Image
+
+	current, err := cli.ImageInspect(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("image %s not found locally (pull it first): %v", ref, err)
+	}
+	if current.ID == info.Image {
+		gray.Printf("%s already runs the current %s\n", name, ref)
+		return nil
+	}
+
+	containerConfig := info.Config
+	containerConfig.Image = ref
+	if err := replaceContainer(ctx, cli, info, containerConfig); err != nil {
+		return err
+	}
+
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Recreated %s on %s ", name, ref)
+	gray.Printf("(%s -> %s)\n", formatID(info.Image, false), formatID(current.ID, false))
+	return nil
+}
+
+// replaceContainer swaps a container for a new one with the same name, host
+// config, volumes, and networks, created from containerConfig
+func replaceContainer(ctx context.Context, cli *client.Client, info container.InspectResponse, containerConfig *container.Config) error {
+	name := strings.TrimPrefix(info.Name, "/")
+	wasRunning := info.State != nil && info.State.Running
+
+	// Keep the old container until the new one is up, so it can be restored
+	if wasRunning {
+		if err := cli.ContainerStop(ctx, info.ID, container.StopOptions{Timeout: stopTimeoutFor(info.Config)}); err != nil {
+			return fmt.Errorf("stopping: %v", err)
+		}
+	}
+	backup := name + "_dockit_old"
+	if err := cli.ContainerRename(ctx, info.ID, backup); err != nil {
+		return fmt.Errorf("renaming: %v", err)
+	}
+
+	restore := func() {
+		cli.ContainerRename(ctx, info.ID, name)
+		if wasRunning {
+			cli.ContainerStart(ctx, info.ID, container.StartOptions{})
+		}
+	}
+
+	if containerConfig.Hostname == info.ID[:12] {
+		// Let the new container get its own default hostname
+		containerConfig.Hostname = ""
+	}
+
+	// Connect to one network at create time and the rest afterwards, which
+	// works on every API version
+	var networks []string
+	for networkName := range info.NetworkSettings.Networks {
+		networks = append(networks, networkName)
+	}
+	networking := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+	if len(networks) > 0 {
+		networking.EndpointsConfig[networks[0]] = endpointFor(info.NetworkSettings.Networks[networks[0]], info.ID)
+	}
+
+	created, err := cli.ContainerCreate(ctx, containerConfig, keepVolumes(info), networking, nil, name)
+	if err != nil {
+		restore()
+		return fmt.Errorf("creating: %v", err)
+	}
+
+	for _, networkName := range networks[min(1, len(networks)):] {
+		if err := cli.NetworkConnect(ctx, networkName, created.ID, endpointFor(info.NetworkSettings.Networks[networkName], info.ID)); err != nil {
+			cli.ContainerRemove(ctx, created.ID, container.RemoveOptions{Force: true})
+			restore()
+			return fmt.Errorf("connecting to %s: %v", networkName, err)
+		}
+	}
+
+	if wasRunning {
+		if err := cli.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+			cli.ContainerRemove(ctx, created.ID, container.RemoveOptions{Force: true})
+			restore()
+			return fmt.Errorf("starting: %v", err)
+		}
+	}
+
+	if err := cli.ContainerRemove(ctx, info.ID, container.RemoveOptions{}); err != nil {
+		yellow.Printf("%s Could not remove old container %s: %v\n", glyphs.warn, backup, err)
+	}
+	return nil
+}
+
+// keepVolumes is the container's host config with every volume it has
+// mounted carried over by name. Anonymous volumes, from the image's VOLUME
+// or a bare -v /path, aren't in the host config, so without this the new
+// container would get fresh empty ones and the data would stay behind.
+func keepVolumes(info container.InspectResponse) *container.HostConfig {
+	hostConfig := *info.HostConfig
+	hostConfig.Binds = slices.Clone(hostConfig.Binds)
+	hostConfig.Mounts = slices.Clone(hostConfig.Mounts)
+
+	named := map[string]string{} // volume name by destination
+	for _, m := range info.Mounts {
+		if m.Type == mount.TypeVolume && m.Name != "" {
+			named[m.Destination] = m.Name
+		}
+	}
+
+	// Destinations the host config mounts already keep their volume, except
+	// a --mount volume with no source, which is anonymous too
+	covered := map[string]bool{}
+	for _, bind := range hostConfig.Binds {
+		if parts := strings.Split(bind, ":"); len(parts) >= 2 {
+			covered[parts[1]] = true
+		}
+	}
+	for i, m := range hostConfig.Mounts {
+		if m.Type == mount.TypeVolume && m.Source == "" {
+			hostConfig.Mounts[i].Source = named[m.Target]
+		}
+		covered[m.Target] = true
+	}
+
+	for _, m := range info.Mounts {
+		if m.Type != mount.TypeVolume || m.Name == "" || covered[m.Destination] {
+			continue
+		}
+		bind := m.Name + ":" + m.Destination
+		if !m.RW {
+			bind += ":ro"
+		}
+		hostConfig.Binds = append(hostConfig.Binds, bind)
+	}
+	return &hostConfig
+}
+
+// endpointFor copies the user-configurable parts of an endpoint, leaving
+// addresses and the old container's ID alias for Docker to reassign
+func endpointFor(settings *network.EndpointSettings, oldID string) *network.EndpointSettings {
+	if settings == nil {
+		return &network.EndpointSettings{}
+	}
+
+	var aliases []string
+	for _, alias := range settings.Aliases {
+		if !strings.HasPrefix(oldID, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return &network.EndpointSettings{
+		IPAMConfig: settings.IPAMConfig,
+		Links:      settings.Links,
+		Aliases:    aliases,
+		DriverOpts: settings.DriverOpts,
+	}
+}
@@ -0,0 +1,168 @@
+package pretty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// defaultNameTemplate names containers after their image, numbering the
+// second and later ones: nginx, nginx-2, nginx-3
+const defaultNameTemplate = "{{.Image}}{{if gt .N 1}}-{{.N}}{{end}}"
+
+// invalidNameChars are the characters Docker doesn't allow in names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// nameTemplateData is what name_template can refer to
+type nameTemplateData struct {
+	Image string // the image's last path element without its tag: nginx
+	Tag   string // "latest" when the reference has none
+	N     int    // counts up from 1 until the name is free
+}
+
+// runSuggestionsMsg delivers port suggestions for the image they were made for
+type runSuggestionsMsg struct {
+	image string
+	ports []string
+}
+
+// suggestName renders the name template for imageRef with the lowest N
+// that gives a name no container has yet
+func suggestName(imageRef string, taken map[string]bool) string {
+	text := config.Defaults.NameTemplate
+	if text == "" {
+		text = defaultNameTemplate
+	}
+	tmpl, err := template.New("name").Parse(text)
+	if err != nil {
+		return ""
+	}
+
+	ref := imageRef
+	if at := strings.Index(ref, "@"); at >= 0 {
+		ref = ref[:at]
+	}
+	data := nameTemplateData{Image: ref, Tag: "latest"}
+	if colon := strings.LastIndex(ref, ":"); colon > strings.LastIndex(ref, "/") {
+		data.Image, data.Tag = ref[:colon], ref[colon+1:]
+	}
+	data.Image = data.Image[strings.LastIndex(data.Image, "/")+1:]
+
+	for data.N = 1; data.N < 1000; data.N++ {
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return ""
+		}
+		name := strings.Trim(invalidNameChars.ReplaceAllString(out.String(), "-"), "-_.")
+		if name == "" {
+			return ""
+		}
+		if !taken[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// suggestPorts inspects the image in the background and suggests a free host
+// port for each port it exposes
+func suggestPorts(ctx context.Context, cli *client.Client, imageRef string, used map[string]bool, local bool) tea.Cmd {
+	return func() tea.Msg {
+		info, err := cli.ImageInspect(ctx, imageRef)
+		if err != nil || info.Config == nil {
+			// Not pulled yet, so there is nothing to go on
+			return runSuggestionsMsg{image: imageRef}
+		}
+		return runSuggestionsMsg{image: imageRef, ports: suggestHostPorts(info.Config.ExposedPorts, used, local)}
+	}
+}
+
+// suggestHostPorts picks a host port for each exposed port: the same number
+// when it is unprivileged and free, otherwise counting up from 8000 plus
+// the number for privileged ports (80 becomes 8080) or from the number
+// itself. used holds the ports other containers publish, as "8080/tcp";
+// on a local daemon, ports something else listens on are skipped too.
+func suggestHostPorts(exposed map[string]struct{}, used map[string]bool, local bool) []string {
+	var ports []nat.Port
+	for spec := range exposed {
+		ports = append(ports, nat.Port(spec))
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Int() < ports[j].Int() })
+
+	taken := map[string]bool{}
+	for key := range used {
+		taken[key] = true
+	}
+
+	var specs []string
+	for _, port := range ports {
+		number, proto := port.Int(), port.Proto()
+		host := number
+		if host < 1024 {
+			host += 8000
+		}
+		for ; host < 65536; host++ {
+			key := fmt.Sprintf("%d/%s", host, proto)
+			if !taken[key] && (!local || hostPortFree(host, proto)) {
+				taken[key] = true
+				break
+			}
+		}
+		if host == 65536 {
+			continue
+		}
+
+		spec := fmt.Sprintf("%d:%d", host, number)
+		if proto != "tcp" {
+			spec += "/" + proto
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// hostPortFree reports whether nothing on this machine listens on port
+func hostPortFree(port int, proto string) bool {
+	address := fmt.Sprintf(":%d", port)
+	if proto == "udp" {
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// daemonIsLocal reports whether the daemon runs on this machine, so its
+// published ports share this machine's
+func daemonIsLocal(daemonHost string) bool {
+	u, err := url.Parse(daemonHost)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "unix", "npipe":
+		return true
+	case "tcp", "http", "https":
+		ip := net.ParseIP(u.Hostname())
+		return u.Hostname() == "localhost" || (ip != nil && ip.IsLoopback())
+	}
+	return false
+}
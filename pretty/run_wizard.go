@@ -0,0 +1,457 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+var restartPolicies = []string{"no", "on-failure", "always", "unless-stopped"}
+
+// runSpec is what the wizard collects for docker run
+type runSpec struct {
+	image   string
+	name    string
+	command []string
+	ports   []string
+	env     []string
+	volumes []string
+	restart string
+	network string
+}
+
+// wizardField is one row of the run form: free text, or a fixed set of
+// choices cycled with left/right
+type wizardField struct {
+	label   string
+	hint    string
+	input   textinput.Model
+	choices []string
+	choice  int
+}
+
+func (f wizardField) value() string {
+	if f.choices != nil {
+		return f.choices[f.choice]
+	}
+	return strings.TrimSpace(f.input.Value())
+}
+
+// Field order in the form
+const (
+	fieldImage = iota
+	fieldName
+	fieldCommand
+	fieldPorts
+	fieldEnv
+	fieldVolumes
+	fieldRestart
+	fieldNetwork
+)
+
+type runWizardModel struct {
+	fields    []wizardField
+	focus     int
+	err       error
+	submitted bool
+
+	// Suggestions for the name and ports, replaced when the image changes
+	// unless the user has edited them
+	ctx            context.Context
+	cli            *client.Client
+	names          map[string]bool // existing container names
+	usedPorts      map[string]bool // host ports containers publish, as "8080/tcp"
+	local          bool            // the daemon shares this machine's ports
+	suggestedFor   string
+	suggestedName  string
+	suggestedPorts string
+}
+
+func newRunWizardModel(imageRef string, networks []string) runWizardModel {
+	text := func(label, placeholder, hint string) wizardField {
+		ti := textinput.New()
+		ti.Placeholder = placeholder
+		ti.CharLimit = 500
+		ti.Width = 50
+		return wizardField{label: label, hint: hint, input: ti}
+	}
+
+	fields := []wizardField{
+		text("Image", "nginx:latest", "required"),
+		text("Name", "web", "optional; suggested from the image, or Docker picks one if empty"),
+		text("Command", "", "optional; overrides the image's command"),
+		text("Ports", "8080:80, 443:443", "HOST:CONTAINER, comma separated; free host ports are suggested for a pulled image"),
+		text("Env", "KEY=value, DEBUG=1", "comma separated"),
+		text("Volumes", "data:/var/lib/data, ./conf:/etc/app:ro", "NAME_OR_PATH:PATH[:ro], comma separated"),
+		{label: "Restart", choices: restartPolicies},
+		{label: "Network", choices: append([]string{"default"}, networks...)},
+	}
+	fields[fieldImage].input.SetValue(imageRef)
+	fields[fieldImage].input.Focus()
+
+	return runWizardModel{fields: fields}
+}
+
+func (m runWizardModel) Init() tea.Cmd {
+	if m.suggestedFor == "" {
+		return textinput.Blink
+	}
+	// The name for the command line's image is filled in already; the ports
+	// need the image inspected
+	return tea.Batch(textinput.Blink, suggestPorts(m.ctx, m.cli, m.suggestedFor, m.usedPorts, m.local))
+}
+
+// suggest fills in a name for the image, and asks for port suggestions,
+// when the image has changed since the last suggestions
+func (m *runWizardModel) suggest() tea.Cmd {
+	imageRef := m.fields[fieldImage].value()
+	if m.cli == nil || imageRef == "" || imageRef == m.suggestedFor {
+		return nil
+	}
+	m.suggestedFor = imageRef
+
+	name := &m.fields[fieldName].input
+	if value := strings.TrimSpace(name.Value()); value == "" || value == m.suggestedName {
+		m.suggestedName = suggestName(imageRef, m.names)
+		name.SetValue(m.suggestedName)
+	}
+	return suggestPorts(m.ctx, m.cli, imageRef, m.usedPorts, m.local)
+}
+
+func (m runWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if suggestions, ok := msg.(runSuggestionsMsg); ok {
+		ports := &m.fields[fieldPorts].input
+		value := strings.TrimSpace(ports.Value())
+		if suggestions.image == m.suggestedFor && (value == "" || value == m.suggestedPorts) {
+			m.suggestedPorts = strings.Join(suggestions.ports, ", ")
+			ports.SetValue(m.suggestedPorts)
+		}
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	m.err = nil
+	field := &m.fields[m.focus]
+	switch keyMsg.String() {
+	case "ctrl+c", "esc":
+		return m, tea.Quit
+	case "tab", "down":
+		return m, m.moveFocus(1)
+	case "shift+tab", "up":
+		return m, m.moveFocus(-1)
+	case "left", "right":
+		if field.choices != nil {
+			step := 1
+			if keyMsg.String() == "left" {
+				step = len(field.choices) - 1
+			}
+			field.choice = (field.choice + step) % len(field.choices)
+			return m, nil
+		}
+	case "enter", "ctrl+s":
+		// Enter moves down the form; on the last field, or with ctrl+s, it runs
+		if keyMsg.String() == "enter" && m.focus < len(m.fields)-1 {
+			return m, m.moveFocus(1)
+		}
+		if _, err := m.spec(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.submitted = true
+		return m, tea.Quit
+	}
+
+	if field.choices != nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	field.input, cmd = field.input.Update(keyMsg)
+	return m, cmd
+}
+
+// moveFocus moves to the next or previous field; choice fields have no text
+// input to focus
+func (m *runWizardModel) moveFocus(step int) tea.Cmd {
+	var suggest tea.Cmd
+	if m.focus == fieldImage {
+		suggest = m.suggest()
+	}
+	if m.fields[m.focus].choices == nil {
+		m.fields[m.focus].input.Blur()
+	}
+	m.focus = (m.focus + step + len(m.fields)) % len(m.fields)
+	if m.fields[m.focus].choices != nil {
+		return suggest
+	}
+	return tea.Batch(suggest, m.fields[m.focus].input.Focus())
+}
+
+// spec validates the form and turns it into a runSpec
+func (m runWizardModel) spec() (runSpec, error) {
+	list := func(i int) []string {
+		var values []string
+		for _, value := range strings.Split(m.fields[i].value(), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	spec := runSpec{
+		image:   m.fields[fieldImage].value(),
+		name:    m.fields[fieldName].value(),
+		command: strings.Fields(m.fields[fieldCommand].value()),
+		ports:   list(fieldPorts),
+		env:     list(fieldEnv),
+		volumes: list(fieldVolumes),
+		restart: m.fields[fieldRestart].value(),
+		network: m.fields[fieldNetwork].value(),
+	}
+	if spec.network == "default" {
+		spec.network = ""
+	}
+
+	if spec.image == "" {
+		return spec, fmt.Errorf("an image is required")
+	}
+	if _, _, err := nat.ParsePortSpecs(spec.ports); err != nil {
+		return spec, fmt.Errorf("ports: %v", err)
+	}
+	for _, env := range spec.env {
+		if key, _, _ := strings.Cut(env, "="); key == "" {
+			return spec, fmt.Errorf("env: %q needs a name before =", env)
+		}
+	}
+	for _, volume := range spec.volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || !strings.HasPrefix(parts[1], "/") {
+			return spec, fmt.Errorf("volumes: %q should be NAME_OR_PATH:/container/path[:ro]", volume)
+		}
+	}
+	return spec, nil
+}
+
+func (m runWizardModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("RUN A CONTAINER"))
+	sb.WriteString("\n")
+
+	for i, field := range m.fields {
+		cursor := "  "
+		label := fmt.Sprintf("%-9s", field.label)
+		if i == m.focus {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+			label = cursorStyle.Render(label)
+		}
+
+		value := field.input.View()
+		if field.choices != nil {
+			value = "< " + selectedStyle.Render(field.choices[field.choice]) + " >"
+		}
+		sb.WriteString(cursor + label + " " + value + "\n")
+		if field.hint != "" && i == m.focus {
+			sb.WriteString(helpStyle.Render("            "+field.hint) + "\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	if m.err != nil {
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(helpStyle.Render("tab/" + glyphs.arrows + ": move | " + "left/right: change choice | enter on last field or ctrl+s: run | esc: cancel"))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// RunWizard walks through a form for docker run, then creates and starts
+// the container and prints the equivalent docker run command
+func RunWizard(args []string) {
+	imageRef := ""
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			imageRef = arg
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	// Offer the user-defined networks; the built-in ones are rarely wanted
+	var networks []string
+	if list, err := cli.NetworkList(ctx, network.ListOptions{}); err == nil {
+		for _, n := range list {
+			if n.Name != "bridge" && n.Name != "host" && n.Name != "none" {
+				networks = append(networks, n.Name)
+			}
+		}
+	}
+	networks = append(networks, "host", "none")
+
+	model := newRunWizardModel(imageRef, networks)
+	model.ctx, model.cli = ctx, cli
+	model.names, model.usedPorts = map[string]bool{}, map[string]bool{}
+	model.local = daemonIsLocal(cli.DaemonHost())
+	if containers, err := cli.ContainerList(ctx, container.ListOptions{All: true}); err == nil {
+		for _, c := range containers {
+			for _, name := range c.Names {
+				model.names[strings.TrimPrefix(name, "/")] = true
+			}
+			for _, port := range c.Ports {
+				if port.PublicPort != 0 {
+					model.usedPorts[fmt.Sprintf("%d/%s", port.PublicPort, port.Type)] = true
+				}
+			}
+		}
+	}
+	model.suggest()
+
+	p := newProgram(model)
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+	result := final.(runWizardModel)
+	if !result.submitted {
+		gray.Println("Cancelled")
+		return
+	}
+	spec, _ := result.spec()
+
+	fmt.Println()
+	cyan.Println("Equivalent command:")
+	fmt.Println("  " + spec.dockerRunCommand())
+	fmt.Println()
+
+	id, err := createFromSpec(ctx, cli, spec)
+	if err != nil {
+		red.Printf("%s ", glyphs.failed)
+		fmt.Printf("Error creating container: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := cli.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+		red.Printf("%s ", glyphs.failed)
+		fmt.Printf("Error starting container: %v\n", err)
+		gray.Printf("The container was created; inspect it with: dockit logs %s\n", formatID(id, false))
+		os.Exit(1)
+	}
+
+	name := spec.name
+	if name == "" {
+		name = formatID(id, false)
+	}
+	green.Printf("%s ", glyphs.ok)
+	fmt.Printf("Started %s ", name)
+	gray.Printf("(%s)\n", spec.image)
+}
+
+// createFromSpec creates the container, pulling the image first if it
+// isn't present, like docker run
+func createFromSpec(ctx context.Context, cli *client.Client, spec runSpec) (string, error) {
+	exposed, bindings, err := nat.ParsePortSpecs(spec.ports)
+	if err != nil {
+		return "", err
+	}
+
+	// The API only takes absolute bind sources; docker run resolves
+	// relative ones against the working directory
+	binds := make([]string, len(spec.volumes))
+	for i, volume := range spec.volumes {
+		binds[i] = volume
+		if source, rest, _ := strings.Cut(volume, ":"); strings.HasPrefix(source, ".") {
+			if abs, err := filepath.Abs(source); err == nil {
+				binds[i] = abs + ":" + rest
+			}
+		}
+	}
+
+	config := &container.Config{
+		Image:        spec.image,
+		Cmd:          spec.command,
+		Env:          spec.env,
+		ExposedPorts: exposed,
+	}
+	hostConfig := &container.HostConfig{
+		PortBindings:  bindings,
+		Binds:         binds,
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyMode(spec.restart)},
+		NetworkMode:   container.NetworkMode(spec.network),
+	}
+
+	created, err := cli.ContainerCreate(ctx, config, hostConfig, nil, nil, spec.name)
+	if client.IsErrNotFound(err) {
+		gray.Printf("Pulling %s...\n", spec.image)
+		reader, pullErr := cli.ImagePull(ctx, spec.image, image.PullOptions{RegistryAuth: registryAuth(spec.image)})
+		if pullErr != nil {
+			return "", pullErr
+		}
+		_, pullErr = io.Copy(io.Discard, reader)
+		reader.Close()
+		if pullErr != nil {
+			return "", pullErr
+		}
+		created, err = cli.ContainerCreate(ctx, config, hostConfig, nil, nil, spec.name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// dockerRunCommand renders the spec as a docker run command line
+func (spec runSpec) dockerRunCommand() string {
+	args := []string{"run", "-d"}
+	if spec.name != "" {
+		args = append(args, "--name", spec.name)
+	}
+	for _, port := range spec.ports {
+		args = append(args, "-p", port)
+	}
+	for _, env := range spec.env {
+		args = append(args, "-e", env)
+	}
+	for _, volume := range spec.volumes {
+		args = append(args, "-v", volume)
+	}
+	if spec.restart != "" && spec.restart != "no" {
+		args = append(args, "--restart", spec.restart)
+	}
+	if spec.network != "" {
+		args = append(args, "--network", spec.network)
+	}
+	args = append(append(args, spec.image), spec.command...)
+
+	// Include --context or --host so the command targets the same daemon
+	args = DockerCommand(args...).Args
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,318 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// SaveImages writes images to a tarball like docker save, drawing a progress
+// bar against their combined size, and records its checksum in the manifest
+// beside it
+func SaveImages(args []string) {
+	output := ""
+	quiet := false
+	record := true
+	var refs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-o" || arg == "--output") && i+1 < len(args):
+			i++
+			output = args[i]
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		case arg == "-q" || arg == "--quiet":
+			quiet = true
+		case arg == "--no-manifest":
+			record = false
+		case !strings.HasPrefix(arg, "-"):
+			refs = append(refs, arg)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			os.Exit(1)
+		}
+	}
+	if len(refs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: at least one image required\n")
+		fmt.Println("Usage: dockit save [-q] [--no-manifest] [-o FILE] IMAGE...")
+		os.Exit(1)
+	}
+	if output == "" && isTerminal(os.Stdout) {
+		fmt.Fprintf(os.Stderr, "Error: refusing to write a tarball to the terminal; use -o FILE or redirect stdout\n")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	// Piped output has no room for progress; it is the tarball
+	if output == "" {
+		reader, err := cli.ImageSave(ctx, refs)
+		if err == nil {
+			_, err = io.Copy(os.Stdout, reader)
+			reader.Close()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving images: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := saveImagesToFile(ctx, cli, refs, output, quiet, record); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving images: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// saveImagesToFile saves refs to a tarball at path, adding it to the
+// manifest in its directory when record is set
+func saveImagesToFile(ctx context.Context, cli *client.Client, refs []string, path string, quiet, record bool) error {
+	showProgress := !quiet && isTerminal(os.Stdout)
+	if showProgress {
+		preparePalette()
+	}
+
+	// The tarball is about the images' unpacked size; shared layers count once
+	// per image, so this errs high
+	var total int64
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		img, err := cli.ImageInspect(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if !seen[img.ID] {
+			seen[img.ID] = true
+			total += img.Size
+		}
+	}
+
+	reader, err := cli.ImageSave(ctx, refs)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	label := strings.Join(refs, ", ") + " " + glyphs.next + " " + path
+	written, digest, err := writeArchive(reader, path, label, total, showProgress)
+	if err != nil {
+		return err
+	}
+
+	if !quiet {
+		green.Printf("%s ", glyphs.ok)
+		fmt.Printf("Saved %d image(s) to %s ", len(refs), path)
+		gray.Printf("(%s)\n", formatSize(written))
+	}
+	if record {
+		manifestPath, err := recordArtifact(path, written, digest, "images", refs)
+		if err != nil || !quiet {
+			printRecorded(manifestPath, err)
+		}
+	}
+	return nil
+}
+
+// writeArchive copies a tarball stream to path with a progress line,
+// writing beside it first so a failed transfer never leaves a truncated file
+// under the final name. It returns the bytes written and their hex SHA256.
+func writeArchive(reader io.Reader, path, label string, total int64, showProgress bool) (int64, string, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), ".dockit-tar-")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(file.Name())
+
+	counter := &countingReader{r: reader}
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		progressPrinter(label, showProgress)(counter, total, stop)
+		close(finished)
+	}()
+
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hasher), counter)
+	close(stop)
+	<-finished
+	if showProgress {
+		fmt.Print("\r\033[K")
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	written := counter.n.Load()
+	if err != nil {
+		return written, "", err
+	}
+
+	// Temp files are private; a saved tarball is as readable as docker save's
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return written, "", err
+	}
+	return written, hex.EncodeToString(hasher.Sum(nil)), os.Rename(file.Name(), path)
+}
+
+// LoadImages imports images from a tarball like docker load, drawing a
+// progress bar as the file uploads and listing what was loaded
+func LoadImages(args []string) {
+	input := ""
+	quiet := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-i" || arg == "--input") && i+1 < len(args):
+			i++
+			input = args[i]
+		case strings.HasPrefix(arg, "--input="):
+			input = strings.TrimPrefix(arg, "--input=")
+		case arg == "-q" || arg == "--quiet":
+			quiet = true
+		case !strings.HasPrefix(arg, "-"):
+			input = arg
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			os.Exit(1)
+		}
+	}
+	if input == "" && isTerminal(os.Stdin) {
+		fmt.Fprintf(os.Stderr, "Error: tarball required\n")
+		fmt.Println("Usage: dockit load [-q] [-i] FILE   or   dockit load < FILE")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	source, label := io.Reader(os.Stdin), "stdin"
+	var total int64
+	if input != "" {
+		file, err := os.Open(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		if info, err := file.Stat(); err == nil {
+			total = info.Size()
+		}
+		source, label = file, input
+	}
+
+	loaded, size, err := loadImages(context.Background(), cli, source, total, label, !quiet && isTerminal(os.Stdout))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading %s: %v\n", label, err)
+		os.Exit(1)
+	}
+
+	if quiet {
+		for _, name := range loaded {
+			fmt.Println(name)
+		}
+		return
+	}
+	green.Printf("%s ", glyphs.ok)
+	fmt.Printf("Loaded %d image(s) from %s ", len(loaded), label)
+	gray.Printf("(%s)\n", formatSize(size))
+	for _, name := range loaded {
+		gray.Printf("  %s %s\n", glyphs.detail, name)
+	}
+}
+
+// loadImages uploads a tarball and returns the images the daemon reports
+// loading, and the bytes sent
+func loadImages(ctx context.Context, cli *client.Client, source io.Reader, total int64, label string, showProgress bool) ([]string, int64, error) {
+	if showProgress {
+		preparePalette()
+	}
+
+	counter := &countingReader{r: bufio.NewReader(source)}
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		progressPrinter(label, showProgress)(counter, total, stop)
+		close(finished)
+	}()
+	response, err := cli.ImageLoad(ctx, counter, client.ImageLoadWithQuiet(true))
+	if err == nil {
+		defer response.Body.Close()
+	}
+	close(stop)
+	<-finished
+	if showProgress {
+		fmt.Print("\r\033[K")
+	}
+	if err != nil {
+		return nil, counter.n.Load(), err
+	}
+
+	// The daemon answers with "Loaded image: NAME" lines
+	var loaded []string
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return loaded, counter.n.Load(), err
+		}
+		if msg.Error != nil {
+			return loaded, counter.n.Load(), msg.Error
+		}
+		for _, line := range strings.Split(strings.TrimSpace(msg.Stream), "\n") {
+			if name, ok := strings.CutPrefix(line, "Loaded image: "); ok {
+				loaded = append(loaded, name)
+			} else if id, ok := strings.CutPrefix(line, "Loaded image ID: "); ok {
+				loaded = append(loaded, formatID(id, false))
+			}
+		}
+	}
+	return loaded, counter.n.Load(), nil
+}
+
+// saveSelectedImages is the images picker's save action: it asks where to
+// write, then saves every marked image into one tarball
+func saveSelectedImages(ctx context.Context, cli *client.Client, items []bulkItem) error {
+	refs := make([]string, 0, len(items))
+	for _, item := range items {
+		refs = append(refs, item.name)
+	}
+
+	path := "images.tar"
+	if len(refs) == 1 {
+		path = strings.NewReplacer("/", "_", ":", "_").Replace(refs[0]) + ".tar"
+	}
+	reader := bufio.NewReader(os.Stdin)
+	if answer := prompt(reader, fmt.Sprintf("Save %d image(s) to [%s]: ", len(refs), path)); answer != "" {
+		path = answer
+	}
+	if _, err := os.Stat(path); err == nil {
+		if !promptYesNo(reader, path+" exists. Overwrite?") {
+			gray.Println("Cancelled")
+			return nil
+		}
+	}
+	return saveImagesToFile(ctx, cli, refs, path, false, true)
+}
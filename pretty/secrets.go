@@ -0,0 +1,39 @@
+package pretty
+
+import "strings"
+
+// secretMask stands in for an environment value that looks like a secret
+const secretMask = "********"
+
+// defaultSecretEnv are the name fragments that mark a secret when the config
+// sets no secret_env
+var defaultSecretEnv = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL"}
+
+// isSecretEnv reports whether an environment variable's name contains one
+// of the secret_env fragments, ignoring case
+func isSecretEnv(name string) bool {
+	fragments := config.SecretEnv
+	if len(fragments) == 0 {
+		fragments = defaultSecretEnv
+	}
+	name = strings.ToUpper(name)
+	for _, fragment := range fragments {
+		if fragment != "" && strings.Contains(name, strings.ToUpper(fragment)) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecrets replaces the values of secret-looking variables in an
+// environment map, leaving empty values as they are
+func maskSecrets(env map[string]string) map[string]string {
+	masked := make(map[string]string, len(env))
+	for name, value := range env {
+		if value != "" && isSecretEnv(name) {
+			value = secretMask
+		}
+		masked[name] = value
+	}
+	return masked
+}
@@ -0,0 +1,282 @@
+package pretty
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sessionPrefix marks the tmux sessions dockit owns, so other tmux sessions
+// are left out of the panel
+const sessionPrefix = "dockit-"
+
+// execSession is a detachable exec session kept alive by tmux
+type execSession struct {
+	name      string // without sessionPrefix
+	container string
+	command   string
+	created   time.Time
+	attached  bool
+	exited    bool
+}
+
+// RunSessions manages exec sessions that survive detaching and dockit restarts
+func RunSessions(args []string) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: sessions need tmux installed\n")
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		if !isTerminal(os.Stdout) {
+			printSessions()
+			return
+		}
+		if err := LaunchSessionsTUI(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	switch args[0] {
+	case "ls", "list":
+		printSessions()
+	case "new":
+		newSession(args[1:])
+	case "attach":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: session name required\n")
+			os.Exit(1)
+		}
+		os.Exit(runCommand(attachTerminal(attachSessionCommand(args[1]))))
+	case "kill":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: session name required\n")
+			os.Exit(1)
+		}
+		for _, name := range args[1:] {
+			if err := killSession(name); err != nil {
+				red.Printf("%s ", glyphs.failed)
+				fmt.Printf("%s: %v\n", name, err)
+				continue
+			}
+			green.Printf("%s ", glyphs.ok)
+			fmt.Printf("Killed session %s\n", name)
+		}
+	case "-h", "--help":
+		printSessionsUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown sessions command %q\n", args[0])
+		printSessionsUsage()
+		os.Exit(1)
+	}
+}
+
+func printSessionsUsage() {
+	fmt.Println("Usage: dockit sessions [ls | new [-d] NAME CONTAINER [COMMAND...] | attach NAME | kill NAME...]")
+	fmt.Println()
+	fmt.Println("Exec sessions run inside tmux, so they keep running after you detach")
+	fmt.Println("(ctrl+b d) or quit dockit, and can be resumed later. With no arguments,")
+	fmt.Println("opens the Sessions panel.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -d    Start the session without attaching to it")
+}
+
+// newSession starts an exec session, running sh when no command is given
+func newSession(args []string) {
+	detached := false
+	var positional []string
+	for i, arg := range args {
+		if arg == "-d" || arg == "--detach" {
+			detached = true
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			os.Exit(1)
+		}
+		// Everything after the container is the command, flags included
+		positional = append(positional, arg)
+		if len(positional) == 2 {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+	}
+	if len(positional) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: session name and container required\n")
+		printSessionsUsage()
+		os.Exit(1)
+	}
+
+	name, containerName, command := positional[0], positional[1], positional[2:]
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+
+	if err := startSession(name, containerName, command); err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting session: %v\n", err)
+		os.Exit(1)
+	}
+
+	if detached {
+		green.Printf("%s ", glyphs.ok)
+		fmt.Printf("Started session %s ", name)
+		gray.Printf("(resume with: dockit sessions attach %s)\n", name)
+		return
+	}
+	os.Exit(runCommand(attachTerminal(attachSessionCommand(name))))
+}
+
+// startSession runs docker exec -it in a new detached tmux session. The pane
+// stays open after the command exits so its last output can still be read.
+func startSession(name, containerName string, command []string) error {
+	if name == "" || strings.ContainsAny(name, ".: ") {
+		return fmt.Errorf("session names can't be empty or contain '.', ':', or spaces")
+	}
+	target := sessionPrefix + name
+
+	docker := DockerCommand(append([]string{"exec", "-it", containerName}, command...)...)
+	quoted := make([]string, len(docker.Args))
+	for i, arg := range docker.Args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	cmd := exec.Command("tmux",
+		"new-session", "-d", "-s", target, strings.Join(quoted, " "), ";",
+		"set-option", "-t", target, "remain-on-exit", "on", ";",
+		"set-option", "-t", target, "@dockit_container", containerName, ";",
+		"set-option", "-t", target, "@dockit_command", strings.Join(command, " "),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// attachSessionCommand attaches the terminal to a session, switching to it
+// instead when dockit itself is running inside tmux
+func attachSessionCommand(name string) *exec.Cmd {
+	if os.Getenv("TMUX") != "" {
+		return exec.Command("tmux", "switch-client", "-t", sessionPrefix+name)
+	}
+	return exec.Command("tmux", "attach-session", "-t", sessionPrefix+name)
+}
+
+// attachTerminal connects cmd to dockit's own terminal
+func attachTerminal(cmd *exec.Cmd) *exec.Cmd {
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+func killSession(name string) error {
+	out, err := exec.Command("tmux", "kill-session", "-t", sessionPrefix+name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// listSessions returns dockit's tmux sessions, oldest first
+func listSessions() ([]execSession, error) {
+	format := strings.Join([]string{
+		"#{session_name}", "#{session_created}", "#{session_attached}",
+		"#{pane_dead}", "#{@dockit_container}", "#{@dockit_command}",
+	}, "\t")
+	out, err := exec.Command("tmux", "list-sessions", "-F", format).CombinedOutput()
+	if err != nil {
+		// No tmux server just means no sessions yet
+		if strings.Contains(string(out), "no server running") || strings.Contains(string(out), "error connecting") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+
+	var sessions []execSession
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 6 || !strings.HasPrefix(fields[0], sessionPrefix) {
+			continue
+		}
+		created, _ := strconv.ParseInt(fields[1], 10, 64)
+		sessions = append(sessions, execSession{
+			name:      strings.TrimPrefix(fields[0], sessionPrefix),
+			created:   time.Unix(created, 0),
+			attached:  fields[2] != "0",
+			exited:    fields[3] == "1",
+			container: fields[4],
+			command:   fields[5],
+		})
+	}
+	return sessions, nil
+}
+
+// sessionState describes a session for display
+func sessionState(s execSession) string {
+	switch {
+	case s.exited:
+		return "exited"
+	case s.attached:
+		return "attached"
+	default:
+		return "detached"
+	}
+}
+
+func printSessions() {
+	sessions, err := listSessions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing sessions: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Print header
+	fmt.Println()
+	cyan.Println("SESSIONS")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	for _, s := range sessions {
+		state := sessionState(s)
+		stateColor, indicator := green, glyphs.running
+		switch state {
+		case "exited":
+			stateColor, indicator = red, glyphs.stopped
+		case "detached":
+			stateColor, indicator = yellow, glyphs.paused
+		}
+
+		namePadded := s.name + strings.Repeat(" ", max(0, 20-len(s.name)))
+		statePadded := state + strings.Repeat(" ", 8-len(state))
+
+		// Print main line
+		stateColor.Print(indicator + " ")
+		fmt.Print(namePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		stateColor.Print(statePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		blue.Println(s.container)
+
+		gray.Printf("  %s Command: %s\n", glyphs.detail, s.command)
+		gray.Printf("  %s Started: %s\n", glyphs.detail, formatTime(s.created))
+	}
+
+	// Summary
+	fmt.Println()
+	fmt.Printf("Total: %d sessions\n", len(sessions))
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@,+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,219 @@
+package pretty
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var detachedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffd700"))
+
+// sessionsLoadedMsg delivers a fresh session list
+type sessionsLoadedMsg struct {
+	sessions []execSession
+	err      error
+}
+
+// sessionsTickMsg refreshes the list so exited sessions show up
+type sessionsTickMsg struct{}
+
+type sessionsModel struct {
+	sessions    []execSession
+	cursor      int
+	err         error
+	status      string
+	confirmKill string
+	creating    bool
+	newInput    textinput.Model
+}
+
+func loadSessions() tea.Msg {
+	sessions, err := listSessions()
+	return sessionsLoadedMsg{sessions: sessions, err: err}
+}
+
+func sessionsTick() tea.Cmd {
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg { return sessionsTickMsg{} })
+}
+
+func (m sessionsModel) Init() tea.Cmd {
+	return tea.Batch(loadSessions, sessionsTick())
+}
+
+func (m sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sessionsLoadedMsg:
+		m.err = msg.err
+		m.sessions = msg.sessions
+		m.cursor = max(0, min(m.cursor, len(m.sessions)-1))
+		return m, nil
+
+	case sessionsTickMsg:
+		return m, tea.Batch(loadSessions, sessionsTick())
+
+	case externalDoneMsg:
+		// Back from a session, detached or finished
+		m.err = msg.err
+		return m, loadSessions
+
+	case tea.KeyMsg:
+		if m.creating {
+			return m, m.updateNew(msg)
+		}
+
+		// Kill asks once before ending the session
+		if m.confirmKill != "" {
+			name := m.confirmKill
+			m.confirmKill = ""
+			if msg.String() != "y" {
+				m.status = ""
+				return m, nil
+			}
+			if err := killSession(name); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.status = "Killed " + name
+			return m, loadSessions
+		}
+
+		m.err = nil
+		m.status = ""
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.sessions)-1 {
+				m.cursor++
+			}
+		case "enter", "a":
+			if len(m.sessions) == 0 {
+				break
+			}
+			return m, tea.ExecProcess(attachSessionCommand(m.sessions[m.cursor].name), func(err error) tea.Msg {
+				return externalDoneMsg{err: err}
+			})
+		case "x":
+			if len(m.sessions) > 0 {
+				m.confirmKill = m.sessions[m.cursor].name
+			}
+		case "n":
+			m.creating = true
+			m.newInput.SetValue("")
+			m.newInput.Focus()
+			return m, textinput.Blink
+		case "r":
+			return m, loadSessions
+		}
+	}
+
+	return m, nil
+}
+
+// updateNew reads "NAME CONTAINER [COMMAND...]" and starts the session
+// detached, so it shows up in the list ready to attach
+func (m *sessionsModel) updateNew(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+	case "esc":
+		m.creating = false
+		m.newInput.Blur()
+		return nil
+	case "enter":
+		m.creating = false
+		m.newInput.Blur()
+		fields := strings.Fields(m.newInput.Value())
+		if len(fields) < 2 {
+			m.err = fmt.Errorf("enter a session name and a container")
+			return nil
+		}
+		command := fields[2:]
+		if len(command) == 0 {
+			command = []string{"sh"}
+		}
+		if err := startSession(fields[0], fields[1], command); err != nil {
+			m.err = err
+			return nil
+		}
+		m.status = "Started " + fields[0]
+		return loadSessions
+	}
+
+	var cmd tea.Cmd
+	m.newInput, cmd = m.newInput.Update(msg)
+	return cmd
+}
+
+func (m sessionsModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("SESSIONS"))
+	sb.WriteString("\n")
+
+	for i, s := range m.sessions {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+
+		state := sessionState(s)
+		indicator := selectedStyle.Render(glyphs.running)
+		switch state {
+		case "exited":
+			indicator = helpStyle.Render(glyphs.stopped)
+		case "detached":
+			indicator = detachedStyle.Render(glyphs.paused)
+		}
+
+		line := fmt.Sprintf("%s %-20s %-8s %-20s", indicator, ellipsize(s.name, 20), state, ellipsize(s.container, 20))
+		line += helpStyle.Render(fmt.Sprintf("  %s  (%s)", ellipsize(s.command, 30), formatTime(s.created)))
+		sb.WriteString(cursor + line + "\n")
+	}
+	if len(m.sessions) == 0 {
+		sb.WriteString(helpStyle.Render("  No sessions; press n to start one") + "\n")
+	}
+
+	// Status line
+	sb.WriteString("\n")
+	switch {
+	case m.creating:
+		sb.WriteString(searchBarStyle.Render("New session: ") + m.newInput.View())
+	case m.confirmKill != "":
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("Kill session %s? (y/n)", m.confirmKill)))
+	case m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, m.err)))
+	case m.status != "":
+		sb.WriteString(selectedStyle.Render(m.status))
+	default:
+		sb.WriteString(helpStyle.Render(fmt.Sprintf("%d sessions; detach with ctrl+b d to come back here", len(m.sessions))))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(helpStyle.Render("enter: attach | n: new | x: kill | r: refresh | q: quit"))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// LaunchSessionsTUI opens the Sessions panel
+func LaunchSessionsTUI() error {
+	ti := textinput.New()
+	ti.Placeholder = "NAME CONTAINER [COMMAND...]"
+	ti.CharLimit = 200
+	ti.Width = 50
+
+	p := newProgram(sessionsModel{newInput: ti}, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// shellCandidates are tried in order; the first that exists in the container
+// is the shell that opens
+var shellCandidates = []string{"/bin/bash", "/usr/bin/bash", "/bin/ash", "/bin/sh"}
+
+// shellMatch is a running container whose name matched the pattern
+type shellMatch struct {
+	id    string
+	name  string
+	image string
+	exact bool // the letters of the pattern in a row, not just in order
+	score int
+}
+
+// OpenShell execs an interactive shell in the running container whose name
+// matches a partial name, asking which one when several do
+func OpenShell(args []string) {
+	var user, pattern string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-u" || arg == "--user") && i+1 < len(args):
+			i++
+			user = args[i]
+		case strings.HasPrefix(arg, "--user="):
+			user = strings.TrimPrefix(arg, "--user=")
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			fmt.Println("Usage: dockit sh [-u USER] NAME")
+			os.Exit(1)
+		case pattern == "":
+			pattern = arg
+		}
+	}
+	if pattern == "" {
+		fmt.Fprintf(os.Stderr, "Error: container name required\n")
+		fmt.Println("Usage: dockit sh [-u USER] NAME")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	containers, err := cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+
+	matches := matchContainers(pattern, containers)
+	var target shellMatch
+	switch {
+	case len(matches) == 0:
+		fmt.Fprintf(os.Stderr, "Error: no running container matches %q\n", pattern)
+		os.Exit(1)
+	case len(matches) == 1:
+		target = matches[0]
+	case !isTerminal(os.Stdin):
+		fmt.Fprintf(os.Stderr, "Error: %q matches %d containers:\n", pattern, len(matches))
+		for _, match := range matches {
+			fmt.Fprintf(os.Stderr, "  %s\n", match.name)
+		}
+		os.Exit(1)
+	default:
+		target = promptShellMatch(bufio.NewReader(os.Stdin), matches)
+	}
+
+	shell, err := detectShell(ctx, cli, target.id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	gray.Fprintf(os.Stderr, "%s in %s\n", shell, target.name)
+
+	dockerArgs := []string{"exec", "-i"}
+	if isTerminal(os.Stdin) {
+		dockerArgs[1] = "-it"
+	}
+	if user != "" {
+		dockerArgs = append(dockerArgs, "--user", user)
+	}
+	cmd := DockerCommand(append(dockerArgs, target.id, shell)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	os.Exit(runCommand(cmd))
+}
+
+// matchContainers returns the containers matching pattern, best first. An
+// exact name or ID prefix wins outright; otherwise names containing the
+// pattern are offered, or failing that names with its letters in order.
+// Earlier, tighter matches and shorter names sort first.
+func matchContainers(pattern string, containers []container.Summary) []shellMatch {
+	pattern = strings.ToLower(pattern)
+	var matches []shellMatch
+	anyExact := false
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		lower := strings.ToLower(name)
+		match := shellMatch{id: c.ID, name: name, image: c.Image}
+
+		// Short patterns are names, not ID prefixes every container might share
+		if lower == pattern || (len(pattern) >= 4 && strings.HasPrefix(c.ID, pattern)) {
+			return []shellMatch{match}
+		}
+		if index := strings.Index(lower, pattern); index >= 0 {
+			match.exact = true
+			match.score = index
+			anyExact = true
+		} else if span, ok := subsequenceSpan(pattern, lower); ok {
+			match.score = span
+		} else {
+			continue
+		}
+		matches = append(matches, match)
+	}
+
+	if anyExact {
+		matches = slices.DeleteFunc(matches, func(match shellMatch) bool { return !match.exact })
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		if len(a.name) != len(b.name) {
+			return len(a.name) < len(b.name)
+		}
+		return a.name < b.name
+	})
+	return matches
+}
+
+// subsequenceSpan reports whether the letters of pattern appear in name in
+// order, and how many characters lie between the first and last of them
+func subsequenceSpan(pattern, name string) (int, bool) {
+	start, next := -1, 0
+	for i := 0; i < len(name) && next < len(pattern); i++ {
+		if name[i] != pattern[next] {
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		next++
+		if next == len(pattern) {
+			return i - start + 1 - len(pattern), true
+		}
+	}
+	return 0, false
+}
+
+// promptShellMatch lists the matches and asks which container to open
+func promptShellMatch(reader *bufio.Reader, matches []shellMatch) shellMatch {
+	cyan.Printf("Several running containers match:\n")
+	for i, match := range matches {
+		fmt.Printf("  %d) %s ", i+1, match.name)
+		gray.Printf("(%s, %s)\n", match.image, formatID(match.id, false))
+	}
+
+	for {
+		answer := prompt(reader, "Container (number or name): ")
+		if answer == "" {
+			os.Exit(1)
+		}
+		var index int
+		if _, err := fmt.Sscanf(answer, "%d", &index); err == nil && index >= 1 && index <= len(matches) {
+			return matches[index-1]
+		}
+		for _, match := range matches {
+			if match.name == answer {
+				return match
+			}
+		}
+	}
+}
+
+// detectShell returns the first of shellCandidates the container has,
+// checked through the archive API so no shell is needed to look
+func detectShell(ctx context.Context, cli *client.Client, id string) (string, error) {
+	for _, shell := range shellCandidates {
+		if _, err := cli.ContainerStatPath(ctx, id, shell); err == nil {
+			return shell, nil
+		}
+	}
+	return "", fmt.Errorf("no bash, ash, or sh found in the container")
+}
@@ -0,0 +1,81 @@
+package pretty
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// imageIDPattern matches image IDs, which Docker reports in place of the image
+// name once a container's tag has moved to a different image
+var imageIDPattern = regexp.MustCompile(`^(sha256:)?[0-9a-f]{12,64}$`)
+
+// imageIndex records which images exist and which image each tag points to
+type imageIndex struct {
+	ids  map[string]bool
+	tags map[string]string
+}
+
+// loadImageIndex builds an imageIndex from the local image list
+func loadImageIndex(ctx context.Context, cli *client.Client) (imageIndex, error) {
+	images, err := cli.ImageList(ctx, image.ListOptions{All: true})
+	if err != nil {
+		return imageIndex{}, err
+	}
+
+	index := imageIndex{ids: map[string]bool{}, tags: map[string]string{}}
+	for _, img := range images {
+		index.ids[img.ID] = true
+		for _, tag := range img.RepoTags {
+			if ref := normalizeTag(tag); ref != "" {
+				index.tags[ref] = img.ID
+			}
+		}
+	}
+	return index, nil
+}
+
+// normalizeTag returns the fully qualified form of an image tag, or "" if
+// it isn't a valid reference
+func normalizeTag(tag string) string {
+	named, err := reference.ParseNormalizedNamed(tag)
+	if err != nil {
+		return ""
+	}
+	return reference.TagNameOnly(named).String()
+}
+
+// staleReason explains why a container runs an outdated image, or returns ""
+// if it is current
+func (index imageIndex) staleReason(ctx context.Context, cli *client.Client, c container.Summary) string {
+	ref := c.Image
+	if imageIDPattern.MatchString(ref) {
+		// The list only shows the ID; the configured tag is in the container config
+		info, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil || info.Config == nil || imageIDPattern.MatchString(info.Config.Image) {
+			return ""
+		}
+		ref = info.Config.Image
+	}
+
+	if !index.ids[c.ImageID] {
+		return "image removed since the container was created"
+	}
+	if strings.Contains(ref, "@") {
+		// Pinned by digest, so the image can't have moved
+		return ""
+	}
+	current, ok := index.tags[normalizeTag(ref)]
+	switch {
+	case !ok:
+		return ref + " no longer exists locally"
+	case current != c.ImageID:
+		return ref + " now points to a newer image"
+	}
+	return ""
+}
@@ -0,0 +1,263 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"gopkg.in/yaml.v3"
+)
+
+// startHistoryLimit is how many timed starts are kept per container
+const startHistoryLimit = 20
+
+// startTiming is one measured cold start; healthy is zero when the
+// container has no healthcheck
+type startTiming struct {
+	At      time.Time     `yaml:"at"`
+	Running time.Duration `yaml:"running"`
+	Healthy time.Duration `yaml:"healthy,omitempty"`
+}
+
+// startHistoryPath is where timed starts are kept, next to the config file
+func startHistoryPath() string {
+	path := ConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "start-times.yaml")
+}
+
+func readStartHistory() (map[string][]startTiming, error) {
+	history := map[string][]startTiming{}
+	data, err := os.ReadFile(startHistoryPath())
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return history, err
+	}
+	if err := yaml.Unmarshal(data, &history); err != nil {
+		return history, fmt.Errorf("error parsing %s: %v", startHistoryPath(), err)
+	}
+	if history == nil {
+		history = map[string][]startTiming{}
+	}
+	return history, nil
+}
+
+func writeStartHistory(history map[string][]startTiming) error {
+	path := startHistoryPath()
+	if path == "" {
+		return fmt.Errorf("could not determine config location")
+	}
+	data, err := yaml.Marshal(history)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// TimeStart starts stopped containers one at a time, measuring how long
+// each takes to be running and, with a healthcheck, healthy; each result is
+// compared with the container's earlier starts
+func TimeStart(args []string) {
+	timeout := 2 * time.Minute
+	var names []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--time":
+		case arg == "--timeout" && i+1 < len(args):
+			i++
+			d, err := time.ParseDuration(args[i])
+			if err != nil || d <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: invalid timeout %q\n", args[i])
+				os.Exit(1)
+			}
+			timeout = d
+		case !strings.HasPrefix(arg, "-"):
+			names = append(names, arg)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %q\n", arg)
+			fmt.Println("Usage: dockit start --time [--timeout DURATION] CONTAINER...")
+			os.Exit(1)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit start --time [--timeout DURATION] CONTAINER...")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	history, err := readStartHistory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	fmt.Println()
+	cyan.Println("TIMED START")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	failed := false
+	for _, name := range names {
+		fmt.Printf("%s ", name)
+		key, timing, err := timeStart(ctx, cli, name, timeout)
+		if err != nil {
+			red.Print(glyphs.failed + " ")
+			red.Println(err)
+			failed = true
+			continue
+		}
+
+		green.Print(glyphs.ok + " ")
+		fmt.Printf("running in %s", formatStartDuration(timing.Running))
+		if timing.Healthy > 0 {
+			fmt.Printf(", healthy in %s", formatStartDuration(timing.Healthy))
+		}
+		fmt.Println()
+
+		previous := history[key]
+		printStartComparison(previous, timing)
+
+		history[key] = append(previous, timing)
+		if len(history[key]) > startHistoryLimit {
+			history[key] = history[key][len(history[key])-startHistoryLimit:]
+		}
+	}
+
+	if err := writeStartHistory(history); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving start times: %v\n", err)
+		os.Exit(1)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// timeStart starts one stopped container and polls until it is running, then
+// healthy if it has a healthcheck. It returns the container's name, which
+// keys its history.
+func timeStart(ctx context.Context, cli *client.Client, name string, timeout time.Duration) (string, startTiming, error) {
+	var timing startTiming
+	info, err := cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return name, timing, err
+	}
+	key := strings.TrimPrefix(info.Name, "/")
+	if info.State != nil && (info.State.Running || info.State.Paused) {
+		return key, timing, fmt.Errorf("already running; stop it first to time a cold start")
+	}
+	hasHealthcheck := info.Config != nil && info.Config.Healthcheck != nil &&
+		len(info.Config.Healthcheck.Test) > 0 && info.Config.Healthcheck.Test[0] != "NONE"
+
+	timing.At = time.Now()
+	if err := cli.ContainerStart(ctx, info.ID, container.StartOptions{}); err != nil {
+		return key, timing, err
+	}
+
+	deadline := timing.At.Add(timeout)
+	for {
+		info, err := cli.ContainerInspect(ctx, info.ID)
+		if err != nil {
+			return key, timing, err
+		}
+		elapsed := time.Since(timing.At)
+		state := info.State
+
+		switch {
+		case state == nil:
+		case !state.Running && !state.Restarting:
+			return key, timing, fmt.Errorf("exited with code %d after %s", state.ExitCode, formatStartDuration(elapsed))
+		case state.Running && timing.Running == 0:
+			timing.Running = elapsed
+		}
+
+		if timing.Running > 0 {
+			switch {
+			case !hasHealthcheck || state.Health == nil:
+				return key, timing, nil
+			case state.Health.Status == "healthy":
+				timing.Healthy = elapsed
+				return key, timing, nil
+			case state.Health.Status == "unhealthy":
+				return key, timing, fmt.Errorf("unhealthy after %s (see 'dockit health %s')", formatStartDuration(elapsed), key)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return key, timing, fmt.Errorf("not ready after %s", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// printStartComparison shows this start against the median of the earlier
+// ones, with a sparkline of the history, warning when it got much slower
+func printStartComparison(previous []startTiming, timing startTiming) {
+	if len(previous) == 0 {
+		gray.Printf("  %s First timed start; later starts are compared with this one\n", glyphs.detail)
+		return
+	}
+
+	// Compare what the user waits for: healthy when there is a healthcheck
+	measure := func(t startTiming) time.Duration {
+		if t.Healthy > 0 {
+			return t.Healthy
+		}
+		return t.Running
+	}
+
+	var durations []time.Duration
+	var series []float64
+	for _, t := range previous {
+		durations = append(durations, measure(t))
+		series = append(series, measure(t).Seconds())
+	}
+	series = append(series, measure(timing).Seconds())
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	median := durations[len(durations)/2]
+
+	change := 0
+	if median > 0 {
+		change = int(math.Round((float64(measure(timing)) - float64(median)) / float64(median) * 100))
+	}
+
+	gray.Printf("  %s %s ", glyphs.detail, renderSparkline(series, len(series), 0))
+	gray.Printf("median of last %d: %s, ", len(previous), formatStartDuration(median))
+	switch {
+	case change >= 50:
+		yellow.Printf("%s %+d%% slower than usual\n", glyphs.warn, change)
+	case change <= -10:
+		green.Printf("%+d%%\n", change)
+	default:
+		gray.Printf("%+d%%\n", change)
+	}
+}
+
+func formatStartDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,113 @@
+package pretty
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// searchHistoryLimit caps how many logs searches are remembered
+const searchHistoryLimit = 50
+
+// tuiState is what dockit remembers between runs so it reopens where it
+// was left: the last interactive view, each picker's cursor row and sort,
+// and the logs searches
+type tuiState struct {
+	LastView []string          `json:"last_view,omitempty"` // dockit arguments that opened it
+	Selected map[string]string `json:"selected,omitempty"`  // picker kind to the ID under the cursor
+	Sort     map[string]int    `json:"sort,omitempty"`      // picker kind to its < and > step
+	Searches []string          `json:"searches,omitempty"`  // oldest first
+}
+
+// currentView is the command line of this run, remembered as the last
+// view when an interactive view it opened is quit
+var currentView []string
+
+// SetView records the dockit arguments of this run
+func SetView(args []string) {
+	currentView = slices.Clone(args)
+}
+
+// LastView returns the arguments of the last interactive view that was
+// quit, or nil
+func LastView() []string {
+	return loadState().LastView
+}
+
+// statePath returns where the state is kept: $XDG_STATE_HOME/dockit, or
+// ~/.local/state/dockit
+func statePath() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "dockit", "state.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "dockit", "state.json")
+}
+
+// loadState reads the saved state; a missing or unreadable file is an empty
+// state, since nothing depends on it
+func loadState() tuiState {
+	var state tuiState
+	path := statePath()
+	if path == "" {
+		return state
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// updateState applies change to the saved state and writes it back. The
+// state is a convenience, so failing to save it is not reported.
+func updateState(change func(*tuiState)) {
+	path := statePath()
+	if path == "" {
+		return
+	}
+	state := loadState()
+	change(&state)
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	// Each save writes its own temp file and renames it over the state, so
+	// two dockits quitting at once each leave a whole file and the last wins
+	temp, err := os.CreateTemp(filepath.Dir(path), "state-*.json")
+	if err != nil {
+		return
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+	}
+}
+
+// rememberView saves this run's command line as the view to reopen
+func rememberView(state *tuiState) {
+	if len(currentView) > 0 {
+		state.LastView = currentView
+	}
+}
+
+// addSearch appends a search to the history, moving a repeat to the end
+func addSearch(state *tuiState, search string) {
+	state.Searches = slices.DeleteFunc(state.Searches, func(s string) bool { return s == search })
+	state.Searches = append(state.Searches, search)
+	if len(state.Searches) > searchHistoryLimit {
+		state.Searches = state.Searches[len(state.Searches)-searchHistoryLimit:]
+	}
+}
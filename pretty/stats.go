@@ -0,0 +1,160 @@
+package pretty
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// statsStream delivers a container's stats samples, about one a second,
+// until the container stops or ctx is cancelled
+type statsStream struct {
+	samples <-chan container.StatsResponse
+	errs    <-chan error
+}
+
+func startStatsStream(ctx context.Context, cli *client.Client, containerID string) (*statsStream, error) {
+	resp, err := cli.ContainerStats(ctx, containerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make(chan container.StatsResponse)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(samples)
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		var previous *container.StatsResponse
+		for {
+			var sample container.StatsResponse
+			if err := decoder.Decode(&sample); err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+			normalizeStats(&sample, previous)
+			previous = &sample
+			select {
+			case samples <- sample:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Closing the body unblocks a decoder waiting on the stream
+	go func() {
+		<-ctx.Done()
+		resp.Body.Close()
+	}()
+
+	return &statsStream{samples: samples, errs: errs}, nil
+}
+
+// unlimitedMemory is the smallest limit taken to mean no limit: cgroup v1
+// reports an unset limit as a page-aligned 2^63, which overflows sizes
+const unlimitedMemory = 1 << 62
+
+// normalizeStats fills in what some daemons leave out of a stats sample, so
+// the CPU and memory math doesn't read missing fields as zero. Older daemons
+// and Podman stream samples without precpu_stats or online_cpus, cgroup v2
+// hosts have no per-CPU usage, and Podman may report memory only in the
+// detailed stats. previous is the stream's last sample, nil for the first
+// one or a one-shot sample.
+func normalizeStats(s *container.StatsResponse, previous *container.StatsResponse) {
+	if previous != nil && s.PreCPUStats.CPUUsage.TotalUsage == 0 && s.PreCPUStats.SystemUsage == 0 {
+		s.PreCPUStats = previous.CPUStats
+	}
+	if previous != nil && s.PreRead.IsZero() {
+		s.PreRead = previous.Read
+	}
+
+	if s.CPUStats.OnlineCPUs == 0 {
+		s.CPUStats.OnlineCPUs = uint32(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if s.CPUStats.OnlineCPUs == 0 {
+		s.CPUStats.OnlineCPUs = s.PreCPUStats.OnlineCPUs
+	}
+
+	if s.MemoryStats.Usage == 0 {
+		// cgroup v2 splits usage into anon and file, v1 into rss and cache
+		if anon, ok := s.MemoryStats.Stats["anon"]; ok {
+			s.MemoryStats.Usage = anon + s.MemoryStats.Stats["file"]
+		} else if rss, ok := s.MemoryStats.Stats["rss"]; ok {
+			s.MemoryStats.Usage = rss + s.MemoryStats.Stats["cache"]
+		}
+	}
+	if s.MemoryStats.Limit >= unlimitedMemory {
+		s.MemoryStats.Limit = 0
+	}
+}
+
+// cpuPercent is the CPU used since the previous sample, where 100% is one
+// full core, like docker stats. Without the host's CPU time or CPU count it
+// falls back to the wall clock time between the samples.
+func cpuPercent(s container.StatsResponse) float64 {
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	if cpuDelta <= 0 {
+		return 0
+	}
+
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if systemDelta > 0 && cpus > 0 {
+		return cpuDelta / systemDelta * cpus * 100
+	}
+
+	// Container CPU time is in nanoseconds, like the gap between reads
+	elapsed := s.Read.Sub(s.PreRead)
+	if s.PreRead.IsZero() || elapsed <= 0 {
+		return 0
+	}
+	return cpuDelta / float64(elapsed.Nanoseconds()) * 100
+}
+
+// memoryUsage returns the memory in use, without the page cache the kernel
+// can reclaim, and the limit, like docker stats; the limit is zero when
+// there is none
+func memoryUsage(s container.StatsResponse) (used, limit uint64) {
+	used = s.MemoryStats.Usage
+	// cgroup v2 reports inactive_file, v1 reports total_inactive_file
+	for _, key := range []string{"inactive_file", "total_inactive_file"} {
+		if cache, ok := s.MemoryStats.Stats[key]; ok && cache < used {
+			used -= cache
+			break
+		}
+	}
+	return used, s.MemoryStats.Limit
+}
+
+// renderSparkline draws the last width values scaled to ceiling, or to the
+// largest value when that is higher
+func renderSparkline(values []float64, width int, ceiling float64) string {
+	levels := []rune(glyphs.spark)
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	for _, v := range values {
+		if v > ceiling {
+			ceiling = v
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if ceiling > 0 {
+			level = int(v / ceiling * float64(len(levels)-1))
+		}
+		sb.WriteRune(levels[min(max(level, 0), len(levels)-1)])
+	}
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package pretty
+
+import (
+	"os"
+	"strings"
+)
+
+// runValueFlags are docker run flags that take a separate value argument
+var runValueFlags = map[string]bool{
+	"-a": true, "--attach": true, "--detach-keys": true,
+	"-c": true, "--cpu-shares": true, "--cpus": true, "--cpuset-cpus": true, "--cpuset-mems": true,
+	"--cpu-period": true, "--cpu-quota": true, "--cpu-rt-period": true, "--cpu-rt-runtime": true,
+	"-e": true, "--env": true, "--env-file": true,
+	"-h": true, "--hostname": true, "--domainname": true,
+	"-l": true, "--label": true, "--label-file": true, "--annotation": true,
+	"-m": true, "--memory": true, "--memory-reservation": true, "--memory-swap": true,
+	"--memory-swappiness": true, "--kernel-memory": true, "--shm-size": true, "--oom-score-adj": true,
+	"-p": true, "--publish": true, "--expose": true,
+	"-u": true, "--user": true, "--group-add": true, "--userns": true,
+	"-v": true, "--volume": true, "--volume-driver": true, "--volumes-from": true, "--mount": true, "--tmpfs": true,
+	"-w": true, "--workdir": true, "--entrypoint": true, "--name": true, "--cidfile": true,
+	"--add-host": true, "--dns": true, "--dns-option": true, "--dns-search": true, "--mac-address": true,
+	"--net": true, "--net-alias": true, "--network": true, "--network-alias": true,
+	"--ip": true, "--ip6": true, "--link": true, "--link-local-ip": true,
+	"--blkio-weight": true, "--blkio-weight-device": true,
+	"--device": true, "--device-cgroup-rule": true, "--gpus": true,
+	"--device-read-bps": true, "--device-read-iops": true, "--device-write-bps": true, "--device-write-iops": true,
+	"--cap-add": true, "--cap-drop": true, "--security-opt": true, "--sysctl": true, "--ulimit": true,
+	"--cgroup-parent": true, "--cgroupns": true, "--ipc": true, "--pid": true, "--pids-limit": true, "--uts": true,
+	"--health-cmd": true, "--health-interval": true, "--health-retries": true,
+	"--health-start-interval": true, "--health-start-period": true, "--health-timeout": true,
+	"--log-driver": true, "--log-opt": true, "--storage-opt": true, "--isolation": true, "--runtime": true,
+	"--platform": true, "--pull": true, "--restart": true, "--stop-signal": true, "--stop-timeout": true,
+}
+
+// stdinIsPiped reports whether stdin is a pipe or redirected file rather than
+// a terminal, e.g. `cat data.sql | dockit exec db psql`
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
+}
+
+// hasShortFlag reports whether flags contain the given short flag, alone or
+// combined with others (-i, -it, -ti)
+func hasShortFlag(flags []string, short byte, long string) bool {
+	for _, flag := range flags {
+		if flag == long {
+			return true
+		}
+		if len(flag) > 1 && flag[0] == '-' && flag[1] != '-' && strings.IndexByte(flag[1:], short) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// pipeStdinFlags adjusts docker exec/run options so piped stdin reaches the
+// container: -i is added so docker attaches stdin and closes the container's
+// stdin at EOF, and -t is dropped since a pipe is not a TTY
+func pipeStdinFlags(flags []string) (adjusted []string, notes []string) {
+	if !stdinIsPiped() || hasShortFlag(flags, 'd', "--detach") {
+		return flags, nil
+	}
+
+	for _, flag := range flags {
+		switch {
+		case flag == "-t" || flag == "--tty":
+			notes = append(notes, "stdin is piped, ignoring "+flag)
+			continue
+		case len(flag) > 2 && flag[0] == '-' && flag[1] != '-' && strings.IndexByte(flag, 't') > 0:
+			// Combined short flags such as -it
+			notes = append(notes, "stdin is piped, ignoring -t")
+			flag = strings.ReplaceAll(flag, "t", "")
+		}
+		adjusted = append(adjusted, flag)
+	}
+
+	if !hasShortFlag(adjusted, 'i', "--interactive") {
+		adjusted = append(adjusted, "-i")
+		notes = append(notes, "stdin is piped, adding -i")
+	}
+	return adjusted, notes
+}
+
+// WarnUnattachedStdin warns when stdin is piped into a pass-through docker
+// run without -i, since docker would silently ignore the input
+func WarnUnattachedStdin(args []string) {
+	if len(args) == 0 || args[0] != "run" || !stdinIsPiped() {
+		return
+	}
+
+	// Only the options before the image count; after it come the
+	// container's command and its own flags, like grep -i
+	var flags []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+		flags = append(flags, arg)
+		if runValueFlags[arg] {
+			i++
+		}
+	}
+	if !hasShortFlag(flags, 'i', "--interactive") && !hasShortFlag(flags, 'd', "--detach") {
+		yellow.Fprintf(os.Stderr, "%s stdin is piped but -i was not given; docker run will not read it\n", glyphs.warn)
+	}
+}
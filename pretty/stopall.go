@@ -0,0 +1,450 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// defaultStopGrace is how long the daemon waits after the stop signal before
+// killing a container that sets no stop timeout of its own
+const defaultStopGrace = 10 * time.Second
+
+// stopTarget is one container being stopped and what became of it
+type stopTarget struct {
+	id       string
+	name     string
+	signal   string
+	grace    time.Duration
+	timeout  *int      // passed to the daemon; nil leaves it to the container's own
+	started  time.Time // zero until its stop is sent
+	finished time.Time
+	exitCode int
+	killed   bool // still running when the grace period ran out
+	skipped  bool // not running to begin with
+	err      error
+}
+
+func (t *stopTarget) elapsed(now time.Time) time.Duration {
+	if !t.finished.IsZero() {
+		return t.finished.Sub(t.started)
+	}
+	return now.Sub(t.started)
+}
+
+type stopModel struct {
+	ctx      context.Context
+	cli      *client.Client
+	title    string
+	targets  []*stopTarget
+	parallel int // how many stops run at once, in order; 0 for all of them
+	next     int // index of the next target to send a stop to
+	now      time.Time
+}
+
+type stopTickMsg time.Time
+
+// stopBeginMsg sends the first stops from Update, so the model keeps track
+// of what was sent
+type stopBeginMsg struct{}
+
+// stopDoneMsg reports one container's stop call returning
+type stopDoneMsg struct {
+	index    int
+	exitCode int
+	err      error
+}
+
+// configStopTimeout is stop_timeout from the config in seconds, or nil when
+// it is unset and the daemon's default applies
+func configStopTimeout() *int {
+	if config.Defaults.StopTimeout == "" {
+		return nil
+	}
+	seconds, err := parseStopTimeout(config.Defaults.StopTimeout)
+	if err != nil {
+		return nil
+	}
+	return &seconds
+}
+
+// stopTimeoutFor is the timeout to stop a container with: nil for one with
+// a --stop-timeout of its own, which the daemon applies, otherwise the
+// config's stop_timeout
+func stopTimeoutFor(cfg *container.Config) *int {
+	if cfg != nil && cfg.StopTimeout != nil {
+		return nil
+	}
+	return configStopTimeout()
+}
+
+// parseStopTimeout reads a stop timeout: a duration like 45s or 2m, or a
+// number of seconds. The daemon takes whole seconds, so it rounds up.
+func parseStopTimeout(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if seconds, err := strconv.Atoi(text); err == nil && seconds >= 0 {
+		return seconds, nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid stop timeout %q (use a duration like 30s or 2m)", text)
+	}
+	return int(math.Ceil(d.Seconds())), nil
+}
+
+// stopContainers stops the named containers, showing a live list with each
+// one's stop signal and how much of its grace period has passed, so the
+// ones that ignore the signal and get killed stand out. parallel caps how
+// many stop at once, 1 stopping them one at a time in order and 0 all at
+// once. A non-nil timeout overrides every container's own. It returns the
+// targets with their results.
+func stopContainers(ctx context.Context, cli *client.Client, title string, names []string, parallel int, timeout *int) []*stopTarget {
+	var targets []*stopTarget
+	for _, name := range names {
+		target := &stopTarget{id: name, name: name, signal: "SIGTERM", grace: defaultStopGrace}
+		info, err := cli.ContainerInspect(ctx, name)
+		if err != nil {
+			target.err = err
+			targets = append(targets, target)
+			continue
+		}
+		target.id = info.ID
+		target.name = strings.TrimPrefix(info.Name, "/")
+		if info.Config != nil {
+			if info.Config.StopSignal != "" {
+				target.signal = info.Config.StopSignal
+			}
+			if info.Config.StopTimeout != nil {
+				target.grace = time.Duration(*info.Config.StopTimeout) * time.Second
+			}
+		}
+		target.timeout = timeout
+		if timeout == nil {
+			target.timeout = stopTimeoutFor(info.Config)
+		}
+		if target.timeout != nil {
+			target.grace = time.Duration(*target.timeout) * time.Second
+		}
+		target.skipped = info.State == nil || !info.State.Running && !info.State.Paused && !info.State.Restarting
+		targets = append(targets, target)
+	}
+
+	model := stopModel{ctx: ctx, cli: cli, title: title, targets: targets, parallel: parallel, now: time.Now()}
+	if !isTerminal(os.Stdout) {
+		// Without a terminal, run the same stops and report once at the end
+		for !model.finished() {
+			cmds := model.sendStops()
+			results := make(chan tea.Msg, len(cmds))
+			for _, cmd := range cmds {
+				go func() { results <- cmd() }()
+			}
+			for range cmds {
+				model.record((<-results).(stopDoneMsg))
+			}
+		}
+		return targets
+	}
+
+	if _, err := newProgram(model).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+	return targets
+}
+
+func (m stopModel) Init() tea.Cmd {
+	return func() tea.Msg { return stopBeginMsg{} }
+}
+
+func (m stopModel) tick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return stopTickMsg(t) })
+}
+
+// sendStops starts the stops that are due: every pending one, or the next
+// in line while fewer than parallel are running
+func (m *stopModel) sendStops() []tea.Cmd {
+	var cmds []tea.Cmd
+	for m.next < len(m.targets) {
+		if m.parallel > 0 && m.inFlight() >= m.parallel {
+			break
+		}
+		index := m.next
+		target := m.targets[index]
+		m.next++
+		if target.err != nil || target.skipped {
+			continue
+		}
+
+		target.started = time.Now()
+		ctx, cli, id, timeout := m.ctx, m.cli, target.id, target.timeout
+		cmds = append(cmds, func() tea.Msg {
+			// The signal is left to the container's own
+			if err := cli.ContainerStop(ctx, id, container.StopOptions{Timeout: timeout}); err != nil {
+				return stopDoneMsg{index: index, err: err}
+			}
+			done := stopDoneMsg{index: index}
+			if info, err := cli.ContainerInspect(ctx, id); err == nil && info.State != nil {
+				done.exitCode = info.State.ExitCode
+			}
+			return done
+		})
+	}
+	return cmds
+}
+
+// inFlight counts the stops sent that haven't returned
+func (m *stopModel) inFlight() int {
+	n := 0
+	for _, target := range m.targets {
+		if !target.started.IsZero() && target.finished.IsZero() {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *stopModel) finished() bool {
+	return m.next >= len(m.targets) && m.inFlight() == 0
+}
+
+func (m *stopModel) record(msg stopDoneMsg) {
+	target := m.targets[msg.index]
+	target.finished = time.Now()
+	target.err = msg.err
+	target.exitCode = msg.exitCode
+	// Docker reports SIGKILL as exit code 137; only count it when the
+	// grace period really ran out, since a process can exit 137 by itself
+	target.killed = msg.err == nil && msg.exitCode == 137 && target.elapsed(target.finished) >= target.grace
+}
+
+func (m stopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case stopBeginMsg:
+		cmds := m.sendStops()
+		if m.finished() {
+			return m, tea.Quit
+		}
+		return m, tea.Batch(append(cmds, m.tick())...)
+
+	case stopTickMsg:
+		m.now = time.Time(msg)
+		return m, m.tick()
+
+	case stopDoneMsg:
+		m.record(msg)
+		cmds := m.sendStops()
+		if m.finished() {
+			m.now = time.Now()
+			return m, tea.Quit
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		// Stops already sent keep going in the daemon either way
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m stopModel) View() string {
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(m.title))
+	sb.WriteString("\n")
+
+	for _, target := range m.targets {
+		sb.WriteString(fmt.Sprintf("%-28s %-8s ", ellipsize(target.name, 28), ellipsize(target.signal, 8)))
+
+		elapsed := target.elapsed(m.now)
+		fraction := 1.0
+		if target.grace > 0 {
+			fraction = float64(elapsed) / float64(target.grace)
+		}
+		switch {
+		case target.err != nil:
+			sb.WriteString(errorStyle.Render(fmt.Sprintf("%s %v", glyphs.failed, target.err)))
+		case target.skipped:
+			sb.WriteString(helpStyle.Render("not running"))
+		case target.started.IsZero():
+			sb.WriteString(helpStyle.Render("waiting"))
+		case !target.finished.IsZero():
+			sb.WriteString(renderProgressBar(fraction, 20, true))
+			if target.killed {
+				sb.WriteString(errorStyle.Render(fmt.Sprintf(" %s killed after %s grace", glyphs.warn, target.grace)))
+			} else {
+				sb.WriteString(progressDoneStyle.Render(fmt.Sprintf(" %s stopped in %s", glyphs.ok, formatStartDuration(elapsed))))
+			}
+		default:
+			sb.WriteString(renderProgressBar(fraction, 20, false))
+			sb.WriteString(fmt.Sprintf(" %4.1fs / %s", elapsed.Seconds(), target.grace))
+			// Past three quarters of the grace period the signal is likely ignored
+			if fraction >= 0.75 {
+				remaining := target.grace - elapsed
+				if remaining < 0 {
+					remaining = 0
+				}
+				sb.WriteString(errorStyle.Render(fmt.Sprintf("  ignoring %s, kill in %.0fs", target.signal, remaining.Seconds())))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// printStopSummary totals the results and names the containers that had to
+// be killed, which is where slow shutdowns hide; it reports whether any
+// stop failed
+func printStopSummary(targets []*stopTarget) bool {
+	stopped, failed := 0, 0
+	var killed []string
+	for _, target := range targets {
+		switch {
+		case target.err != nil:
+			failed++
+			// The live list shows errors; print them when there was no list
+			if !isTerminal(os.Stdout) {
+				red.Printf("%s %s: %v\n", glyphs.failed, target.name, target.err)
+			}
+		case target.skipped:
+		case target.killed:
+			killed = append(killed, fmt.Sprintf("%s (%s)", target.name, target.signal))
+		default:
+			stopped++
+			if !isTerminal(os.Stdout) {
+				green.Print(glyphs.ok + " ")
+				gray.Printf("%s stopped in %s\n", target.name, formatStartDuration(target.finished.Sub(target.started)))
+			}
+		}
+	}
+
+	fmt.Println()
+	green.Printf("%d stopped", stopped)
+	if len(killed) > 0 {
+		yellow.Printf(", %d killed", len(killed))
+	}
+	if failed > 0 {
+		red.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+
+	if len(killed) > 0 {
+		yellow.Printf("%s Killed after ignoring their stop signal: %s\n", glyphs.warn, strings.Join(killed, ", "))
+		gray.Printf("  %s Handle the signal in the process, or give it longer with --stop-timeout or stop_timeout in the config\n", glyphs.detail)
+	}
+	return failed > 0
+}
+
+// stopSelectedContainers is the containers picker's stop action
+func stopSelectedContainers(ctx context.Context, cli *client.Client, items []bulkItem, inOrder bool) error {
+	return stopItems(ctx, cli, items, nil, inOrder)
+}
+
+// stopSelectedWithTimeout is the containers picker's stop action for slow
+// shutdowns: it asks how long to give them before they are killed
+func stopSelectedWithTimeout(ctx context.Context, cli *client.Client, items []bulkItem, inOrder bool) error {
+	reader := bufio.NewReader(os.Stdin)
+	suggested := "10s"
+	if config.Defaults.StopTimeout != "" {
+		suggested = config.Defaults.StopTimeout
+	}
+	target := fmt.Sprintf("%d containers", len(items))
+	if len(items) == 1 {
+		target = items[0].name
+	}
+	for {
+		answer := prompt(reader, fmt.Sprintf("Stop timeout for %s [%s]: ", target, suggested))
+		if answer == "" {
+			answer = suggested
+		}
+		seconds, err := parseStopTimeout(answer)
+		if err != nil {
+			red.Printf("%s %v\n", glyphs.failed, err)
+			continue
+		}
+		return stopItems(ctx, cli, items, &seconds, inOrder)
+	}
+}
+
+// stopItems stops the picker's rows with the live stop list, all at once, or
+// one at a time when inOrder is set
+func stopItems(ctx context.Context, cli *client.Client, items []bulkItem, timeout *int, inOrder bool) error {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.id)
+	}
+
+	fmt.Println()
+	title := fmt.Sprintf("STOPPING %d CONTAINERS", len(ids))
+	parallel := 0
+	if inOrder {
+		title += " IN ORDER"
+		parallel = 1
+	}
+	targets := stopContainers(ctx, cli, title, ids, parallel, timeout)
+	if printStopSummary(targets) {
+		return fmt.Errorf("some containers did not stop")
+	}
+	return nil
+}
+
+// StopAll stops every running container at once with the live stop list
+func StopAll(args []string) {
+	var timeout *int
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--all" || arg == "-a":
+		case (arg == "-t" || arg == "--timeout") && i+1 < len(args):
+			i++
+			seconds, err := parseStopTimeout(args[i])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			timeout = &seconds
+		default:
+			fmt.Fprintf(os.Stderr, "Error: --all stops every running container and takes only --timeout\n")
+			fmt.Println("Usage: dockit stop --all [-t TIMEOUT]")
+			os.Exit(1)
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	containers, err := cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+	if len(containers) == 0 {
+		gray.Println("No running containers")
+		return
+	}
+
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+
+	fmt.Println()
+	targets := stopContainers(ctx, cli, fmt.Sprintf("STOPPING ALL %d CONTAINERS", len(ids)), ids, 0, timeout)
+	if printStopSummary(targets) {
+		os.Exit(1)
+	}
+}
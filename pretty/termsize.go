@@ -0,0 +1,20 @@
+package pretty
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tooSmallView replaces a full-screen TUI's view when the terminal is below
+// the size its layout needs, rather than letting lines wrap over each other.
+// It returns "" when the terminal is big enough.
+func tooSmallView(width, height, minWidth, minHeight int) string {
+	if width >= minWidth && height >= minHeight {
+		return ""
+	}
+
+	message := fmt.Sprintf("Terminal too small: %dx%d, needs %dx%d", width, height, minWidth, minHeight)
+	wrap := lipgloss.NewStyle().Width(max(width, 1))
+	return wrap.Render(errorStyle.Render(message)) + "\n" + wrap.Render(helpStyle.Render("Resize the window, or q to quit"))
+}
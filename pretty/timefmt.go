@@ -0,0 +1,182 @@
+package pretty
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatTime shows when something happened in the configured style: "3 hours
+// ago" (the default) or an absolute date and time
+func formatTime(t time.Time) string {
+	if strings.ToLower(config.Time.Style) == "absolute" {
+		return formatDateTime(t)
+	}
+	return humanizeTime(t)
+}
+
+// humanizeTime describes t relative to now, like "3 hours ago"
+func humanizeTime(t time.Time) string {
+	duration := time.Since(t)
+	if duration < 0 {
+		// Clock skew between the daemon and this machine
+		duration = 0
+	}
+
+	ago := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+
+	switch {
+	case duration < time.Minute:
+		return ago(int(duration.Seconds()), "second")
+	case duration < time.Hour:
+		return ago(int(duration.Minutes()), "minute")
+	case duration < 24*time.Hour:
+		return ago(int(duration.Hours()), "hour")
+	case duration < 7*24*time.Hour:
+		return ago(int(duration.Hours()/24), "day")
+	case duration < 30*24*time.Hour:
+		return ago(int(duration.Hours()/(24*7)), "week")
+	case duration < 365*24*time.Hour:
+		return ago(int(duration.Hours()/(24*30)), "month")
+	default:
+		return ago(int(duration.Hours()/(24*365)), "year")
+	}
+}
+
+// formatAgo shows a duration compactly, like "42s ago" or "3m12s ago", for
+// columns of times
+func formatAgo(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds ago", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm ago", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%02dh ago", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
+
+// formatDateTime shows t as a local date and time, like "2024-03-01 14:05:09"
+func formatDateTime(t time.Time) string {
+	return t.Local().Format(dateLayout() + " " + clockLayout())
+}
+
+// formatClock shows t as a local time of day, like "14:05:09" or "2:05:09 PM"
+func formatClock(t time.Time) string {
+	return t.Local().Format(clockLayout())
+}
+
+// formatStamp shows a log or event time: just the time of day for today,
+// with the date for anything older
+func formatStamp(t time.Time) string {
+	now := time.Now()
+	local := t.Local()
+	if local.Year() == now.Year() && local.YearDay() == now.YearDay() {
+		return formatClock(local)
+	}
+	return formatDateTime(local)
+}
+
+// clockLayout is the configured 12 or 24 hour clock, defaulting to the locale's
+func clockLayout() string {
+	clock := strings.ToLower(config.Time.Clock)
+	if clock == "" || clock == "auto" {
+		clock, _ = localeTimeDefaults()
+	}
+	if clock == "12h" {
+		return "3:04:05 PM"
+	}
+	return "15:04:05"
+}
+
+// dateLayout is the configured date order, defaulting to the locale's
+func dateLayout() string {
+	order := strings.ToLower(config.Time.Date)
+	if order == "" || order == "auto" {
+		_, order = localeTimeDefaults()
+	}
+	switch order {
+	case "dmy":
+		return "02/01/2006"
+	case "mdy":
+		return "01/02/2006"
+	default:
+		return "2006-01-02"
+	}
+}
+
+// localeTimeDefaults guesses the clock and date order from the locale
+// (LC_ALL, LC_TIME, then LANG), falling back to 24 hour ISO dates
+func localeTimeDefaults() (clock, date string) {
+	locale := ""
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			locale = value
+			break
+		}
+	}
+
+	// "en_US.UTF-8" -> language "en", region "US"
+	locale, _, _ = strings.Cut(locale, ".")
+	language, region, _ := strings.Cut(locale, "_")
+
+	switch {
+	case language == "" || language == "C" || language == "POSIX":
+		return "24h", "ymd"
+	case language == "en" && region == "US":
+		return "12h", "mdy"
+	case language == "en" && (region == "CA" || region == "PH"):
+		return "12h", "ymd"
+	case language == "en" && (region == "AU" || region == "NZ" || region == "IN"):
+		return "12h", "dmy"
+	case language == "zh" || language == "ja" || language == "ko" || language == "sv" || language == "lt" || language == "hu":
+		return "24h", "ymd"
+	default:
+		return "24h", "dmy"
+	}
+}
+
+// parseLogTime reads the timestamps JSON loggers write: RFC 3339 strings or
+// Unix epochs in seconds, milliseconds, or nanoseconds
+func parseLogTime(value any) (time.Time, bool) {
+	var epoch float64
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		epoch = n
+	case float64:
+		epoch = v
+	default:
+		return time.Time{}, false
+	}
+
+	switch {
+	case epoch > 1e17:
+		return time.Unix(0, int64(epoch)), true
+	case epoch > 1e11:
+		return time.UnixMilli(int64(epoch)), true
+	case epoch > 0:
+		seconds, fraction := math.Modf(epoch)
+		return time.Unix(int64(seconds), int64(fraction*1e9)), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,134 @@
+package pretty
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxAPICalls is how many recent Docker API calls are kept for the debug panel
+const maxAPICalls = 200
+
+// apiCall is one request dockit made to the Docker API
+type apiCall struct {
+	start    time.Time
+	method   string
+	path     string
+	duration time.Duration // until the response headers arrived
+	status   int
+	err      error
+}
+
+// apiTrace records recent API calls from every client dockit creates
+var apiTrace struct {
+	sync.Mutex
+	calls []apiCall
+}
+
+// apiVersionPrefix is stripped from paths, since it's the same on every call
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// tracingTransport records each request it passes to the next transport
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := apiCall{
+		start:  time.Now(),
+		method: req.Method,
+		path:   apiVersionPrefix.ReplaceAllString(req.URL.Path, ""),
+	}
+	resp, err := t.next.RoundTrip(req)
+	call.duration = time.Since(call.start)
+	call.err = err
+	if resp != nil {
+		call.status = resp.StatusCode
+	}
+
+	apiTrace.Lock()
+	apiTrace.calls = append(apiTrace.calls, call)
+	if len(apiTrace.calls) > maxAPICalls {
+		apiTrace.calls = apiTrace.calls[len(apiTrace.calls)-maxAPICalls:]
+	}
+	apiTrace.Unlock()
+
+	return resp, err
+}
+
+// recentAPICalls returns up to n of the most recent calls, newest last
+func recentAPICalls(n int) []apiCall {
+	apiTrace.Lock()
+	defer apiTrace.Unlock()
+	calls := apiTrace.calls
+	if len(calls) > n {
+		calls = calls[len(calls)-n:]
+	}
+	return append([]apiCall(nil), calls...)
+}
+
+// formatAPICall renders a call as time, method, path, duration, and status
+func formatAPICall(call apiCall) string {
+	status := fmt.Sprint(call.status)
+	if call.err != nil {
+		status = "error: " + call.err.Error()
+	}
+	return fmt.Sprintf("%s %-6s %-50s %8s  %s",
+		call.start.Format("15:04:05.000"), call.method, ellipsize(call.path, 50),
+		call.duration.Round(time.Millisecond), status)
+}
+
+// renderAPITrace is the TUI debug panel listing the calls that fit in height lines
+func renderAPITrace(height int) string {
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("DOCKER API CALLS"))
+	sb.WriteString("\n")
+
+	calls := recentAPICalls(max(1, height-4))
+	if len(calls) == 0 {
+		sb.WriteString(helpStyle.Render("No API calls yet"))
+		sb.WriteString("\n")
+	}
+	for _, call := range calls {
+		line := formatAPICall(call)
+		switch {
+		case call.err != nil || call.status >= 400:
+			line = errorStyle.Render(line)
+		case call.duration > time.Second:
+			line = searchBarStyle.Render(line)
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(helpStyle.Render("Durations are until the response headers arrive; streams stay open after | ctrl+t: close"))
+	return sb.String()
+}
+
+// tracing is set by the global --trace flag
+var tracing bool
+
+// EnableTrace makes PrintTrace report the API calls made by a command
+func EnableTrace() {
+	tracing = true
+}
+
+// PrintTrace writes the API calls made so far to stderr when --trace is set
+func PrintTrace() {
+	if !tracing {
+		return
+	}
+	calls := recentAPICalls(maxAPICalls)
+	var total time.Duration
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "DOCKER API CALLS")
+	for _, call := range calls {
+		fmt.Fprintln(os.Stderr, formatAPICall(call))
+		total += call.duration
+	}
+	fmt.Fprintf(os.Stderr, "Total: %d calls, %s\n", len(calls), total.Round(time.Millisecond))
+}
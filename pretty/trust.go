@@ -0,0 +1,49 @@
+package pretty
+
+import (
+	"os"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// trustedRegistries returns the configured allowlist of registries/namespaces,
+// from DOCKIT_TRUSTED_REGISTRIES or the config file. An empty list means no
+// allowlist is enforced.
+func trustedRegistries() []string {
+	candidates := config.TrustedRegistries
+	if value := os.Getenv("DOCKIT_TRUSTED_REGISTRIES"); value != "" {
+		candidates = strings.Split(value, ",")
+	}
+
+	var entries []string
+	for _, entry := range candidates {
+		entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// isTrustedImage reports whether an image reference comes from one of the
+// allowlisted registries or namespaces, e.g. "docker.io/library" or "ghcr.io/acme"
+func isTrustedImage(image string, allowlist []string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		// Bare image IDs have no origin we can check
+		return false
+	}
+	name := named.Name()
+
+	for _, entry := range allowlist {
+		if name == entry || strings.HasPrefix(name, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// containerTimes is when a container last started and stopped, which only
+// an inspect has: the list API gives the creation time, and a status
+// sentence rounded to "About an hour"
+type containerTimes struct {
+	started   time.Time // zero if it never started
+	finished  time.Time // zero if it never stopped
+	exitCode  int
+	restarts  int
+	oomKilled bool
+}
+
+// containerTimesCache inspects each container the first time its times are
+// asked for, so a list pays only for the rows it shows
+type containerTimesCache struct {
+	cli   *client.Client
+	times map[string]*containerTimes // nil when the inspect failed
+}
+
+func newContainerTimesCache(cli *client.Client) *containerTimesCache {
+	return &containerTimesCache{cli: cli, times: map[string]*containerTimes{}}
+}
+
+// get returns a container's times; ok is false when it couldn't be inspected
+func (c *containerTimesCache) get(ctx context.Context, id string) (containerTimes, bool) {
+	times, seen := c.times[id]
+	if !seen {
+		if info, err := c.cli.ContainerInspect(ctx, id); err == nil && info.State != nil {
+			times = &containerTimes{
+				started:   parseDaemonTime(info.State.StartedAt),
+				finished:  parseDaemonTime(info.State.FinishedAt),
+				exitCode:  info.State.ExitCode,
+				restarts:  info.RestartCount,
+				oomKilled: info.State.OOMKilled,
+			}
+		}
+		c.times[id] = times
+	}
+	if times == nil {
+		return containerTimes{}, false
+	}
+	return *times, true
+}
+
+// parseDaemonTime reads a time from an inspect, where never is
+// 0001-01-01T00:00:00Z; both that and an unreadable time come back zero
+func parseDaemonTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+	return t
+}
+
+// describeUptime says how long a running or paused container has been up
+// since it last started, not since it was created, and when that was
+func describeUptime(state string, times containerTimes) string {
+	since := formatClock(times.started)
+	if time.Since(times.started) >= 24*time.Hour {
+		since = formatDateTime(times.started)
+	}
+	prefix := "Up"
+	if state == "paused" {
+		prefix = "Paused, up"
+	}
+	text := fmt.Sprintf("%s %s (since %s)", prefix, formatUptime(time.Since(times.started)), since)
+	switch times.restarts {
+	case 0:
+	case 1:
+		text += ", restarted once"
+	default:
+		text += fmt.Sprintf(", restarted %d times", times.restarts)
+	}
+	return text
+}
+
+// formatUptime shows a duration to its two largest units, like "3h 12m"
+func formatUptime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours()/24), int(d.Hours())%24)
+	}
+}
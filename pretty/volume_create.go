@@ -0,0 +1,221 @@
+package pretty
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// volumeNamePattern is what Docker accepts for a volume name
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// volumeCreateOptions holds the docker volume create flags
+type volumeCreateOptions struct {
+	name   string
+	driver string
+	opts   map[string]string
+	labels map[string]string
+}
+
+// CreateVolume creates a volume like docker volume create, prompting for the
+// name, driver, options, and labels when run without arguments in a terminal
+func CreateVolume(args []string) {
+	options, err := parseVolumeCreateArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Println("Usage: dockit volume create [-d DRIVER] [-o KEY=VALUE]... [--label KEY=VALUE]... [NAME]")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	if len(args) == 0 && isTerminal(os.Stdin) {
+		err = createVolumeInteractive(ctx, cli, options)
+	} else {
+		err = createVolume(ctx, cli, options)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func parseVolumeCreateArgs(args []string) (volumeCreateOptions, error) {
+	options := volumeCreateOptions{driver: "local", opts: map[string]string{}, labels: map[string]string{}}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// Support both "--flag value" and "--flag=value"
+		flag, value, hasValue := strings.Cut(arg, "=")
+		if !strings.HasPrefix(arg, "-") {
+			options.name = arg
+			continue
+		}
+		nextValue := func() string {
+			if hasValue {
+				return value
+			}
+			if i+1 < len(args) {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+
+		switch flag {
+		case "-d", "--driver":
+			options.driver = nextValue()
+		case "-o", "--opt":
+			k, v, ok := strings.Cut(nextValue(), "=")
+			if !ok || k == "" {
+				return options, fmt.Errorf("driver options are KEY=VALUE")
+			}
+			options.opts[k] = v
+		case "--label":
+			k, v, _ := strings.Cut(nextValue(), "=")
+			if k == "" {
+				return options, fmt.Errorf("labels are KEY=VALUE")
+			}
+			options.labels[k] = v
+		default:
+			return options, fmt.Errorf("unknown option %q", arg)
+		}
+	}
+	return options, nil
+}
+
+// createVolumeInteractive asks for the volume's settings, then creates it
+func createVolumeInteractive(ctx context.Context, cli *client.Client, options volumeCreateOptions) error {
+	reader := bufio.NewReader(os.Stdin)
+	if options.opts == nil {
+		options.opts = map[string]string{}
+	}
+	if options.labels == nil {
+		options.labels = map[string]string{}
+	}
+
+	fmt.Println()
+	cyan.Println("NEW VOLUME")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	for {
+		options.name = prompt(reader, "Name (empty for a generated one): ")
+		if err := validateVolumeName(ctx, cli, options.name); err != nil {
+			red.Printf("%s %v\n", glyphs.failed, err)
+			continue
+		}
+		break
+	}
+
+	if driver := prompt(reader, fmt.Sprintf("Driver [%s]: ", options.driver)); driver != "" {
+		options.driver = driver
+	}
+	if options.driver == "local" {
+		gray.Printf("  %s Leave options empty for a plain volume; for NFS use type=nfs, o=addr=HOST,rw, device=:/export\n", glyphs.detail)
+	}
+	promptPairs(reader, "Driver option", options.opts)
+	promptPairs(reader, "Label", options.labels)
+
+	fmt.Println()
+	return createVolume(ctx, cli, options)
+}
+
+// promptPairs reads KEY=VALUE entries into pairs until an empty answer
+func promptPairs(reader *bufio.Reader, label string, pairs map[string]string) {
+	for {
+		answer := prompt(reader, label+" KEY=VALUE (empty to finish): ")
+		if answer == "" {
+			return
+		}
+		key, value, ok := strings.Cut(answer, "=")
+		if !ok || key == "" {
+			red.Printf("%s Expected KEY=VALUE\n", glyphs.failed)
+			continue
+		}
+		pairs[key] = value
+	}
+}
+
+// validateVolumeName checks the name's syntax and that it is free; an empty
+// name lets Docker generate one
+func validateVolumeName(ctx context.Context, cli *client.Client, name string) error {
+	if name == "" {
+		return nil
+	}
+	if !volumeNamePattern.MatchString(name) {
+		return fmt.Errorf("%q is not a valid volume name (letters, digits, _ . -, starting with a letter or digit)", name)
+	}
+	if _, err := cli.VolumeInspect(ctx, name); err == nil {
+		return fmt.Errorf("volume %s already exists", name)
+	} else if !client.IsErrNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// createVolume creates the volume and shows the volume list with it marked
+func createVolume(ctx context.Context, cli *client.Client, options volumeCreateOptions) error {
+	if err := validateVolumeName(ctx, cli, options.name); err != nil {
+		return err
+	}
+
+	created, err := cli.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       options.name,
+		Driver:     options.driver,
+		DriverOpts: options.opts,
+		Labels:     options.labels,
+	})
+	if err != nil {
+		return fmt.Errorf("creating volume: %v", err)
+	}
+
+	// Scripts only need the name, like docker volume create prints
+	if !isTerminal(os.Stdout) {
+		fmt.Println(created.Name)
+		return nil
+	}
+
+	green.Print(glyphs.ok + " ")
+	fmt.Printf("Created volume %s ", created.Name)
+	gray.Printf("(%s)\n", created.Driver)
+	for _, key := range sortedKeys(created.Options) {
+		gray.Printf("  %s %s=%s\n", glyphs.detail, key, created.Options[key])
+	}
+
+	response, err := cli.VolumeList(ctx, volume.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil
+	}
+	volumes := response.Volumes
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	renderVolumes(volumes, volumesInUse(containers), created.Name)
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,171 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// PrintVolumeDetails displays a single volume with its size and attached containers
+func PrintVolumeDetails(args []string) {
+	var volumeName string
+	fullIDs := config.Defaults.FullIDs
+	for _, arg := range args {
+		if arg == "--no-trunc" {
+			fullIDs = true
+		} else if !strings.HasPrefix(arg, "-") && volumeName == "" {
+			volumeName = arg
+		}
+	}
+
+	if volumeName == "" {
+		fmt.Fprintf(os.Stderr, "Error: volume name required\n")
+		fmt.Println("Usage: dockit volume inspect VOLUME")
+		os.Exit(1)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	vol, err := cli.VolumeInspect(ctx, volumeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error inspecting volume: %v\n", err)
+		os.Exit(1)
+	}
+	if err := printVolumeDetails(ctx, cli, vol, fullIDs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printVolumeDetails prints a volume's settings, size, and the containers
+// that mount it
+func printVolumeDetails(ctx context.Context, cli *client.Client, vol volume.Volume, fullIDs bool) error {
+	// Containers referencing the volume, running or not
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("volume", vol.Name)),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Size is only reported by the system/df endpoint
+	size := "unknown"
+	usage, err := cli.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err == nil {
+		for _, v := range usage.Volumes {
+			if v.Name == vol.Name && v.UsageData != nil && v.UsageData.Size >= 0 {
+				size = formatSize(v.UsageData.Size)
+				break
+			}
+		}
+	}
+
+	// Print header
+	fmt.Println()
+	cyan.Printf("VOLUME: %s\n", vol.Name)
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	printDetail("Driver", vol.Driver)
+	printDetail("Scope", vol.Scope)
+	printDetail("Mountpoint", vol.Mountpoint)
+	if created, err := time.Parse(time.RFC3339, vol.CreatedAt); err == nil {
+		printDetail("Created", formatTime(created))
+	}
+	printDetail("Size", size)
+
+	if len(vol.Labels) > 0 {
+		printDetail("Labels", formatLabels(vol.Labels))
+	}
+	if len(vol.Options) > 0 {
+		printDetail("Options", formatLabels(vol.Options))
+	}
+
+	// Attached containers
+	fmt.Println()
+	cyan.Println("ATTACHED CONTAINERS")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	if len(containers) == 0 {
+		gray.Println("No containers use this volume")
+		return nil
+	}
+
+	for _, c := range containers {
+		statusColor, indicator := stateStyle(c.State)
+
+		containerID := formatID(c.ID, fullIDs)
+
+		name := strings.TrimPrefix(c.Names[0], "/")
+		nameWidth := 30
+		if len(name) > nameWidth {
+			name = name[:nameWidth-3] + "..."
+		}
+		namePadded := name + strings.Repeat(" ", nameWidth-len(name))
+
+		stateWidth := 10
+		statePadded := c.State + strings.Repeat(" ", stateWidth-len(c.State))
+
+		// Where the volume is mounted in this container
+		mountInfo := ""
+		for _, m := range c.Mounts {
+			if m.Name == vol.Name {
+				mode := "rw"
+				if !m.RW {
+					mode = "ro"
+				}
+				mountInfo = fmt.Sprintf("%s (%s)", m.Destination, mode)
+				break
+			}
+		}
+
+		statusColor.Print(indicator)
+		fmt.Print(" ")
+		gray.Print(containerID)
+		gray.Print(" " + glyphs.divider + " ")
+		blue.Print(namePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		statusColor.Print(statePadded)
+		gray.Print(glyphs.divider + " ")
+		fmt.Println(mountInfo)
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %d containers\n", len(containers))
+	return nil
+}
+
+func printDetail(label, value string) {
+	gray.Printf("  %-12s ", label+":")
+	fmt.Println(value)
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,183 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// PrintVolumes displays volumes in a pretty format
+func PrintVolumes(args []string) {
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	format, args, err := parseOutputFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Filters are handed to the Docker API as-is, like docker volume ls
+	filterArgs := filters.NewArgs()
+	interactive := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var filter string
+		switch {
+		case arg == "-i" || arg == "--interactive":
+			interactive = true
+			continue
+		case (arg == "-f" || arg == "--filter") && i+1 < len(args):
+			i++
+			filter = args[i]
+		case strings.HasPrefix(arg, "--filter="):
+			filter = strings.TrimPrefix(arg, "--filter=")
+		default:
+			continue
+		}
+		if err := addFilter(filterArgs, filter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	response, err := cli.VolumeList(ctx, volume.ListOptions{Filters: filterArgs})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing volumes: %v\n", err)
+		os.Exit(1)
+	}
+	volumes := response.Volumes
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing containers: %v\n", err)
+		os.Exit(1)
+	}
+	used := volumesInUse(containers)
+
+	if interactive {
+		reload := func() ([]bulkItem, error) {
+			response, err := cli.VolumeList(ctx, volume.ListOptions{Filters: filterArgs})
+			if err != nil {
+				return nil, err
+			}
+			volumes := response.Volumes
+			sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+			containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+			if err != nil {
+				return nil, err
+			}
+			return volumeBulkItems(volumes, volumesInUse(containers)), nil
+		}
+		runBulk(ctx, cli, "volumes", volumeBulkItems(volumes, used), volumeActions, reload)
+		return
+	}
+
+	if format.enabled() {
+		records := []volumeRecord{}
+		for _, v := range volumes {
+			records = append(records, newVolumeRecord(v, used))
+		}
+		printRecords(format, records)
+		return
+	}
+
+	if len(volumes) == 0 {
+		gray.Println("No volumes found")
+		gray.Println("(create one with 'dockit volume create NAME')")
+		return
+	}
+
+	renderVolumes(volumes, used, "")
+}
+
+// renderVolumes prints the pretty volume list, marking the highlight volume
+// as new
+func renderVolumes(volumes []*volume.Volume, used map[string]bool, highlight string) {
+	// Print header
+	fmt.Println()
+	cyan.Println("VOLUMES")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	inUse := 0
+	for _, v := range volumes {
+		// In-use indicator
+		statusColor, indicator := gray, glyphs.stopped
+		if used[v.Name] {
+			statusColor, indicator = green, glyphs.running
+			inUse++
+		}
+
+		// Volume name
+		name := v.Name
+		nameWidth := 40
+		if len(name) > nameWidth {
+			name = name[:nameWidth-3] + "..."
+		}
+		namePadded := name + strings.Repeat(" ", nameWidth-len(name))
+
+		// Driver and scope
+		driverWidth := 10
+		driverPadded := v.Driver + strings.Repeat(" ", max(0, driverWidth-len(v.Driver)))
+		scopeWidth := 6
+		scopePadded := v.Scope + strings.Repeat(" ", max(0, scopeWidth-len(v.Scope)))
+
+		// Created time
+		created := ""
+		if t, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil {
+			created = formatTime(t)
+		}
+
+		// Print main line
+		statusColor.Print(indicator)
+		fmt.Print(" ")
+		if v.Name == highlight {
+			green.Print(namePadded)
+		} else {
+			blue.Print(namePadded)
+		}
+		gray.Print(" " + glyphs.divider + " ")
+		fmt.Print(driverPadded)
+		gray.Print(" " + glyphs.divider + " ")
+		fmt.Print(scopePadded)
+		gray.Print(" " + glyphs.divider + " ")
+		gray.Println(created)
+
+		// Mountpoint
+		if v.Mountpoint != "" {
+			gray.Printf("  %s Mountpoint: %s\n", glyphs.detail, v.Mountpoint)
+		}
+
+		if v.Name == highlight {
+			green.Printf("  %s New\n", glyphs.detail)
+		}
+
+		// Kept by prune and other cleanup flows
+		if isProtected(v.Name, v.Labels) {
+			gray.Printf("  %s Protected\n", glyphs.detail)
+		}
+
+		fmt.Println()
+	}
+
+	// Summary
+	fmt.Printf("Total: %d volumes", len(volumes))
+	if inUse > 0 {
+		green.Printf(" (%d in use)", inUse)
+	}
+	fmt.Println()
+}
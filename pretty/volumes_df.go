@@ -0,0 +1,295 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// volumeUsage is one volume with its size and the containers mounting it
+type volumeUsage struct {
+	name       string
+	size       int64 // -1 when the daemon doesn't know
+	containers []string
+	stopped    []string // IDs of the stopped containers mounting it
+	protected  bool
+}
+
+// volumeClass groups volumes by what still needs them. Classes with a key
+// can be cleaned up with it.
+type volumeClass struct {
+	key     string
+	name    string
+	note    string
+	volumes []volumeUsage
+}
+
+func (c volumeClass) size() int64 {
+	var total int64
+	for _, v := range c.volumes {
+		if v.size > 0 {
+			total += v.size
+		}
+	}
+	return total
+}
+
+// reclaimable is the size cleanup would free, leaving protected volumes
+func (c volumeClass) reclaimable() int64 {
+	var total int64
+	for _, v := range c.volumes {
+		if v.size > 0 && !v.protected {
+			total += v.size
+		}
+	}
+	return total
+}
+
+func (c volumeClass) protected() int {
+	count := 0
+	for _, v := range c.volumes {
+		if v.protected {
+			count++
+		}
+	}
+	return count
+}
+
+// PrintVolumeUsage classifies volumes as in use, used only by stopped
+// containers, or orphaned, with their sizes, and cleans up a chosen class
+func PrintVolumeUsage(args []string) {
+	interactive, clean := false, ""
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-i" || arg == "--interactive":
+			interactive = true
+		case arg == "--clean" && i+1 < len(args):
+			i++
+			clean = args[i]
+		case strings.HasPrefix(arg, "--clean="):
+			clean = strings.TrimPrefix(arg, "--clean=")
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option %s\n", arg)
+			fmt.Println("Usage: dockit volumes df [-i] [--clean orphaned|stopped]")
+			os.Exit(1)
+		}
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Docker client: %v\n", err)
+		os.Exit(1)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+
+	classes, err := collectVolumeClasses(ctx, cli)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing volume usage: %v\n", err)
+		os.Exit(1)
+	}
+
+	if interactive {
+		chosen, err := LaunchVolumeUsageTUI(classes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if chosen < 0 {
+			return
+		}
+		cleanVolumeClass(ctx, cli, classes[chosen])
+		return
+	}
+
+	if clean != "" {
+		for _, class := range classes {
+			if class.key != "" && strings.HasPrefix(clean, class.key) {
+				cleanVolumeClass(ctx, cli, class)
+				return
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Error: unknown class %q (expected orphaned or stopped)\n", clean)
+		os.Exit(1)
+	}
+
+	renderVolumeClasses(classes)
+}
+
+// collectVolumeClasses sorts every volume into the in-use, stopped-only, and
+// orphaned classes, using system/df for the sizes
+func collectVolumeClasses(ctx context.Context, cli *client.Client) ([]volumeClass, error) {
+	usage, err := cli.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return nil, err
+	}
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	type mounter struct {
+		names   []string
+		running bool
+		stopped []string
+	}
+	mounters := map[string]*mounter{}
+	for i := range containers {
+		c := &containers[i]
+		name := strings.TrimPrefix(c.Names[0], "/")
+		for _, m := range c.Mounts {
+			if m.Name == "" {
+				continue
+			}
+			entry := mounters[m.Name]
+			if entry == nil {
+				entry = &mounter{}
+				mounters[m.Name] = entry
+			}
+			entry.names = append(entry.names, name)
+			if containerStateIsActive(c) {
+				entry.running = true
+			} else if isProtected(c.Names[0], c.Labels) {
+				// A protected container keeps its volumes too
+				entry.running = true
+			} else {
+				entry.stopped = append(entry.stopped, c.ID)
+			}
+		}
+	}
+
+	classes := []volumeClass{
+		{name: "In use", note: "mounted by a running container"},
+		{key: "s", name: "Stopped only", note: "cleanup removes the stopped containers too"},
+		{key: "o", name: "Orphaned", note: "no container mounts them"},
+	}
+	for _, v := range usage.Volumes {
+		entry := volumeUsage{name: v.Name, size: -1, protected: isProtected(v.Name, v.Labels)}
+		if v.UsageData != nil {
+			entry.size = v.UsageData.Size
+		}
+		class := 2
+		if m := mounters[v.Name]; m != nil {
+			entry.containers = m.names
+			entry.stopped = m.stopped
+			class = 1
+			if m.running {
+				class = 0
+			}
+		}
+		classes[class].volumes = append(classes[class].volumes, entry)
+	}
+
+	// Biggest first, since that is what cleanup is after
+	for _, class := range classes {
+		sort.Slice(class.volumes, func(i, j int) bool {
+			if class.volumes[i].size != class.volumes[j].size {
+				return class.volumes[i].size > class.volumes[j].size
+			}
+			return class.volumes[i].name < class.volumes[j].name
+		})
+	}
+	return classes, nil
+}
+
+// renderVolumeClasses prints each class with its volumes and sizes
+func renderVolumeClasses(classes []volumeClass) {
+	// Print header
+	fmt.Println()
+	cyan.Println("VOLUME USAGE")
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	colors := []func(format string, a ...interface{}) string{green.Sprintf, yellow.Sprintf, red.Sprintf}
+	var total int64
+	for i, class := range classes {
+		fmt.Print(colors[i]("%-14s", class.name))
+		fmt.Printf(" %3d volumes  %10s", len(class.volumes), formatSize(class.size()))
+		gray.Printf("  (%s)\n", class.note)
+		total += class.size()
+
+		for _, v := range class.volumes {
+			size := "unknown"
+			if v.size >= 0 {
+				size = formatSize(v.size)
+			}
+			fmt.Printf("  %s %-40s %10s", glyphs.detail, v.name, size)
+			if len(v.containers) > 0 {
+				gray.Printf("  %s", strings.Join(v.containers, ", "))
+			}
+			if v.protected {
+				gray.Print("  protected")
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+
+	fmt.Print("Total: ")
+	fmt.Println(formatSize(total))
+	gray.Println("(clean up with 'dockit volumes df --clean orphaned|stopped' or pick a class with -i)")
+}
+
+// cleanVolumeClass removes every unprotected volume in class, first removing
+// the stopped containers that still mount it
+func cleanVolumeClass(ctx context.Context, cli *client.Client, class volumeClass) {
+	// Print header
+	fmt.Println()
+	cyan.Printf("CLEAN UP: %s\n", strings.ToUpper(class.name))
+	cyan.Println(strings.Repeat(glyphs.rule, 90))
+
+	removedContainers := map[string]bool{}
+	removed, protected := 0, 0
+	var reclaimed int64
+	for _, v := range class.volumes {
+		if v.protected {
+			protected++
+			continue
+		}
+
+		var err error
+		for _, id := range v.stopped {
+			if removedContainers[id] {
+				continue
+			}
+			if err = cli.ContainerRemove(ctx, id, container.RemoveOptions{}); err != nil {
+				break
+			}
+			removedContainers[id] = true
+		}
+		if err == nil {
+			err = cli.VolumeRemove(ctx, v.name, false)
+		}
+		if err != nil {
+			red.Print(glyphs.failed + " ")
+			fmt.Printf("%-40s ", v.name)
+			red.Println(err)
+			continue
+		}
+
+		removed++
+		if v.size > 0 {
+			reclaimed += v.size
+		}
+		green.Print(glyphs.ok + " ")
+		fmt.Println(v.name)
+	}
+
+	fmt.Println()
+	fmt.Printf("Removed %d volumes", removed)
+	if len(removedContainers) > 0 {
+		fmt.Printf(" and %d stopped containers", len(removedContainers))
+	}
+	if protected > 0 {
+		yellow.Printf(" (%d protected)", protected)
+	}
+	fmt.Println()
+	fmt.Print("Total reclaimed: ")
+	green.Println(formatSize(reclaimed))
+}
@@ -0,0 +1,128 @@
+package pretty
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type volumeUsageModel struct {
+	classes []volumeClass
+	cursor  int
+	pending int // class awaiting y to confirm its cleanup, or -1
+	chosen  int
+}
+
+func (m volumeUsageModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m volumeUsageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.pending >= 0 {
+		if key.String() == "y" {
+			m.chosen = m.pending
+			return m, tea.Quit
+		}
+		m.pending = -1
+		return m, nil
+	}
+
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.classes)-1 {
+			m.cursor++
+		}
+	default:
+		for i, class := range m.classes {
+			if class.key == key.String() && len(class.volumes) > class.protected() {
+				// Show what is about to go while asking
+				m.pending, m.cursor = i, i
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m volumeUsageModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render("VOLUME USAGE"))
+	sb.WriteString("\n")
+
+	for i, class := range m.classes {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = cursorStyle.Render(glyphs.cursor + " ")
+		}
+		key := "   "
+		if class.key != "" {
+			key = "[" + class.key + "]"
+		}
+		line := fmt.Sprintf("%s %-14s %4d volumes  %10s", key, class.name, len(class.volumes), formatSize(class.size()))
+		line += helpStyle.Render("  (" + class.note + ")")
+		if protected := class.protected(); protected > 0 {
+			line += helpStyle.Render(fmt.Sprintf("  %d protected", protected))
+		}
+		sb.WriteString(cursor + line + "\n")
+	}
+
+	// The volumes of the class under the cursor
+	sb.WriteString("\n")
+	class := m.classes[m.cursor]
+	if len(class.volumes) == 0 {
+		sb.WriteString(helpStyle.Render("  no volumes") + "\n")
+	}
+	for _, v := range class.volumes {
+		size := "unknown"
+		if v.size >= 0 {
+			size = formatSize(v.size)
+		}
+		line := fmt.Sprintf("  %s %-40s %10s", glyphs.detail, v.name, size)
+		if len(v.containers) > 0 {
+			line += helpStyle.Render("  " + strings.Join(v.containers, ", "))
+		}
+		if v.protected {
+			line += helpStyle.Render("  protected")
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	sb.WriteString("\n")
+	if m.pending >= 0 {
+		pending := m.classes[m.pending]
+		count := len(pending.volumes) - pending.protected()
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("Remove %d %s volumes (%s)? y to confirm",
+			count, strings.ToLower(pending.name), formatSize(pending.reclaimable()))))
+	} else {
+		sb.WriteString(helpStyle.Render(glyphs.arrows + ": browse | s: clean stopped only | o: clean orphaned | q: quit"))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// LaunchVolumeUsageTUI shows the volume classes and returns the one the user
+// chose to clean up, or -1
+func LaunchVolumeUsageTUI(classes []volumeClass) (int, error) {
+	model := volumeUsageModel{classes: classes, pending: -1, chosen: -1}
+
+	p := newProgram(model)
+	final, err := p.Run()
+	if err != nil {
+		return -1, fmt.Errorf("error running TUI: %v", err)
+	}
+
+	return final.(volumeUsageModel).chosen, nil
+}
@@ -0,0 +1,134 @@
+package pretty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/docker/docker/client"
+)
+
+// watchInspectInterval is how often watch inspects the container for its
+// state, health, and restarts, which no stream reports
+const watchInspectInterval = 2 * time.Second
+
+// watchStatus is what watch shows above the graphs from the last inspect
+type watchStatus struct {
+	state   string
+	times   containerTimes
+	health  string // empty without a healthcheck
+	failing int    // failed probes in a row
+	probe   string // the last probe's output
+}
+
+// PrintWatch opens a read-only view of one container for deploys and
+// debugging: its uptime, restarts, and health, CPU and memory graphs, and
+// the log tail, all kept current
+func PrintWatch(args []string) {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: container name or ID required\n")
+		fmt.Println("Usage: dockit watch CONTAINER")
+		os.Exit(1)
+	}
+
+	if err := launchQuick(positional[0], true); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type watchTickMsg struct{}
+
+type watchInspectedMsg struct {
+	status watchStatus
+	err    error
+}
+
+// inspectWatched reads the container's state, times, and health
+func inspectWatched(ctx context.Context, cli *client.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := cli.ContainerInspect(ctx, id)
+		if err != nil || info.State == nil {
+			return watchInspectedMsg{err: err}
+		}
+		status := watchStatus{
+			state: info.State.Status,
+			times: containerTimes{
+				started:   parseDaemonTime(info.State.StartedAt),
+				finished:  parseDaemonTime(info.State.FinishedAt),
+				exitCode:  info.State.ExitCode,
+				restarts:  info.RestartCount,
+				oomKilled: info.State.OOMKilled,
+			},
+		}
+		if health := info.State.Health; health != nil {
+			status.health = health.Status
+			status.failing = health.FailingStreak
+			if n := len(health.Log); n > 0 {
+				status.probe = strings.Join(strings.Fields(health.Log[n-1].Output), " ")
+			}
+		}
+		return watchInspectedMsg{status: status}
+	}
+}
+
+func scheduleWatchInspect() tea.Cmd {
+	return tea.Tick(watchInspectInterval, func(time.Time) tea.Msg { return watchTickMsg{} })
+}
+
+// renderWatchStatus is the line under the title: uptime or exit, restarts,
+// and health with the last failing probe's output
+func renderWatchStatus(status *watchStatus, width int) string {
+	if status == nil {
+		return helpStyle.Render("  Inspecting...")
+	}
+
+	var text string
+	switch status.state {
+	case "running", "paused":
+		text = describeUptime(status.state, status.times)
+	default:
+		text = "Not running"
+		if !status.times.finished.IsZero() {
+			text = fmt.Sprintf("Exited (%d) %s", status.times.exitCode, formatAgo(time.Since(status.times.finished)))
+			if status.times.oomKilled {
+				text += ", OOM killed"
+			}
+		}
+		if status.times.restarts > 0 {
+			text += fmt.Sprintf(", restarted %d times", status.times.restarts)
+		}
+	}
+	line := "  " + text
+
+	var healthStyle lipgloss.Style
+	health := "no healthcheck"
+	switch status.health {
+	case "":
+		healthStyle = helpStyle
+	case "healthy":
+		healthStyle = selectedStyle
+		health = "healthy"
+	case "unhealthy":
+		healthStyle = errorStyle
+		health = fmt.Sprintf("unhealthy, %d failures in a row", status.failing)
+		if status.probe != "" {
+			health += ": " + status.probe
+		}
+	default:
+		healthStyle = detachedStyle
+		health = status.health
+	}
+	health = ellipsize(health, max(width-lipgloss.Width(line)-4, 10))
+	return line + "  " + healthStyle.Render(health)
+}